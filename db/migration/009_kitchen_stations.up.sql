@@ -0,0 +1,41 @@
+-- KITCHEN STATIONS (KDS)
+CREATE TABLE kitchen_stations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL, -- e.g., "Grill", "Drinks", "Dessert"
+    code VARCHAR(50) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE, -- Catch-all for unrouted items
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (store_id, code)
+);
+
+-- Product or category -> station mapping. Product routes win over category routes.
+CREATE TABLE kitchen_station_routes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    station_id UUID NOT NULL REFERENCES kitchen_stations(id) ON DELETE CASCADE,
+    product_id UUID REFERENCES products(id) ON DELETE CASCADE,
+    category_id UUID REFERENCES categories(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (product_id IS NOT NULL OR category_id IS NOT NULL)
+);
+
+-- One ticket per (order, station)
+CREATE TABLE kitchen_tickets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    station_id UUID NOT NULL REFERENCES kitchen_stations(id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL DEFAULT 'NEW', -- NEW, COOKING, READY
+    bumped_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (order_id, station_id)
+);
+
+-- Item-level KDS status
+ALTER TABLE order_items
+ADD COLUMN ticket_id UUID REFERENCES kitchen_tickets(id) ON DELETE SET NULL,
+ADD COLUMN status VARCHAR(50) NOT NULL DEFAULT 'NEW';
+
+CREATE INDEX idx_kitchen_station_routes_station ON kitchen_station_routes(station_id);
+CREATE INDEX idx_kitchen_tickets_station_status ON kitchen_tickets(station_id, status);
+CREATE INDEX idx_order_items_ticket ON order_items(ticket_id);
@@ -0,0 +1,26 @@
+-- Status change timestamps (used for SLA tracking & prep time reports)
+ALTER TABLE orders
+ADD COLUMN accepted_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN cooking_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN ready_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN completed_at TIMESTAMP WITH TIME ZONE, -- DONE or VOIDED
+ADD COLUMN status_changed_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN overdue_notified_status VARCHAR(50); -- Status already escalated, avoids duplicate ORDER_OVERDUE
+
+-- Existing orders keep their last change, not the time of the migration
+UPDATE orders SET status_changed_at = COALESCE(updated_at, created_at, NOW());
+ALTER TABLE orders ALTER COLUMN status_changed_at SET DEFAULT NOW();
+
+ALTER TABLE kitchen_tickets
+ADD COLUMN started_at TIMESTAMP WITH TIME ZONE;
+
+-- Per-store SLA targets per order stage
+CREATE TABLE store_sla_targets (
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL, -- NEW, ACCEPTED, COOKING, READY
+    target_seconds INT NOT NULL CHECK (target_seconds > 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (store_id, status)
+);
+
+CREATE INDEX idx_orders_status_changed_at ON orders(status, status_changed_at);
@@ -0,0 +1,27 @@
+-- Structured order status history (replaces free-form audit JSON for timelines)
+CREATE TABLE order_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status VARCHAR(50),
+    to_status VARCHAR(50) NOT NULL,
+    actor_id UUID REFERENCES profiles(id) ON DELETE SET NULL,
+    actor_role VARCHAR(50),
+    source VARCHAR(20) NOT NULL, -- REST, WEBSOCKET, SYSTEM
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Item level changes (KDS status, etc.)
+CREATE TABLE order_item_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL, -- STATUS_CHANGED
+    from_status VARCHAR(50),
+    to_status VARCHAR(50),
+    actor_id UUID REFERENCES profiles(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_order_status_history_order ON order_status_history(order_id, created_at);
+CREATE INDEX idx_order_item_events_order ON order_item_events(order_id, created_at);
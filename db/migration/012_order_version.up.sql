@@ -0,0 +1,3 @@
+-- Optimistic concurrency for orders: every mutation bumps the version and
+-- only applies when the caller saw the latest version and status.
+ALTER TABLE orders ADD COLUMN version INT NOT NULL DEFAULT 1;
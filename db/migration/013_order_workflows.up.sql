@@ -0,0 +1,14 @@
+-- Per-store order workflow graphs. A store (and order type) without rows
+-- uses the built-in default graph.
+CREATE TABLE order_workflow_transitions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    order_type VARCHAR(20) NOT NULL DEFAULT '', -- '' = all order types
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    allowed_roles TEXT[] NOT NULL DEFAULT '{}', -- empty = any role allowed on the route
+    side_effects TEXT[] NOT NULL DEFAULT '{}', -- NOTIFY, DEDUCT_STOCK
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(store_id, order_type, from_status, to_status),
+    CHECK (from_status <> to_status)
+);
@@ -0,0 +1,27 @@
+-- Explicit order types with type specific data
+ALTER TABLE orders
+    ADD COLUMN order_type VARCHAR(20) NOT NULL DEFAULT 'TAKEAWAY',
+    ADD COLUMN table_id UUID REFERENCES tables(id) ON DELETE SET NULL, -- DINE_IN
+    ADD COLUMN customer_name VARCHAR(255), -- PICKUP, DELIVERY
+    ADD COLUMN customer_phone VARCHAR(50), -- PICKUP, DELIVERY
+    ADD COLUMN delivery_address TEXT, -- DELIVERY
+    ADD COLUMN courier VARCHAR(100), -- DELIVERY
+    ADD COLUMN service_charge_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+UPDATE orders SET order_type = 'DINE_IN' WHERE table_session_id IS NOT NULL;
+
+ALTER TABLE orders ADD CONSTRAINT orders_order_type_check
+    CHECK (order_type IN ('DINE_IN', 'TAKEAWAY', 'DELIVERY', 'PICKUP'));
+
+CREATE INDEX idx_orders_store_type ON orders(store_id, order_type, created_at);
+
+-- Tax and service charge per order type, in percent of the item total.
+-- Order types without a row are not charged.
+CREATE TABLE store_order_type_charges (
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    order_type VARCHAR(20) NOT NULL,
+    tax_rate DECIMAL(5, 2) NOT NULL DEFAULT 0 CHECK (tax_rate >= 0),
+    service_charge_rate DECIMAL(5, 2) NOT NULL DEFAULT 0 CHECK (service_charge_rate >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (store_id, order_type)
+);
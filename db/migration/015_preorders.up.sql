@@ -0,0 +1,28 @@
+-- Scheduled pre-orders: kept out of the KDS until lead time before scheduled_for
+ALTER TABLE orders
+    ADD COLUMN scheduled_for TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN kitchen_released_at TIMESTAMP WITH TIME ZONE; -- NULL = not visible to the kitchen yet
+
+UPDATE orders SET kitchen_released_at = created_at;
+
+CREATE INDEX idx_orders_scheduled ON orders(store_id, scheduled_for) WHERE scheduled_for IS NOT NULL;
+CREATE INDEX idx_orders_unreleased ON orders(scheduled_for) WHERE kitchen_released_at IS NULL;
+
+CREATE TABLE store_preorder_settings (
+    store_id UUID PRIMARY KEY REFERENCES stores(id) ON DELETE CASCADE,
+    lead_time_minutes INT NOT NULL DEFAULT 15 CHECK (lead_time_minutes >= 0),
+    slot_minutes INT NOT NULL DEFAULT 15 CHECK (slot_minutes > 0),
+    slot_capacity INT NOT NULL DEFAULT 0 CHECK (slot_capacity >= 0), -- 0 = unlimited
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Jakarta',
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Weekdays without a row are closed. Stores without any row are always open.
+CREATE TABLE store_opening_hours (
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6), -- 0 = Sunday
+    opens_at TIME NOT NULL,
+    closes_at TIME NOT NULL,
+    PRIMARY KEY (store_id, weekday),
+    CHECK (opens_at < closes_at)
+);
@@ -0,0 +1,17 @@
+-- Held (parked) orders: a half-entered order a cashier can resume later.
+-- Drafts never reach the kitchen and do not reserve stock; converting one runs
+-- the normal CreateOrder validation on the stored payload.
+CREATE TABLE order_drafts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    cashier_id UUID NOT NULL REFERENCES profiles(id),
+    terminal_id VARCHAR(64) NOT NULL DEFAULT '',
+    label VARCHAR(100) NOT NULL DEFAULT '',
+    payload JSONB NOT NULL, -- CreateOrderRequest as entered so far
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_order_drafts_store ON order_drafts(store_id, cashier_id);
+CREATE INDEX idx_order_drafts_expires ON order_drafts(expires_at);
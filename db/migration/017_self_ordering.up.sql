@@ -0,0 +1,7 @@
+-- Customer self-ordering through the table session (QR) token
+CREATE TABLE store_self_order_settings (
+    store_id UUID PRIMARY KEY REFERENCES stores(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    auto_accept BOOLEAN NOT NULL DEFAULT FALSE, -- FALSE = staff confirms NEW orders
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
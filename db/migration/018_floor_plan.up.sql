@@ -0,0 +1,20 @@
+-- Floor plan: areas (e.g. "Indoor", "Terrace") and table positions on it
+CREATE TABLE floor_areas (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    sort_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (store_id, name)
+);
+
+ALTER TABLE tables
+    ADD COLUMN area_id UUID REFERENCES floor_areas(id) ON DELETE SET NULL,
+    ADD COLUMN pos_x INT NOT NULL DEFAULT 0, -- Layout grid position within the area
+    ADD COLUMN pos_y INT NOT NULL DEFAULT 0,
+    ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW();
+
+-- qr_code is the opaque per-table code encoded in the printed QR URL
+UPDATE tables SET qr_code = substr(md5(random()::text || id::text), 1, 16) WHERE qr_code IS NULL;
+
+CREATE INDEX idx_tables_store ON tables(store_id, area_id);
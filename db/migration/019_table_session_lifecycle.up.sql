@@ -0,0 +1,27 @@
+-- Table session lifecycle: bill request, close, move and merge.
+-- A table is occupied while it has an active session and dirty after the
+-- session is closed until staff mark it clean.
+ALTER TABLE table_sessions
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'OPEN', -- OPEN, BILL_REQUESTED, CLOSED, MERGED
+    ADD COLUMN bill_requested_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN closed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN closed_by UUID REFERENCES profiles(id) ON DELETE SET NULL,
+    ADD COLUMN merged_into_id UUID REFERENCES table_sessions(id) ON DELETE SET NULL;
+
+-- Sessions were never closed before; treat expired ones as closed and keep
+-- only the newest active session per table
+UPDATE table_sessions ts
+SET is_active = FALSE
+WHERE ts.is_active = TRUE
+  AND EXISTS (
+      SELECT 1 FROM table_sessions n
+      WHERE n.table_id = ts.table_id AND n.is_active = TRUE AND n.created_at > ts.created_at
+  );
+UPDATE table_sessions
+SET is_active = FALSE, status = 'CLOSED', closed_at = LEAST(expires_at, NOW())
+WHERE is_active IS NOT TRUE OR expires_at <= NOW();
+
+CREATE UNIQUE INDEX idx_table_sessions_active ON table_sessions(table_id) WHERE is_active = TRUE;
+CREATE INDEX idx_orders_table_session ON orders(table_session_id);
+
+ALTER TABLE tables ADD COLUMN needs_cleaning BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+-- Indexes for the background sweeper jobs
+CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys(created_at);
+CREATE INDEX idx_table_sessions_expires ON table_sessions(expires_at) WHERE is_active = TRUE;
@@ -0,0 +1,39 @@
+-- Table reservations and the walk-in waitlist
+CREATE TABLE reservations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    table_id UUID REFERENCES tables(id) ON DELETE SET NULL,
+    customer_name VARCHAR(100) NOT NULL,
+    customer_phone VARCHAR(30) NOT NULL,
+    customer_email VARCHAR(255),
+    party_size INT NOT NULL CHECK (party_size > 0),
+    reserved_at TIMESTAMP WITH TIME ZONE NOT NULL, -- Start of the time slot
+    duration_minutes INT NOT NULL CHECK (duration_minutes > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'BOOKED', -- BOOKED, SEATED, NO_SHOW, CANCELLED
+    note TEXT,
+    table_session_id UUID REFERENCES table_sessions(id) ON DELETE SET NULL, -- Set when seated
+    created_by UUID REFERENCES profiles(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_reservations_store_time ON reservations(store_id, reserved_at);
+CREATE INDEX idx_reservations_table_booked ON reservations(table_id, reserved_at) WHERE status = 'BOOKED';
+
+CREATE TABLE waitlist_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    customer_name VARCHAR(100) NOT NULL,
+    customer_phone VARCHAR(30),
+    party_size INT NOT NULL CHECK (party_size > 0),
+    quoted_wait_minutes INT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'WAITING', -- WAITING, NOTIFIED, SEATED, CANCELLED
+    note TEXT,
+    table_id UUID REFERENCES tables(id) ON DELETE SET NULL, -- Table offered when notified
+    table_session_id UUID REFERENCES table_sessions(id) ON DELETE SET NULL,
+    notified_at TIMESTAMP WITH TIME ZONE,
+    seated_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_waitlist_store_open ON waitlist_entries(store_id, created_at) WHERE status IN ('WAITING', 'NOTIFIED');
@@ -0,0 +1,23 @@
+-- Rotating refresh tokens. Each login starts a family; refreshing revokes the
+-- presented token and issues the next one in the same family. Presenting a
+-- revoked token again revokes the whole family.
+CREATE TABLE refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES profiles(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE, -- SHA-256 hex, the token itself is never stored
+    device_name VARCHAR(100),
+    user_agent TEXT,
+    ip_address VARCHAR(45),
+    access_jti VARCHAR(64), -- Latest access token issued with this token, denylisted on logout
+    access_expires_at TIMESTAMP WITH TIME ZONE,
+    authenticated_at TIMESTAMP WITH TIME ZONE NOT NULL, -- Login time of the family
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    replaced_by_id UUID REFERENCES refresh_tokens(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_refresh_tokens_user ON refresh_tokens(user_id) WHERE revoked_at IS NULL;
+CREATE INDEX idx_refresh_tokens_family ON refresh_tokens(family_id);
+CREATE INDEX idx_refresh_tokens_expires ON refresh_tokens(expires_at);
@@ -0,0 +1,23 @@
+-- Registered POS terminals. A device authenticates with its credential; staff
+-- then sign in on it with a short PIN instead of their password.
+CREATE TABLE pos_devices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    credential_hash VARCHAR(64) NOT NULL UNIQUE, -- SHA-256 hex, the credential is shown once on registration
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_seen_at TIMESTAMP WITH TIME ZONE, -- Last PIN login
+    created_by UUID REFERENCES profiles(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_pos_devices_store ON pos_devices(store_id);
+
+-- bcrypt hash of the 4-6 digit PIN
+ALTER TABLE profiles ADD COLUMN pin_hash VARCHAR(255);
+
+-- The terminal the record was made on, NULL when not made on a POS device
+ALTER TABLE orders ADD COLUMN terminal_id UUID REFERENCES pos_devices(id) ON DELETE SET NULL;
+ALTER TABLE shifts ADD COLUMN terminal_id UUID REFERENCES pos_devices(id) ON DELETE SET NULL;
+ALTER TABLE audit_logs ADD COLUMN terminal_id UUID REFERENCES pos_devices(id) ON DELETE SET NULL;
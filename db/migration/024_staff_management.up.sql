@@ -0,0 +1,7 @@
+-- Staff management: owners deactivate staff instead of deleting them, so
+-- orders, shifts and audit logs keep pointing at the user.
+ALTER TABLE profiles ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE profiles ADD COLUMN deactivated_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE profiles ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW();
+
+CREATE INDEX idx_profiles_store ON profiles(store_id);
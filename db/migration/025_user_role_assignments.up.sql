@@ -0,0 +1,18 @@
+-- user_roles becomes the source of truth for what a user may do. A user can
+-- hold several roles, and a role can be limited to one store. profiles.role
+-- stays as the primary role shown on the profile.
+ALTER TABLE user_roles ADD COLUMN id UUID NOT NULL DEFAULT uuid_generate_v4();
+ALTER TABLE user_roles ADD COLUMN store_id UUID REFERENCES stores(id) ON DELETE CASCADE; -- NULL = every store the user works in
+ALTER TABLE user_roles ADD COLUMN assigned_by UUID REFERENCES profiles(id) ON DELETE SET NULL;
+
+ALTER TABLE user_roles DROP CONSTRAINT user_roles_pkey;
+ALTER TABLE user_roles ADD PRIMARY KEY (id);
+
+CREATE UNIQUE INDEX idx_user_roles_global ON user_roles(user_id, role_code) WHERE store_id IS NULL;
+CREATE UNIQUE INDEX idx_user_roles_store ON user_roles(user_id, role_code, store_id) WHERE store_id IS NOT NULL;
+
+-- Existing users keep the role of their profile
+INSERT INTO user_roles (user_id, role_code)
+SELECT p.id, p.role FROM profiles p
+JOIN roles r ON r.code = p.role
+ON CONFLICT DO NOTHING;
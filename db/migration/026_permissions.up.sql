@@ -0,0 +1,102 @@
+-- 1. Role codes are upper case. Migration 003 seeded ADMIN and SUPERVISOR and
+-- 005 lower case codes; move their users to the codes the API checks.
+-- SUPERVISOR becomes KASIR plus KITCHEN, now that a user can hold both.
+INSERT INTO roles (code, name) VALUES ('SUPPLIER', 'Supplier') ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO user_roles (user_id, role_code, store_id, assigned_by, assigned_at)
+SELECT ur.user_id, f.new_code, ur.store_id, ur.assigned_by, ur.assigned_at
+FROM user_roles ur
+JOIN (VALUES
+    ('ADMIN', 'SUPER_ADMIN'),
+    ('SUPERVISOR', 'KASIR'),
+    ('SUPERVISOR', 'KITCHEN'),
+    ('super_admin', 'SUPER_ADMIN'),
+    ('store_owner', 'STORE_OWNER'),
+    ('staff', 'STAFF'),
+    ('supplier', 'SUPPLIER')
+) AS f(old_code, new_code) ON f.old_code = ur.role_code
+ON CONFLICT DO NOTHING;
+
+UPDATE profiles p
+SET role = f.new_code
+FROM (VALUES
+    ('ADMIN', 'SUPER_ADMIN'),
+    ('SUPERVISOR', 'KASIR'),
+    ('super_admin', 'SUPER_ADMIN'),
+    ('store_owner', 'STORE_OWNER'),
+    ('staff', 'STAFF'),
+    ('supplier', 'SUPPLIER')
+) AS f(old_code, new_code)
+WHERE p.role = f.old_code;
+
+-- Also removes their user_roles rows
+DELETE FROM roles WHERE code IN ('ADMIN', 'SUPERVISOR', 'super_admin', 'store_owner', 'staff', 'supplier');
+
+ALTER TABLE roles ADD CONSTRAINT roles_code_upper CHECK (code = UPPER(code));
+
+-- 2. Named permissions checked by the API instead of role codes
+CREATE TABLE permissions (
+    code VARCHAR(50) PRIMARY KEY,
+    description TEXT NOT NULL
+);
+
+INSERT INTO permissions (code, description) VALUES
+('order.create', 'Create orders and held orders'),
+('order.view', 'View orders'),
+('order.history', 'View the status timeline of orders'),
+('order.update_status', 'Move orders through the workflow'),
+('order.void', 'Void orders'),
+('shift.view', 'View the current shift'),
+('shift.open', 'Open a cashier shift'),
+('shift.close', 'Close a cashier shift'),
+('payment.process', 'Take payments'),
+('kitchen.view', 'View KDS stations, tickets and SLA targets'),
+('kitchen.update', 'Bump and recall kitchen tickets'),
+('kitchen.manage', 'Set up KDS stations, routes and SLA targets'),
+('preorder.view', 'View pre-orders and pickup slots'),
+('table.view', 'View tables, floor plan and QR codes'),
+('table.manage', 'Set up tables and the floor plan'),
+('table.service', 'Serve table sessions: move, merge, request the bill, mark clean'),
+('table.close', 'Close table sessions'),
+('reservation.manage', 'Run reservations and the walk-in waitlist'),
+('settings.view', 'View store settings: workflows, charges, pre-order and self-order'),
+('settings.manage', 'Change store settings: workflows, charges, pre-order and self-order'),
+('report.view', 'View reports'),
+('staff.manage', 'Manage staff accounts and their roles'),
+('device.manage', 'Register and revoke POS devices'),
+('permission.manage', 'Change what each role may do');
+
+-- 3. Permissions of each role. Rows with a store_id replace the default row of
+-- the role in that store. SUPER_ADMIN holds every permission without a row.
+CREATE TABLE role_permissions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    role_code VARCHAR(50) NOT NULL REFERENCES roles(code) ON DELETE CASCADE,
+    store_id UUID REFERENCES stores(id) ON DELETE CASCADE, -- NULL = default for every store
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    updated_by UUID REFERENCES profiles(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_role_permissions_default ON role_permissions(role_code) WHERE store_id IS NULL;
+CREATE UNIQUE INDEX idx_role_permissions_store ON role_permissions(role_code, store_id) WHERE store_id IS NOT NULL;
+
+-- Same access as the role checks the routes had before
+INSERT INTO role_permissions (role_code, permissions) VALUES
+('STORE_OWNER', ARRAY[
+    'order.view', 'order.history', 'order.update_status', 'order.void',
+    'kitchen.view', 'kitchen.update', 'kitchen.manage', 'preorder.view',
+    'table.view', 'table.manage', 'table.service', 'table.close', 'reservation.manage',
+    'settings.view', 'settings.manage', 'report.view', 'staff.manage', 'device.manage', 'permission.manage'
+]),
+('KASIR', ARRAY[
+    'order.create', 'order.view', 'order.history', 'order.update_status',
+    'shift.view', 'shift.open', 'shift.close', 'payment.process', 'preorder.view',
+    'table.view', 'table.service', 'table.close', 'reservation.manage', 'settings.view'
+]),
+('KITCHEN', ARRAY[
+    'order.view', 'order.update_status', 'kitchen.view', 'kitchen.update', 'preorder.view', 'settings.view'
+]),
+('STAFF', ARRAY[
+    'order.create', 'order.view', 'preorder.view', 'table.view', 'table.service', 'reservation.manage'
+]),
+('SUPPLIER', ARRAY[]::TEXT[]);
@@ -0,0 +1,24 @@
+-- Row-level security as a second line of defence behind the store checks in
+-- the usecases. ExecTx sets app.store_ids to the stores of the caller; when it
+-- is not set (SUPER_ADMIN, background jobs) every row is visible.
+CREATE OR REPLACE FUNCTION app_store_allowed(store UUID) RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+    SELECT COALESCE(current_setting('app.store_ids', true), '') = ''
+        OR store = ANY(string_to_array(current_setting('app.store_ids', true), ',')::UUID[])
+$$;
+
+-- FORCE so the policies also apply to the table owner the API connects as
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'orders', 'shifts', 'order_drafts', 'kitchen_stations', 'floor_areas',
+        'tables', 'reservations', 'waitlist_entries', 'pos_devices'
+    ] LOOP
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('CREATE POLICY store_isolation ON %I USING (app_store_allowed(store_id)) WITH CHECK (app_store_allowed(store_id))', t);
+    END LOOP;
+END
+$$;
@@ -0,0 +1,23 @@
+-- TOTP two-factor authentication (RFC 6238). A secret only protects logins
+-- once the user confirmed it with a code; until then enrolment can restart.
+CREATE TABLE user_totp (
+    user_id UUID PRIMARY KEY REFERENCES profiles(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL, -- base32
+    confirmed_at TIMESTAMP WITH TIME ZONE,
+    last_used_step BIGINT NOT NULL DEFAULT 0, -- Codes of this step and before are refused, no replays
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- One-time codes for a lost authenticator. Replaced as a set.
+CREATE TABLE user_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES profiles(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL, -- SHA-256 hex
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (user_id, code_hash)
+);
+
+-- Users holding one of these roles have to set up 2FA before they can log in
+-- with a password.
+ALTER TABLE roles ADD COLUMN requires_two_factor BOOLEAN NOT NULL DEFAULT FALSE;
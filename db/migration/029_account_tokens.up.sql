@@ -0,0 +1,28 @@
+-- 1. Email verification. Accounts that existed before count as verified, so
+-- their owners keep opening stores.
+ALTER TABLE auth.users ADD COLUMN email_verified_at TIMESTAMP WITH TIME ZONE;
+UPDATE auth.users SET email_verified_at = created_at;
+
+-- 2. One-time tokens sent by email for password resets and address
+-- verification. Only the hash is stored; a token works once, before expires_at.
+CREATE TABLE auth.one_time_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    purpose VARCHAR(30) NOT NULL, -- PASSWORD_RESET, EMAIL_VERIFICATION
+    email VARCHAR(255) NOT NULL, -- Address the token was sent to; becomes the user's address once verified
+    token_hash VARCHAR(64) NOT NULL UNIQUE, -- SHA-256 hex
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_one_time_tokens_user ON auth.one_time_tokens(user_id, purpose) WHERE used_at IS NULL;
+CREATE INDEX idx_one_time_tokens_expires ON auth.one_time_tokens(expires_at);
+
+-- 3. Owners open their own stores once their email is verified
+INSERT INTO permissions (code, description) VALUES
+('store.create', 'Open new stores');
+
+UPDATE role_permissions
+SET permissions = array_append(permissions, 'store.create')
+WHERE role_code = 'STORE_OWNER' AND store_id IS NULL;
@@ -0,0 +1,4 @@
+-- Requiring 2FA for a role applies to every store, so no role gets it by
+-- default; SUPER_ADMIN always has every permission.
+INSERT INTO permissions (code, description) VALUES
+('auth.2fa.manage', 'Require two-factor authentication for roles');
@@ -0,0 +1,10 @@
+-- Row-level security fails closed: only an explicit '*' (SUPER_ADMIN,
+-- background jobs) shows every store. An unset or empty app.store_ids shows
+-- no store at all. The API sets the scope on every connection it takes from
+-- the pool, so pool reads are covered as well as transactions. Maintenance
+-- sessions run SET app.store_ids = '*' first.
+CREATE OR REPLACE FUNCTION app_store_allowed(store UUID) RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+    SELECT COALESCE(current_setting('app.store_ids', true), '') = '*'
+        OR store = ANY(string_to_array(NULLIF(current_setting('app.store_ids', true), '*'), ',')::UUID[])
+$$;
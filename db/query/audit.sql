@@ -1,8 +1,8 @@
 -- name: CreateAuditLog :one
 INSERT INTO audit_logs (
-    user_id, action, entity, entity_id, "before", "after", ip_address
+    user_id, action, entity, entity_id, "before", "after", ip_address, terminal_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8
 ) RETURNING *;
 
 -- name: ListAuditLogs :many
@@ -8,3 +8,46 @@ INSERT INTO auth.users (
 -- name: GetAuthUserByEmail :one
 SELECT * FROM auth.users
 WHERE email = $1 LIMIT 1;
+
+-- name: GetAuthUser :one
+SELECT * FROM auth.users
+WHERE id = $1 LIMIT 1;
+
+-- name: UpdateAuthUserPassword :exec
+UPDATE auth.users
+SET encrypted_password = $2
+WHERE id = $1;
+
+-- name: SetAuthUserEmailVerified :exec
+-- Sets the address too, it changes when a new one is verified.
+UPDATE auth.users
+SET email = $2, email_verified_at = NOW()
+WHERE id = $1;
+
+-- name: CreateOneTimeToken :one
+INSERT INTO auth.one_time_tokens (
+    user_id, purpose, email, token_hash, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: CountRecentOneTimeTokens :one
+SELECT COUNT(*) FROM auth.one_time_tokens
+WHERE user_id = $1 AND purpose = $2 AND created_at > $3;
+
+-- name: UseOneTimeToken :one
+-- Marks the token used; no row when it is unknown, used or expired.
+UPDATE auth.one_time_tokens
+SET used_at = NOW()
+WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+RETURNING *;
+
+-- name: RevokeOneTimeTokens :exec
+-- Only the latest link sent for a purpose works.
+UPDATE auth.one_time_tokens
+SET used_at = NOW()
+WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL;
+
+-- name: PurgeOneTimeTokens :execrows
+DELETE FROM auth.one_time_tokens
+WHERE expires_at < $1;
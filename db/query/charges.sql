@@ -0,0 +1,24 @@
+-- name: GetOrderTypeCharge :one
+SELECT * FROM store_order_type_charges
+WHERE store_id = $1 AND order_type = $2;
+
+-- name: ListOrderTypeCharges :many
+SELECT * FROM store_order_type_charges
+WHERE store_id = $1
+ORDER BY order_type;
+
+-- name: UpsertOrderTypeCharge :one
+INSERT INTO store_order_type_charges (
+    store_id, order_type, tax_rate, service_charge_rate
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (store_id, order_type)
+DO UPDATE SET tax_rate = EXCLUDED.tax_rate,
+              service_charge_rate = EXCLUDED.service_charge_rate,
+              updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteOrderTypeCharge :exec
+DELETE FROM store_order_type_charges
+WHERE store_id = $1 AND order_type = $2;
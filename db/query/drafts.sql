@@ -0,0 +1,37 @@
+-- name: CreateOrderDraft :one
+INSERT INTO order_drafts (
+    store_id, cashier_id, terminal_id, label, payload, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetOrderDraft :one
+SELECT * FROM order_drafts
+WHERE id = $1 AND expires_at > NOW();
+
+-- name: UpdateOrderDraft :one
+UPDATE order_drafts
+SET terminal_id = @terminal_id,
+    label = @label,
+    payload = @payload,
+    expires_at = @expires_at,
+    updated_at = NOW()
+WHERE id = @id AND expires_at > NOW()
+RETURNING *;
+
+-- name: ListOrderDrafts :many
+-- Optional filters: cashier and terminal.
+SELECT * FROM order_drafts
+WHERE store_id = @store_id
+  AND expires_at > NOW()
+  AND (sqlc.narg(cashier_id)::uuid IS NULL OR cashier_id = sqlc.narg(cashier_id))
+  AND (sqlc.narg(terminal_id)::text IS NULL OR terminal_id = sqlc.narg(terminal_id))
+ORDER BY updated_at DESC;
+
+-- name: DeleteOrderDraft :execrows
+DELETE FROM order_drafts
+WHERE id = $1;
+
+-- name: DeleteExpiredOrderDrafts :execrows
+DELETE FROM order_drafts
+WHERE expires_at <= NOW();
@@ -8,3 +8,7 @@ INSERT INTO idempotency_keys (
 -- name: GetIdempotencyKey :one
 SELECT * FROM idempotency_keys
 WHERE key = $1 LIMIT 1;
+
+-- name: PurgeIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE created_at < $1;
@@ -0,0 +1,3 @@
+-- name: TryJobLock :one
+-- Session level lock: held until the connection closes.
+SELECT pg_try_advisory_lock(hashtext($1::text));
@@ -0,0 +1,107 @@
+-- name: CreateKitchenStation :one
+INSERT INTO kitchen_stations (
+    store_id, name, code, is_default
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetKitchenStation :one
+SELECT * FROM kitchen_stations
+WHERE id = $1 LIMIT 1;
+
+-- name: GetKitchenStationRoute :one
+SELECT * FROM kitchen_station_routes
+WHERE id = $1 LIMIT 1;
+
+-- name: ListKitchenStations :many
+SELECT * FROM kitchen_stations
+WHERE store_id = $1
+ORDER BY name;
+
+-- name: CreateKitchenStationRoute :one
+INSERT INTO kitchen_station_routes (
+    station_id, product_id, category_id
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: ListKitchenStationRoutes :many
+SELECT * FROM kitchen_station_routes
+WHERE station_id = $1
+ORDER BY created_at;
+
+-- name: DeleteKitchenStationRoute :exec
+DELETE FROM kitchen_station_routes
+WHERE id = $1;
+
+-- name: ResolveKitchenStation :one
+-- Product route first, then category route, then the store's default station.
+SELECT ks.* FROM kitchen_stations ks
+LEFT JOIN kitchen_station_routes r ON r.station_id = ks.id
+WHERE ks.store_id = $1
+  AND (r.product_id = $2 OR r.category_id = $3 OR ks.is_default)
+ORDER BY (r.product_id = $2) IS TRUE DESC,
+         (r.category_id = $3) IS TRUE DESC,
+         ks.is_default DESC
+LIMIT 1;
+
+-- name: CreateKitchenTicket :one
+INSERT INTO kitchen_tickets (
+    order_id, station_id
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: GetKitchenTicket :one
+SELECT * FROM kitchen_tickets
+WHERE id = $1 LIMIT 1;
+
+-- name: ListOrderKitchenTickets :many
+SELECT * FROM kitchen_tickets
+WHERE order_id = $1
+ORDER BY created_at;
+
+-- name: ListActiveKitchenTickets :many
+SELECT kt.*, o.order_number, o.order_type, o.store_id
+FROM kitchen_tickets kt
+JOIN orders o ON kt.order_id = o.id
+WHERE kt.station_id = $1 AND kt.status <> 'READY'
+  AND o.status NOT IN ('DONE', 'VOIDED')
+  AND o.kitchen_released_at IS NOT NULL
+ORDER BY kt.created_at;
+
+-- name: UpdateKitchenTicketStatus :one
+UPDATE kitchen_tickets
+SET status = $2,
+    started_at = CASE WHEN $2 = 'COOKING' AND started_at IS NULL THEN NOW() ELSE started_at END,
+    bumped_at = CASE WHEN $2 = 'READY' THEN NOW() ELSE NULL END,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: CountOpenKitchenTickets :one
+SELECT COUNT(*) FROM kitchen_tickets
+WHERE order_id = $1 AND status <> 'READY';
+
+-- name: ListTicketItems :many
+SELECT * FROM order_items
+WHERE ticket_id = $1;
+
+-- name: GetOrderItem :one
+SELECT * FROM order_items
+WHERE id = $1 LIMIT 1;
+
+-- name: UpdateOrderItemStatus :one
+UPDATE order_items
+SET status = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateTicketItemsStatus :exec
+UPDATE order_items
+SET status = $2
+WHERE ticket_id = $1;
+
+-- name: CountOpenTicketItems :one
+SELECT COUNT(*) FROM order_items
+WHERE ticket_id = $1 AND status <> 'READY';
@@ -0,0 +1,25 @@
+-- name: CreateOrderStatusHistory :one
+INSERT INTO order_status_history (
+    order_id, from_status, to_status, actor_id, actor_role, source, reason
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: ListOrderStatusHistory :many
+SELECT * FROM order_status_history
+WHERE order_id = $1
+ORDER BY created_at;
+
+-- name: CreateOrderItemEvent :one
+INSERT INTO order_item_events (
+    order_id, order_item_id, event_type, from_status, to_status, actor_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: ListOrderItemEvents :many
+SELECT e.*, oi.product_name
+FROM order_item_events e
+JOIN order_items oi ON oi.id = e.order_item_id
+WHERE e.order_id = $1
+ORDER BY e.created_at;
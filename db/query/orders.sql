@@ -1,22 +1,29 @@
 -- name: CreateOrder :one
 INSERT INTO orders (
     store_id, table_session_id, cashier_id, order_number, 
-    total_amount, tax_amount, discount_amount, final_amount, note, status, payment_status
+    total_amount, tax_amount, discount_amount, final_amount, note, status, payment_status,
+    order_type, table_id, customer_name, customer_phone, delivery_address, courier, service_charge_amount,
+    scheduled_for, kitchen_released_at, terminal_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21
 ) RETURNING *;
 
 -- name: CreateOrderItem :one
 INSERT INTO order_items (
-    order_id, product_id, product_name, product_price, quantity, total_price, note
+    order_id, product_id, product_name, product_price, quantity, total_price, note, ticket_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8
 ) RETURNING *;
 
 -- name: GetOrder :one
 SELECT * FROM orders
 WHERE id = $1 LIMIT 1;
 
+-- name: GetOrderForUpdate :one
+SELECT * FROM orders
+WHERE id = $1
+FOR UPDATE;
+
 -- name: ListOrdersByStore :many
 SELECT * FROM orders
 WHERE store_id = $1 
@@ -24,18 +31,38 @@ ORDER BY created_at DESC
 LIMIT $2 OFFSET $3;
 
 -- name: UpdateOrderStatus :one
+-- Compare-and-swap: no row is returned when the order changed since it was read.
 UPDATE orders
-SET status = $2, updated_at = NOW()
-WHERE id = $1
+SET status = @status,
+    version = version + 1,
+    updated_at = NOW(),
+    status_changed_at = NOW(),
+    accepted_at = CASE WHEN @status = 'ACCEPTED' THEN NOW() ELSE accepted_at END,
+    cooking_at = CASE WHEN @status = 'COOKING' AND cooking_at IS NULL THEN NOW() ELSE cooking_at END,
+    ready_at = CASE WHEN @status = 'READY' THEN NOW() ELSE ready_at END,
+    completed_at = CASE WHEN @status IN ('DONE', 'VOIDED') THEN NOW() ELSE completed_at END
+WHERE id = @id
+  AND version = @expected_version
+  AND status = @expected_status
 RETURNING *;
 
 -- name: UpdateOrderPaymentStatus :one
+-- Compare-and-swap: no row is returned when the order changed since it was read.
 UPDATE orders
-SET payment_status = $2, updated_at = NOW()
-WHERE id = $1
+SET payment_status = @payment_status,
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = @id
+  AND version = @expected_version
+  AND payment_status = @expected_payment_status
 RETURNING *;
 
 -- name: GetOrdersBySession :many
 SELECT * FROM orders
 WHERE table_session_id = $1
 ORDER BY created_at DESC;
+
+-- name: ListOrderItems :many
+SELECT * FROM order_items
+WHERE order_id = $1
+ORDER BY id;
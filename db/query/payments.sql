@@ -13,3 +13,8 @@ WHERE id = $1;
 -- name: GetPaymentByOrder :one
 SELECT * FROM payments
 WHERE order_id = $1 LIMIT 1;
+
+-- name: ListPaymentsByOrder :many
+SELECT * FROM payments
+WHERE order_id = $1
+ORDER BY created_at;
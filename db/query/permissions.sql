@@ -0,0 +1,21 @@
+-- name: ListPermissions :many
+SELECT * FROM permissions
+ORDER BY code;
+
+-- name: ListRolePermissions :many
+-- Every row, the permission cache holds the whole table.
+SELECT * FROM role_permissions;
+
+-- name: GetRolePermissions :one
+SELECT * FROM role_permissions
+WHERE role_code = $1 AND store_id IS NOT DISTINCT FROM $2
+LIMIT 1;
+
+-- name: DeleteRolePermissions :exec
+DELETE FROM role_permissions
+WHERE role_code = $1 AND store_id IS NOT DISTINCT FROM $2;
+
+-- name: CreateRolePermissions :one
+INSERT INTO role_permissions (role_code, store_id, permissions, updated_by)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
@@ -0,0 +1,30 @@
+-- name: CreatePosDevice :one
+INSERT INTO pos_devices (
+    store_id, name, credential_hash, created_by
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetPosDevice :one
+SELECT * FROM pos_devices
+WHERE id = $1;
+
+-- name: GetPosDeviceByCredential :one
+SELECT * FROM pos_devices
+WHERE credential_hash = $1 AND is_active;
+
+-- name: ListPosDevices :many
+SELECT * FROM pos_devices
+WHERE store_id = $1
+ORDER BY created_at;
+
+-- name: RevokePosDevice :one
+UPDATE pos_devices
+SET is_active = FALSE, revoked_at = NOW()
+WHERE id = $1 AND is_active
+RETURNING *;
+
+-- name: TouchPosDevice :exec
+UPDATE pos_devices
+SET last_seen_at = NOW()
+WHERE id = $1;
@@ -0,0 +1,78 @@
+-- name: GetPreorderSettings :one
+SELECT * FROM store_preorder_settings
+WHERE store_id = $1;
+
+-- name: UpsertPreorderSettings :one
+INSERT INTO store_preorder_settings (
+    store_id, lead_time_minutes, slot_minutes, slot_capacity, timezone
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+ON CONFLICT (store_id)
+DO UPDATE SET lead_time_minutes = EXCLUDED.lead_time_minutes,
+              slot_minutes = EXCLUDED.slot_minutes,
+              slot_capacity = EXCLUDED.slot_capacity,
+              timezone = EXCLUDED.timezone,
+              updated_at = NOW()
+RETURNING *;
+
+-- name: ListOpeningHours :many
+SELECT * FROM store_opening_hours
+WHERE store_id = $1
+ORDER BY weekday;
+
+-- name: DeleteOpeningHours :exec
+DELETE FROM store_opening_hours
+WHERE store_id = $1;
+
+-- name: CreateOpeningHours :one
+INSERT INTO store_opening_hours (
+    store_id, weekday, opens_at, closes_at
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: LockPreorderSlot :exec
+-- Serializes capacity checks for one slot until the transaction ends.
+SELECT pg_advisory_xact_lock(hashtext(@lock_key::text));
+
+-- name: CountPreordersInSlot :one
+SELECT COUNT(*) FROM orders
+WHERE store_id = @store_id
+  AND scheduled_for >= @slot_start AND scheduled_for < @slot_end
+  AND status <> 'VOIDED';
+
+-- name: ListPreorderTimes :many
+SELECT scheduled_for FROM orders
+WHERE store_id = @store_id
+  AND scheduled_for >= @from_time AND scheduled_for < @to_time
+  AND status <> 'VOIDED'
+ORDER BY scheduled_for;
+
+-- name: ListUpcomingPreorders :many
+SELECT * FROM orders
+WHERE store_id = $1
+  AND scheduled_for IS NOT NULL
+  AND kitchen_released_at IS NULL
+  AND status NOT IN ('DONE', 'VOIDED')
+ORDER BY scheduled_for;
+
+-- name: ListDuePreorders :many
+-- Stores without settings use the default 15 minute lead time.
+SELECT o.* FROM orders o
+LEFT JOIN store_preorder_settings s ON s.store_id = o.store_id
+WHERE o.kitchen_released_at IS NULL
+  AND o.scheduled_for IS NOT NULL
+  AND o.status NOT IN ('DONE', 'VOIDED')
+  AND o.scheduled_for - (COALESCE(s.lead_time_minutes, 15) * INTERVAL '1 minute') <= NOW()
+ORDER BY o.scheduled_for;
+
+-- name: ReleasePreorder :execrows
+-- The NEW stage SLA starts when the kitchen can see the order. Bumps the
+-- version so clients holding the pre-order must reload it.
+UPDATE orders
+SET kitchen_released_at = NOW(),
+    status_changed_at = CASE WHEN status = 'NEW' THEN NOW() ELSE status_changed_at END,
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = $1 AND kitchen_released_at IS NULL;
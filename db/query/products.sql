@@ -20,3 +20,22 @@ UPDATE products
 SET stock = stock + $2, updated_at = NOW()
 WHERE id = $1
 RETURNING *;
+
+-- name: CreateStockMovement :one
+INSERT INTO stock_movements (
+    product_id, quantity, type, reference_id
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: CountStockMovementsByReference :one
+SELECT COUNT(*) FROM stock_movements
+WHERE reference_id = $1 AND type = $2;
+
+-- name: ListMenuProducts :many
+-- Available products of a store for the customer menu, grouped by category.
+SELECT p.*, c.name AS category_name
+FROM products p
+LEFT JOIN categories c ON p.category_id = c.id
+WHERE p.store_id = $1 AND p.is_available = TRUE
+ORDER BY c.name NULLS LAST, p.name;
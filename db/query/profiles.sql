@@ -12,3 +12,44 @@ WHERE email = $1 LIMIT 1;
 -- name: GetProfile :one
 SELECT * FROM profiles
 WHERE id = $1 LIMIT 1;
+
+-- name: ListPinStaff :many
+-- Staff of a store who can sign in on its POS devices.
+SELECT id, full_name, role FROM profiles
+WHERE store_id = $1 AND pin_hash IS NOT NULL AND is_active
+ORDER BY full_name;
+
+-- name: SetProfilePin :exec
+UPDATE profiles
+SET pin_hash = $2
+WHERE id = $1;
+
+-- name: ListStoreProfiles :many
+SELECT * FROM profiles
+WHERE store_id = $1
+ORDER BY full_name;
+
+-- name: SetProfileActive :one
+UPDATE profiles
+SET is_active = $2,
+    deactivated_at = CASE WHEN $2 THEN NULL ELSE NOW() END,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateProfileRole :one
+UPDATE profiles
+SET role = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateProfileStore :one
+UPDATE profiles
+SET store_id = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateProfileEmail :exec
+UPDATE profiles
+SET email = $2, updated_at = NOW()
+WHERE id = $1;
@@ -0,0 +1,43 @@
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens (
+    user_id, family_id, token_hash, device_name, user_agent, ip_address,
+    access_jti, access_expires_at, authenticated_at, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING *;
+
+-- name: GetRefreshTokenForUpdate :one
+SELECT * FROM refresh_tokens
+WHERE token_hash = $1
+FOR UPDATE;
+
+-- name: GetRefreshTokenByAccessJTI :one
+SELECT * FROM refresh_tokens
+WHERE user_id = $1 AND access_jti = $2;
+
+-- name: ListActiveRefreshTokens :many
+SELECT * FROM refresh_tokens
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+ORDER BY created_at DESC;
+
+-- name: RotateRefreshToken :exec
+UPDATE refresh_tokens
+SET revoked_at = NOW(), replaced_by_id = $2
+WHERE id = $1;
+
+-- name: RevokeRefreshTokenFamily :many
+-- Returns the access tokens to denylist.
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE family_id = $1 AND user_id = $2 AND revoked_at IS NULL
+RETURNING access_jti, access_expires_at;
+
+-- name: RevokeUserRefreshTokens :many
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL
+RETURNING access_jti, access_expires_at;
+
+-- name: PurgeRefreshTokens :execrows
+DELETE FROM refresh_tokens
+WHERE expires_at < $1;
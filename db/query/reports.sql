@@ -0,0 +1,13 @@
+-- name: GetSalesByOrderType :many
+SELECT order_type,
+       COUNT(*) AS orders_count,
+       COALESCE(SUM(total_amount), 0)::float8 AS gross_sales,
+       COALESCE(SUM(service_charge_amount), 0)::float8 AS service_charge,
+       COALESCE(SUM(tax_amount), 0)::float8 AS tax,
+       COALESCE(SUM(final_amount), 0)::float8 AS net_sales
+FROM orders
+WHERE store_id = @store_id
+  AND status <> 'VOIDED'
+  AND created_at >= @from_date AND created_at < @to_date
+GROUP BY order_type
+ORDER BY order_type;
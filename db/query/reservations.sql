@@ -0,0 +1,76 @@
+-- name: LockReservations :exec
+-- Serializes table assignment of one store until the transaction ends.
+SELECT pg_advisory_xact_lock(hashtext(@lock_key::text));
+
+-- name: CreateReservation :one
+INSERT INTO reservations (
+    store_id, table_id, customer_name, customer_phone, customer_email,
+    party_size, reserved_at, duration_minutes, note, created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING *;
+
+-- name: GetReservation :one
+SELECT r.*, t.name AS table_name
+FROM reservations r
+LEFT JOIN tables t ON t.id = r.table_id
+WHERE r.id = $1;
+
+-- name: GetReservationForUpdate :one
+SELECT * FROM reservations
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListReservations :many
+SELECT r.*, t.name AS table_name
+FROM reservations r
+LEFT JOIN tables t ON t.id = r.table_id
+WHERE r.store_id = @store_id
+  AND r.reserved_at >= @from_time AND r.reserved_at < @to_time
+ORDER BY r.reserved_at, r.created_at;
+
+-- name: UpdateReservation :one
+UPDATE reservations
+SET table_id = $2, customer_name = $3, customer_phone = $4, customer_email = $5,
+    party_size = $6, reserved_at = $7, duration_minutes = $8, note = $9, updated_at = NOW()
+WHERE id = $1 AND status = 'BOOKED'
+RETURNING *;
+
+-- name: SetReservationStatus :one
+UPDATE reservations
+SET status = $2, updated_at = NOW()
+WHERE id = $1 AND status = 'BOOKED'
+RETURNING *;
+
+-- name: SeatReservation :one
+UPDATE reservations
+SET status = 'SEATED', table_id = $2, table_session_id = $3, updated_at = NOW()
+WHERE id = $1 AND status = 'BOOKED'
+RETURNING *;
+
+-- name: CountReservationConflicts :one
+-- Booked reservations of the table overlapping [starts_at, ends_at).
+SELECT COUNT(*) FROM reservations
+WHERE table_id = @table_id AND status = 'BOOKED'
+  AND id IS DISTINCT FROM @exclude_id
+  AND reserved_at < @ends_at
+  AND reserved_at + make_interval(mins => duration_minutes) > @starts_at;
+
+-- name: FindFreeTable :one
+-- Smallest table that fits the party and has no overlapping booking.
+SELECT * FROM tables t
+WHERE t.store_id = @store_id AND t.capacity >= @party_size
+  AND NOT EXISTS (
+      SELECT 1 FROM reservations r
+      WHERE r.table_id = t.id AND r.status = 'BOOKED'
+        AND r.id IS DISTINCT FROM @exclude_id
+        AND r.reserved_at < @ends_at
+        AND r.reserved_at + make_interval(mins => r.duration_minutes) > @starts_at
+  )
+ORDER BY t.capacity, t.name
+LIMIT 1;
+
+-- name: MarkNoShowReservations :execrows
+UPDATE reservations
+SET status = 'NO_SHOW', updated_at = NOW()
+WHERE status = 'BOOKED' AND reserved_at < $1;
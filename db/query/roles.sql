@@ -11,12 +11,46 @@ WHERE code = $1 LIMIT 1;
 SELECT * FROM roles
 ORDER BY name;
 
--- name: AssignRoleToUser :exec
-INSERT INTO user_roles (user_id, role_code)
-VALUES ($1, $2);
+-- name: AssignRoleToUser :one
+INSERT INTO user_roles (user_id, role_code, store_id, assigned_by)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
 
 -- name: GetUserRoles :many
-SELECT r.code, r.name 
-FROM roles r
-JOIN user_roles ur ON r.code = ur.role_code
-WHERE ur.user_id = $1;
+-- Global roles first, then roles limited to a store.
+SELECT ur.id, ur.role_code, r.name, ur.store_id, ur.assigned_at
+FROM user_roles ur
+JOIN roles r ON r.code = ur.role_code
+WHERE ur.user_id = $1
+ORDER BY ur.store_id NULLS FIRST, ur.role_code;
+
+-- name: CountUsersWithRole :one
+SELECT COUNT(DISTINCT user_id) FROM user_roles
+WHERE role_code = $1;
+
+-- name: LockRoleAssignments :exec
+-- Serializes checks on who holds a role until the transaction ends.
+SELECT pg_advisory_xact_lock(hashtext(@lock_key::text));
+
+-- name: DeleteUserGlobalRoles :exec
+DELETE FROM user_roles
+WHERE user_id = $1 AND store_id IS NULL;
+
+-- name: RevokeUserRole :one
+DELETE FROM user_roles
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: SetRoleTwoFactor :one
+UPDATE roles
+SET requires_two_factor = $2
+WHERE code = $1
+RETURNING *;
+
+-- name: UserRequiresTwoFactor :one
+-- Whether any role of the user, global or in a store, requires 2FA.
+SELECT EXISTS (
+    SELECT 1 FROM user_roles ur
+    JOIN roles r ON r.code = ur.role_code
+    WHERE ur.user_id = $1 AND r.requires_two_factor
+);
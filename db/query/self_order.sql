@@ -0,0 +1,15 @@
+-- name: GetSelfOrderSettings :one
+SELECT * FROM store_self_order_settings
+WHERE store_id = $1;
+
+-- name: UpsertSelfOrderSettings :one
+INSERT INTO store_self_order_settings (
+    store_id, enabled, auto_accept
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (store_id)
+DO UPDATE SET enabled = EXCLUDED.enabled,
+              auto_accept = EXCLUDED.auto_accept,
+              updated_at = NOW()
+RETURNING *;
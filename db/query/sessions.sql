@@ -5,13 +5,89 @@ INSERT INTO table_sessions (
     $1, $2, $3
 ) RETURNING *;
 
--- name: GetSessionByToken :one
-SELECT * FROM table_sessions
-WHERE token = $1 LIMIT 1;
-
 -- name: GetTableSessions :many
 SELECT ts.*, t.store_id, t.name as table_name 
 FROM table_sessions ts
 JOIN tables t ON ts.table_id = t.id
 WHERE t.store_id = $1
 ORDER BY ts.created_at DESC;
+
+-- name: GetActiveSessionByToken :one
+-- Expired and closed sessions are not found.
+SELECT ts.*, t.store_id, t.name AS table_name, s.name AS store_name
+FROM table_sessions ts
+JOIN tables t ON ts.table_id = t.id
+JOIN stores s ON t.store_id = s.id
+WHERE ts.token = $1 AND ts.is_active = TRUE AND ts.expires_at > NOW()
+LIMIT 1;
+
+-- name: GetActiveSessionByTable :one
+-- The open session of a table, even when its token has expired.
+SELECT * FROM table_sessions
+WHERE table_id = $1 AND is_active = TRUE
+LIMIT 1;
+
+-- name: GetSession :one
+SELECT ts.*, t.store_id, t.name AS table_name, s.name AS store_name
+FROM table_sessions ts
+JOIN tables t ON ts.table_id = t.id
+JOIN stores s ON t.store_id = s.id
+WHERE ts.id = $1;
+
+-- name: GetSessionForUpdate :one
+SELECT * FROM table_sessions
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ExtendSession :one
+UPDATE table_sessions
+SET expires_at = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: RequestSessionBill :one
+UPDATE table_sessions
+SET status = 'BILL_REQUESTED', bill_requested_at = NOW()
+WHERE id = $1 AND is_active = TRUE
+RETURNING *;
+
+-- name: CloseSession :one
+UPDATE table_sessions
+SET is_active = FALSE, status = $2, closed_at = NOW(), closed_by = $3, merged_into_id = $4
+WHERE id = $1 AND is_active = TRUE
+RETURNING *;
+
+-- name: MoveSession :one
+UPDATE table_sessions
+SET table_id = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: CountUnsettledSessionOrders :one
+-- Orders that still block closing the session: not voided and not paid.
+SELECT COUNT(*) FROM orders
+WHERE table_session_id = $1 AND status <> 'VOIDED' AND payment_status <> 'PAID';
+
+-- name: LockSessionOrders :many
+-- Locks the orders of a session in a fixed order before they are moved.
+SELECT id FROM orders
+WHERE table_session_id = $1
+ORDER BY id
+FOR UPDATE;
+
+-- name: MoveSessionOrders :execrows
+-- Bumps the version so clients holding a moved order must reload it.
+UPDATE orders
+SET table_session_id = sqlc.arg(target_session_id),
+    table_id = sqlc.arg(target_table_id),
+    version = version + 1,
+    updated_at = NOW()
+WHERE table_session_id = sqlc.arg(source_session_id);
+
+-- name: LockExpiredSessions :many
+-- Expired open sessions, locked so no order can be added while the sweeper
+-- decides. Sessions another transaction holds are left for the next run.
+SELECT * FROM table_sessions
+WHERE is_active = TRUE AND expires_at <= NOW()
+ORDER BY id
+FOR UPDATE SKIP LOCKED;
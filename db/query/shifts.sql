@@ -1,8 +1,8 @@
 -- name: CreateShift :one
 INSERT INTO shifts (
-    user_id, store_id, opening_cash
+    user_id, store_id, opening_cash, terminal_id
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4
 ) RETURNING *;
 
 -- name: CloseShift :one
@@ -18,6 +18,11 @@ SELECT * FROM shifts
 WHERE user_id = $1 AND closed_at IS NULL
 LIMIT 1;
 
+-- name: GetShiftForUpdate :one
+SELECT * FROM shifts
+WHERE id = $1
+FOR UPDATE;
+
 -- name: ListShifts :many
 SELECT * FROM shifts
 WHERE store_id = $1
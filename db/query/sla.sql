@@ -0,0 +1,62 @@
+-- name: UpsertSLATarget :one
+INSERT INTO store_sla_targets (
+    store_id, status, target_seconds
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (store_id, status)
+DO UPDATE SET target_seconds = EXCLUDED.target_seconds, updated_at = NOW()
+RETURNING *;
+
+-- name: ListSLATargets :many
+SELECT * FROM store_sla_targets
+WHERE store_id = $1
+ORDER BY status;
+
+-- name: DeleteSLATarget :exec
+DELETE FROM store_sla_targets
+WHERE store_id = $1 AND status = $2;
+
+-- name: ListOverdueOrders :many
+-- Orders whose current stage exceeded the store target and were not escalated yet.
+SELECT o.id, o.store_id, o.order_number, o.status, o.order_type, o.status_changed_at, t.target_seconds
+FROM orders o
+JOIN store_sla_targets t ON t.store_id = o.store_id AND t.status = o.status
+WHERE o.status_changed_at + (t.target_seconds * INTERVAL '1 second') < NOW()
+  AND o.overdue_notified_status IS DISTINCT FROM o.status
+  AND o.kitchen_released_at IS NOT NULL
+ORDER BY o.status_changed_at;
+
+-- name: MarkOrderOverdueNotified :execrows
+-- Escalation bookkeeping only, so the order version is left untouched.
+UPDATE orders
+SET overdue_notified_status = status
+WHERE id = @id AND status = @status;
+
+-- name: GetStationPrepTimes :many
+SELECT ks.id AS station_id, ks.name AS station_name,
+       COUNT(kt.id) AS tickets_count,
+       AVG(EXTRACT(EPOCH FROM (kt.bumped_at - COALESCE(kt.started_at, kt.created_at))))::float8 AS avg_prep_seconds
+FROM kitchen_tickets kt
+JOIN kitchen_stations ks ON ks.id = kt.station_id
+WHERE ks.store_id = @store_id
+  AND kt.bumped_at IS NOT NULL
+  AND kt.created_at >= @from_date AND kt.created_at < @to_date
+GROUP BY ks.id, ks.name
+ORDER BY ks.name;
+
+-- name: GetProductPrepTimes :many
+-- Station ticket timing when the item was routed, otherwise order-level timing.
+SELECT oi.product_id, oi.product_name,
+       COUNT(oi.id) AS items_count,
+       AVG(EXTRACT(EPOCH FROM (
+           COALESCE(kt.bumped_at, o.ready_at) - COALESCE(kt.started_at, o.cooking_at, o.accepted_at, o.created_at)
+       )))::float8 AS avg_prep_seconds
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id
+LEFT JOIN kitchen_tickets kt ON kt.id = oi.ticket_id
+WHERE o.store_id = @store_id
+  AND COALESCE(kt.bumped_at, o.ready_at) IS NOT NULL
+  AND o.created_at >= @from_date AND o.created_at < @to_date
+GROUP BY oi.product_id, oi.product_name
+ORDER BY avg_prep_seconds DESC;
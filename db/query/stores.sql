@@ -23,3 +23,8 @@ RETURNING *;
 -- name: DeleteStore :exec
 DELETE FROM stores
 WHERE id = $1;
+
+-- name: SetStoreScope :exec
+-- Row-level security only shows rows of these stores (comma separated
+-- UUIDs, '*' for every store) until the connection is scoped again.
+SELECT set_config('app.store_ids', sqlc.arg(store_ids)::text, false);
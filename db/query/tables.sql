@@ -0,0 +1,84 @@
+-- name: CreateFloorArea :one
+INSERT INTO floor_areas (
+    store_id, name, sort_order
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetFloorArea :one
+SELECT * FROM floor_areas
+WHERE id = $1;
+
+-- name: ListFloorAreas :many
+SELECT * FROM floor_areas
+WHERE store_id = $1
+ORDER BY sort_order, name;
+
+-- name: UpdateFloorArea :one
+UPDATE floor_areas
+SET name = $2, sort_order = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteFloorArea :exec
+DELETE FROM floor_areas
+WHERE id = $1;
+
+-- name: CreateTable :one
+INSERT INTO tables (
+    store_id, area_id, name, capacity, qr_code, pos_x, pos_y
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetTable :one
+SELECT * FROM tables
+WHERE id = $1;
+
+-- name: GetTableForUpdate :one
+SELECT * FROM tables
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetTableByQRCode :one
+SELECT * FROM tables
+WHERE qr_code = $1;
+
+-- name: ListTables :many
+SELECT * FROM tables
+WHERE store_id = $1
+ORDER BY name;
+
+-- name: UpdateTable :one
+UPDATE tables
+SET area_id = $2, name = $3, capacity = $4, pos_x = $5, pos_y = $6, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteTable :exec
+DELETE FROM tables
+WHERE id = $1;
+
+-- name: SetTableNeedsCleaning :exec
+UPDATE tables
+SET needs_cleaning = $2
+WHERE id = $1;
+
+-- name: ListTableStatuses :many
+SELECT t.id, t.store_id, t.area_id, t.name, t.capacity, t.pos_x, t.pos_y, t.needs_cleaning,
+    ts.id AS session_id, ts.status AS session_status, ts.created_at AS session_started_at, ts.bill_requested_at,
+    (SELECT COUNT(*) FROM orders o
+     WHERE o.table_session_id = ts.id AND o.status <> 'VOIDED' AND o.payment_status <> 'PAID') AS open_orders
+FROM tables t
+LEFT JOIN table_sessions ts ON ts.table_id = t.id AND ts.is_active = TRUE
+WHERE t.store_id = $1
+ORDER BY t.name;
+
+-- name: GetTableStatus :one
+SELECT t.id, t.store_id, t.area_id, t.name, t.capacity, t.pos_x, t.pos_y, t.needs_cleaning,
+    ts.id AS session_id, ts.status AS session_status, ts.created_at AS session_started_at, ts.bill_requested_at,
+    (SELECT COUNT(*) FROM orders o
+     WHERE o.table_session_id = ts.id AND o.status <> 'VOIDED' AND o.payment_status <> 'PAID') AS open_orders
+FROM tables t
+LEFT JOIN table_sessions ts ON ts.table_id = t.id AND ts.is_active = TRUE
+WHERE t.id = $1;
\ No newline at end of file
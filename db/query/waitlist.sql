@@ -0,0 +1,42 @@
+-- name: CreateWaitlistEntry :one
+INSERT INTO waitlist_entries (
+    store_id, customer_name, customer_phone, party_size, quoted_wait_minutes, note
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetWaitlistEntry :one
+SELECT w.*, t.name AS table_name
+FROM waitlist_entries w
+LEFT JOIN tables t ON t.id = w.table_id
+WHERE w.id = $1;
+
+-- name: ListWaitlist :many
+SELECT w.*, t.name AS table_name
+FROM waitlist_entries w
+LEFT JOIN tables t ON t.id = w.table_id
+WHERE w.store_id = $1 AND w.status IN ('WAITING', 'NOTIFIED')
+ORDER BY w.created_at;
+
+-- name: CountWaitingParties :one
+SELECT COUNT(*) FROM waitlist_entries
+WHERE store_id = $1 AND status = 'WAITING';
+
+-- name: NotifyWaitlistEntry :one
+-- Notifying again offers another table.
+UPDATE waitlist_entries
+SET status = 'NOTIFIED', table_id = $2, notified_at = NOW()
+WHERE id = $1 AND status IN ('WAITING', 'NOTIFIED')
+RETURNING *;
+
+-- name: SeatWaitlistEntry :one
+UPDATE waitlist_entries
+SET status = 'SEATED', table_id = $2, table_session_id = $3, seated_at = NOW()
+WHERE id = $1 AND status IN ('WAITING', 'NOTIFIED')
+RETURNING *;
+
+-- name: CancelWaitlistEntry :one
+UPDATE waitlist_entries
+SET status = 'CANCELLED'
+WHERE id = $1 AND status IN ('WAITING', 'NOTIFIED')
+RETURNING *;
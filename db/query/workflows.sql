@@ -0,0 +1,16 @@
+-- name: ListWorkflowTransitions :many
+-- Type specific transitions first, then the ones for all order types.
+SELECT * FROM order_workflow_transitions
+WHERE store_id = @store_id AND order_type IN (@order_type::text, '')
+ORDER BY order_type DESC, from_status, to_status;
+
+-- name: CreateWorkflowTransition :one
+INSERT INTO order_workflow_transitions (
+    store_id, order_type, from_status, to_status, allowed_roles, side_effects
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: DeleteWorkflowTransitions :exec
+DELETE FROM order_workflow_transitions
+WHERE store_id = $1 AND order_type = $2;
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"net/http"
+
+	"pos-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type OrderDraftHandler struct {
+	OrderDraftUsecase domain.OrderDraftUsecase
+}
+
+func NewOrderDraftHandler(uc domain.OrderDraftUsecase) *OrderDraftHandler {
+	return &OrderDraftHandler{
+		OrderDraftUsecase: uc,
+	}
+}
+
+func (h *OrderDraftHandler) CreateDraft(c *gin.Context) {
+	h.saveDraft(c, nil)
+}
+
+func (h *OrderDraftHandler) UpdateDraft(c *gin.Context) {
+	draftID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid draft ID"})
+		return
+	}
+	h.saveDraft(c, &draftID)
+}
+
+func (h *OrderDraftHandler) saveDraft(c *gin.Context, draftID *uuid.UUID) {
+	var req domain.SaveOrderDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.CashierID, _ = uuid.Parse(c.GetString("user_id"))
+	// A device token pins the terminal; the body may only name it for other tokens
+	if device := c.GetString("terminal_id"); device != "" {
+		if req.TerminalID != "" && req.TerminalID != device {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "terminal_id does not match this device"})
+			return
+		}
+		req.TerminalID = device
+	}
+
+	draft, err := h.OrderDraftUsecase.SaveDraft(c.Request.Context(), draftID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	if draftID == nil {
+		status = http.StatusCreated
+	}
+	c.JSON(status, draft)
+}
+
+// ListDrafts expects store_id; cashier_id (or "me") and terminal_id narrow the list.
+func (h *OrderDraftHandler) ListDrafts(c *gin.Context) {
+	storeID, err := uuid.Parse(c.Query("store_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing store_id"})
+		return
+	}
+
+	filter := domain.ListOrderDraftsFilter{StoreID: storeID}
+	if v := c.Query("cashier_id"); v != "" {
+		if v == "me" {
+			v = c.GetString("user_id")
+		}
+		cashierID, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cashier_id"})
+			return
+		}
+		filter.CashierID = &cashierID
+	}
+	if v, ok := c.GetQuery("terminal_id"); ok {
+		filter.TerminalID = &v
+	}
+
+	drafts, err := h.OrderDraftUsecase.ListDrafts(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, drafts)
+}
+
+func (h *OrderDraftHandler) GetDraft(c *gin.Context) {
+	draftID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid draft ID"})
+		return
+	}
+
+	draft, err := h.OrderDraftUsecase.GetDraft(c.Request.Context(), draftID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, draft)
+}
+
+func (h *OrderDraftHandler) DiscardDraft(c *gin.Context) {
+	draftID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid draft ID"})
+		return
+	}
+
+	if err := h.OrderDraftUsecase.DiscardDraft(c.Request.Context(), draftID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ConvertDraft turns the draft into a NEW order.
+func (h *OrderDraftHandler) ConvertDraft(c *gin.Context) {
+	draftID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid draft ID"})
+		return
+	}
+
+	order, err := h.OrderDraftUsecase.ConvertDraft(c.Request.Context(), draftID, terminalID(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("ETag", orderETag(order))
+	c.JSON(http.StatusCreated, order)
+}
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"net/http"
+
+	"pos-api/internal/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSHandler struct {
+	TokenMaker util.TokenMaker
+}
+
+func NewJWKSHandler(tokenMaker util.TokenMaker) *JWKSHandler {
+	return &JWKSHandler{
+		TokenMaker: tokenMaker,
+	}
+}
+
+// GetJWKS publishes the public keys tokens are signed with. Clients may cache
+// the set; a kid they do not know means it is time to fetch it again.
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.TokenMaker.JWKS())
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"net/http"
+
+	"pos-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type KitchenHandler struct {
+	KitchenUsecase domain.KitchenUsecase
+}
+
+func NewKitchenHandler(uc domain.KitchenUsecase) *KitchenHandler {
+	return &KitchenHandler{
+		KitchenUsecase: uc,
+	}
+}
+
+func (h *KitchenHandler) CreateStation(c *gin.Context) {
+	var req domain.CreateStationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	station, err := h.KitchenUsecase.CreateStation(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, station)
+}
+
+func (h *KitchenHandler) ListStations(c *gin.Context) {
+	storeID, err := uuid.Parse(c.Query("store_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing store_id"})
+		return
+	}
+
+	stations, err := h.KitchenUsecase.ListStations(c.Request.Context(), storeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stations)
+}
+
+func (h *KitchenHandler) AddStationRoute(c *gin.Context) {
+	stationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid station ID"})
+		return
+	}
+
+	var req domain.CreateStationRouteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	route, err := h.KitchenUsecase.AddStationRoute(c.Request.Context(), stationID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, route)
+}
+
+func (h *KitchenHandler) ListStationRoutes(c *gin.Context) {
+	stationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid station ID"})
+		return
+	}
+
+	routes, err := h.KitchenUsecase.ListStationRoutes(c.Request.Context(), stationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, routes)
+}
+
+func (h *KitchenHandler) DeleteStationRoute(c *gin.Context) {
+	routeID, err := uuid.Parse(c.Param("routeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid route ID"})
+		return
+	}
+
+	if err := h.KitchenUsecase.DeleteStationRoute(c.Request.Context(), routeID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *KitchenHandler) ListStationTickets(c *gin.Context) {
+	stationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid station ID"})
+		return
+	}
+
+	tickets, err := h.KitchenUsecase.ListStationTickets(c.Request.Context(), stationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tickets)
+}
+
+func (h *KitchenHandler) UpdateTicketStatus(c *gin.Context) {
+	ticketID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	var req struct {
+		Status string `json:"status" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := uuid.Parse(c.GetString("user_id"))
+
+	ticket, err := h.KitchenUsecase.UpdateTicketStatus(c.Request.Context(), ticketID, domain.KitchenStatus(req.Status), userID)
+	if err != nil {
+		if writeOrderConflict(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ticket)
+}
+
+func (h *KitchenHandler) UpdateItemStatus(c *gin.Context) {
+	itemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	var req struct {
+		Status string `json:"status" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := uuid.Parse(c.GetString("user_id"))
+
+	ticket, err := h.KitchenUsecase.UpdateItemStatus(c.Request.Context(), itemID, domain.KitchenStatus(req.Status), userID)
+	if err != nil {
+		if writeOrderConflict(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ticket)
+}
@@ -1,7 +1,11 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"pos-api/internal/domain"
 
@@ -25,6 +29,7 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	req.TerminalID = terminalID(c)
 
 	order, err := h.OrderUsecase.CreateOrder(c.Request.Context(), &req)
 	if err != nil {
@@ -32,6 +37,7 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", orderETag(order))
 	c.JSON(http.StatusCreated, order)
 }
 
@@ -43,48 +49,37 @@ func (h *OrderHandler) UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		Status string `json:"status" binding:"required"`
-	}
+	var req domain.UpdateOrderStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	userIDStr := c.GetString("user_id")
-	userID, _ := uuid.Parse(userIDStr)
-
-	// Get role from context (set by AuthMiddleware, standardizing on single "role" for logic check, or check all?)
-	// For simplicity, we assume primary role or check if any of them is owner.
-	// But AuthMiddleware sets "roles" []string.
-	// Use helper to get roles.
-	var userRole string
-	if rolesVal, exists := c.Get("roles"); exists {
-		if roles, ok := rolesVal.([]string); ok && len(roles) > 0 {
-			userRole = roles[0] // Just taking first for now or we need to pass all?
-			// Logic requires checking if ONE of them is StoreOwner.
-			// Let's pass the first one, or better change usecase to accept slice?
-			// Simpler: Check explicit permission here or pass strings.
-			// Re-reading logic: "if userRole != StoreOwner".
-			// If user has multiple roles, we should check if *any* is StoreOwner.
-			for _, r := range roles {
-				if r == string(domain.RoleStoreOwner) || r == string(domain.RoleSuperAdmin) {
-					userRole = r
-					break
-				}
-			}
-			if userRole == "" {
-				userRole = roles[0]
-			}
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+		version, ok := parseIfMatch(ifMatch)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
 		}
+		req.ExpectedVersion = version
 	}
 
-	order, err := h.OrderUsecase.UpdateStatus(c.Request.Context(), orderID, domain.OrderStatus(req.Status), userID, userRole)
+	userIDStr := c.GetString("user_id")
+	req.UserID, _ = uuid.Parse(userIDStr)
+	req.Source = domain.StatusSourceREST
+	req.TerminalID = terminalID(c)
+	req.Actor = actorClaims(c)
+
+	order, err := h.OrderUsecase.UpdateStatus(c.Request.Context(), orderID, &req)
 	if err != nil {
+		if writeOrderConflict(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	c.Header("ETag", orderETag(order))
 	c.JSON(http.StatusOK, order)
 }
 
@@ -102,5 +97,60 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 		return
 	}
 
+	etag := orderETag(order)
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
 	c.JSON(http.StatusOK, order)
 }
+
+func (h *OrderHandler) GetTimeline(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	timeline, err := h.OrderUsecase.GetTimeline(c.Request.Context(), orderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
+
+// The order version doubles as its ETag.
+func orderETag(order *domain.Order) string {
+	return fmt.Sprintf(`"%d"`, order.Version)
+}
+
+// parseIfMatch returns the expected order version, or nil for "*".
+func parseIfMatch(header string) (*int32, bool) {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return nil, true
+	}
+	header = strings.TrimPrefix(header, "W/")
+	version, err := strconv.ParseInt(strings.Trim(header, `"`), 10, 32)
+	if err != nil {
+		return nil, false
+	}
+	v := int32(version)
+	return &v, true
+}
+
+// writeOrderConflict answers 409 with the latest order state when err is an
+// optimistic locking failure. Returns false for any other error.
+func writeOrderConflict(c *gin.Context, err error) bool {
+	var conflict *domain.OrderConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	c.Header("ETag", orderETag(conflict.Current))
+	c.JSON(http.StatusConflict, gin.H{"error": conflict.Error(), "current": conflict.Current})
+	return true
+}
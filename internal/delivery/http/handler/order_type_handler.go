@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+
+	"pos-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type OrderTypeHandler struct {
+	OrderTypeUsecase domain.OrderTypeUsecase
+}
+
+func NewOrderTypeHandler(uc domain.OrderTypeUsecase) *OrderTypeHandler {
+	return &OrderTypeHandler{
+		OrderTypeUsecase: uc,
+	}
+}
+
+func (h *OrderTypeHandler) SetCharges(c *gin.Context) {
+	var req domain.SetOrderTypeChargesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	charges, err := h.OrderTypeUsecase.SetCharges(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, charges)
+}
+
+func (h *OrderTypeHandler) GetCharges(c *gin.Context) {
+	storeID, err := uuid.Parse(c.Query("store_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing store_id"})
+		return
+	}
+
+	charges, err := h.OrderTypeUsecase.GetCharges(c.Request.Context(), storeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, charges)
+}
+
+// GetSalesReport expects store_id and optional from/to dates, see parseReportRange.
+func (h *OrderTypeHandler) GetSalesReport(c *gin.Context) {
+	storeID, err := uuid.Parse(c.Query("store_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing store_id"})
+		return
+	}
+
+	from, to, ok := parseReportRange(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.OrderTypeUsecase.GetSalesReport(c.Request.Context(), storeID, from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
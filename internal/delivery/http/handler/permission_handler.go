@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"net/http"
+
+	"pos-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PermissionHandler struct {
+	PermissionUsecase domain.PermissionUsecase
+}
+
+func NewPermissionHandler(uc domain.PermissionUsecase) *PermissionHandler {
+	return &PermissionHandler{
+		PermissionUsecase: uc,
+	}
+}
+
+func (h *PermissionHandler) ListPermissions(c *gin.Context) {
+	permissions, err := h.PermissionUsecase.ListPermissions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, permissions)
+}
+
+// GetRolePermissions returns the permissions of :role in store_id, or the
+// defaults without it. STORE_OWNER always gets their own store.
+func (h *PermissionHandler) GetRolePermissions(c *gin.Context) {
+	var storeID *uuid.UUID
+	if v := c.Query("store_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store_id"})
+			return
+		}
+		storeID = &id
+	}
+
+	res, err := h.PermissionUsecase.GetRolePermissions(c.Request.Context(), actorClaims(c), domain.UserRole(c.Param("role")), storeID)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *PermissionHandler) SetRolePermissions(c *gin.Context) {
+	var req domain.SetRolePermissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.PermissionUsecase.SetRolePermissions(c.Request.Context(), actorClaims(c), domain.UserRole(c.Param("role")), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// ResetRolePermissions makes :role in store_id use the defaults again.
+func (h *PermissionHandler) ResetRolePermissions(c *gin.Context) {
+	storeID, err := uuid.Parse(c.Query("store_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing store_id"})
+		return
+	}
+
+	if err := h.PermissionUsecase.ResetRolePermissions(c.Request.Context(), actorClaims(c), domain.UserRole(c.Param("role")), storeID); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"net/http"
+
+	"pos-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// posDeviceKey is set by middleware.DeviceMiddleware on POS routes.
+	posDeviceKey = "pos_device"
+	// authPayloadKey is set by middleware.AuthMiddleware.
+	authPayloadKey = "authorization_payload"
+)
+
+type PosDeviceHandler struct {
+	PosDeviceUsecase domain.PosDeviceUsecase
+}
+
+func NewPosDeviceHandler(uc domain.PosDeviceUsecase) *PosDeviceHandler {
+	return &PosDeviceHandler{
+		PosDeviceUsecase: uc,
+	}
+}
+
+// RegisterDevice returns the device credential; it cannot be shown again.
+func (h *PosDeviceHandler) RegisterDevice(c *gin.Context) {
+	var req domain.RegisterPosDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	actorID, _ := uuid.Parse(c.GetString("user_id"))
+
+	res, err := h.PosDeviceUsecase.RegisterDevice(c.Request.Context(), &req, actorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+func (h *PosDeviceHandler) ListDevices(c *gin.Context) {
+	storeID, err := uuid.Parse(c.Query("store_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing store_id"})
+		return
+	}
+
+	devices, err := h.PosDeviceUsecase.ListDevices(c.Request.Context(), storeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, devices)
+}
+
+func (h *PosDeviceHandler) RevokeDevice(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return
+	}
+
+	device, err := h.PosDeviceUsecase.RevokeDevice(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, device)
+}
+
+// SetPin sets the PIN of the calling user.
+func (h *PosDeviceHandler) SetPin(c *gin.Context) {
+	var req domain.SetPinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, _ := uuid.Parse(c.GetString("user_id"))
+
+	if err := h.PosDeviceUsecase.SetPin(c.Request.Context(), userID, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListStaff returns who can sign in on the calling device.
+func (h *PosDeviceHandler) ListStaff(c *gin.Context) {
+	device := c.MustGet(posDeviceKey).(*domain.PosDevice)
+
+	staff, err := h.PosDeviceUsecase.ListStaff(c.Request.Context(), device)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, staff)
+}
+
+func (h *PosDeviceHandler) PinLogin(c *gin.Context) {
+	device := c.MustGet(posDeviceKey).(*domain.PosDevice)
+
+	var req domain.PinLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	res, err := h.PosDeviceUsecase.PinLogin(c.Request.Context(), device, &req)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// SwitchUser hands the device to the next user. The request carries the
+// token of the current user, which is revoked once the new PIN checks out.
+func (h *PosDeviceHandler) SwitchUser(c *gin.Context) {
+	device := c.MustGet(posDeviceKey).(*domain.PosDevice)
+	current := actorClaims(c)
+
+	var req domain.PinLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	res, err := h.PosDeviceUsecase.SwitchUser(c.Request.Context(), device, current, &req)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// terminalID returns the POS device of the calling token, nil when the user
+// did not sign in on one.
+func terminalID(c *gin.Context) *uuid.UUID {
+	id, err := uuid.Parse(c.GetString("terminal_id"))
+	if err != nil {
+		return nil
+	}
+	return &id
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+
+	"pos-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// tableSessionKey is set by middleware.SessionMiddleware on customer routes.
+const tableSessionKey = "table_session"
+
+type SelfOrderHandler struct {
+	SelfOrderUsecase domain.SelfOrderUsecase
+}
+
+func NewSelfOrderHandler(uc domain.SelfOrderUsecase) *SelfOrderHandler {
+	return &SelfOrderHandler{
+		SelfOrderUsecase: uc,
+	}
+}
+
+func (h *SelfOrderHandler) GetMenu(c *gin.Context) {
+	session := c.MustGet(tableSessionKey).(*domain.TableSession)
+
+	menu, err := h.SelfOrderUsecase.GetMenu(c.Request.Context(), session)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, menu)
+}
+
+func (h *SelfOrderHandler) PlaceOrder(c *gin.Context) {
+	session := c.MustGet(tableSessionKey).(*domain.TableSession)
+
+	var req domain.CustomerOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.SelfOrderUsecase.PlaceOrder(c.Request.Context(), session, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+func (h *SelfOrderHandler) GetBill(c *gin.Context) {
+	session := c.MustGet(tableSessionKey).(*domain.TableSession)
+
+	bill, err := h.SelfOrderUsecase.GetBill(c.Request.Context(), session)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, bill)
+}
+
+func (h *SelfOrderHandler) GetSettings(c *gin.Context) {
+	storeID, err := uuid.Parse(c.Query("store_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing store_id"})
+		return
+	}
+
+	settings, err := h.SelfOrderUsecase.GetSettings(c.Request.Context(), storeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *SelfOrderHandler) SetSettings(c *gin.Context) {
+	var req domain.SetSelfOrderSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := h.SelfOrderUsecase.SetSettings(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
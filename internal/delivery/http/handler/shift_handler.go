@@ -37,6 +37,7 @@ func (h *ShiftHandler) OpenShift(c *gin.Context) {
 			req.UserID = uid
 		}
 	}
+	req.TerminalID = terminalID(c)
 
 	shift, err := h.ShiftUsecase.OpenShift(c.Request.Context(), &req)
 	if err != nil {
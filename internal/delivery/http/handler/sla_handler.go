@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"pos-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SLAHandler struct {
+	SLAUsecase domain.SLAUsecase
+}
+
+func NewSLAHandler(uc domain.SLAUsecase) *SLAHandler {
+	return &SLAHandler{
+		SLAUsecase: uc,
+	}
+}
+
+func (h *SLAHandler) SetTargets(c *gin.Context) {
+	var req domain.SetSLATargetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	targets, err := h.SLAUsecase.SetTargets(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, targets)
+}
+
+func (h *SLAHandler) GetTargets(c *gin.Context) {
+	storeID, err := uuid.Parse(c.Query("store_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing store_id"})
+		return
+	}
+
+	targets, err := h.SLAUsecase.GetTargets(c.Request.Context(), storeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, targets)
+}
+
+// GetPrepTimeReport expects store_id and optional from/to dates, see parseReportRange.
+func (h *SLAHandler) GetPrepTimeReport(c *gin.Context) {
+	storeID, err := uuid.Parse(c.Query("store_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing store_id"})
+		return
+	}
+
+	from, to, ok := parseReportRange(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.SLAUsecase.GetPrepTimeReport(c.Request.Context(), storeID, from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// parseReportRange reads optional from/to query dates (YYYY-MM-DD, inclusive)
+// and defaults to the last 7 days. Writes a 400 and returns false on bad input.
+func parseReportRange(c *gin.Context) (time.Time, time.Time, bool) {
+	var err error
+	to := time.Now()
+	from := to.AddDate(0, 0, -7)
+	if v := c.Query("from"); v != "" {
+		if from, err = time.Parse("2006-01-02", v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, expected YYYY-MM-DD"})
+			return from, to, false
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if to, err = time.Parse("2006-01-02", v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, expected YYYY-MM-DD"})
+			return from, to, false
+		}
+		to = to.AddDate(0, 0, 1) // Inclusive end date
+	}
+	return from, to, true
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"pos-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StoreHandler struct {
+	StoreUsecase domain.StoreUsecase
+}
+
+func NewStoreHandler(uc domain.StoreUsecase) *StoreHandler {
+	return &StoreHandler{
+		StoreUsecase: uc,
+	}
+}
+
+func (h *StoreHandler) CreateStore(c *gin.Context) {
+	var req domain.CreateStoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	store, err := h.StoreUsecase.CreateStore(c.Request.Context(), actorClaims(c), &req)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, store)
+}
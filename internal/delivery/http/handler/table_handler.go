@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"pos-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TableHandler struct {
+	TableUsecase domain.TableUsecase
+}
+
+func NewTableHandler(uc domain.TableUsecase) *TableHandler {
+	return &TableHandler{
+		TableUsecase: uc,
+	}
+}
+
+func (h *TableHandler) CreateArea(c *gin.Context) {
+	var req domain.FloorAreaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	area, err := h.TableUsecase.CreateArea(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, area)
+}
+
+func (h *TableHandler) ListAreas(c *gin.Context) {
+	storeID, err := uuid.Parse(c.Query("store_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing store_id"})
+		return
+	}
+
+	areas, err := h.TableUsecase.ListAreas(c.Request.Context(), storeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, areas)
+}
+
+func (h *TableHandler) UpdateArea(c *gin.Context) {
+	areaID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid area ID"})
+		return
+	}
+
+	var req domain.FloorAreaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	area, err := h.TableUsecase.UpdateArea(c.Request.Context(), areaID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, area)
+}
+
+func (h *TableHandler) DeleteArea(c *gin.Context) {
+	areaID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid area ID"})
+		return
+	}
+
+	if err := h.TableUsecase.DeleteArea(c.Request.Context(), areaID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TableHandler) CreateTable(c *gin.Context) {
+	var req domain.CreateTableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	table, err := h.TableUsecase.CreateTable(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, table)
+}
+
+// ListTables expects store_id; area_id narrows the list to one floor area.
+func (h *TableHandler) ListTables(c *gin.Context) {
+	storeID, areaID, ok := storeAndArea(c)
+	if !ok {
+		return
+	}
+
+	tables, err := h.TableUsecase.ListTables(c.Request.Context(), storeID, areaID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tables)
+}
+
+func (h *TableHandler) GetTable(c *gin.Context) {
+	tableID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid table ID"})
+		return
+	}
+
+	table, err := h.TableUsecase.GetTable(c.Request.Context(), tableID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, table)
+}
+
+func (h *TableHandler) UpdateTable(c *gin.Context) {
+	tableID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid table ID"})
+		return
+	}
+
+	var req domain.UpdateTableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	table, err := h.TableUsecase.UpdateTable(c.Request.Context(), tableID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, table)
+}
+
+func (h *TableHandler) DeleteTable(c *gin.Context) {
+	tableID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid table ID"})
+		return
+	}
+
+	if err := h.TableUsecase.DeleteTable(c.Request.Context(), tableID); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// QRCodePNG renders the table QR code; scale is the pixel size of one module.
+func (h *TableHandler) QRCodePNG(c *gin.Context) {
+	tableID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid table ID"})
+		return
+	}
+
+	scale, _ := strconv.Atoi(c.DefaultQuery("scale", "10"))
+	png, err := h.TableUsecase.QRCodePNG(c.Request.Context(), tableID, scale)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// QRCodeSVG renders the table QR code; size is the width of the image.
+func (h *TableHandler) QRCodeSVG(c *gin.Context) {
+	tableID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid table ID"})
+		return
+	}
+
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "512"))
+	svg, err := h.TableUsecase.QRCodeSVG(c.Request.Context(), tableID, size)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, "image/svg+xml", []byte(svg))
+}
+
+// QRSheetPDF expects store_id and an optional area_id.
+func (h *TableHandler) QRSheetPDF(c *gin.Context) {
+	storeID, areaID, ok := storeAndArea(c)
+	if !ok {
+		return
+	}
+
+	doc, err := h.TableUsecase.QRSheetPDF(c.Request.Context(), storeID, areaID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="table-qr-codes.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", doc)
+}
+
+// GetBoard expects store_id and returns every table as FREE, OCCUPIED,
+// BILL_REQUESTED or DIRTY. Changes are pushed as TABLE_STATUS_CHANGED events.
+func (h *TableHandler) GetBoard(c *gin.Context) {
+	storeID, err := uuid.Parse(c.Query("store_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing store_id"})
+		return
+	}
+
+	board, err := h.TableUsecase.GetBoard(c.Request.Context(), storeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, board)
+}
+
+func (h *TableHandler) MarkClean(c *gin.Context) {
+	tableID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid table ID"})
+		return
+	}
+
+	status, err := h.TableUsecase.MarkClean(c.Request.Context(), tableID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// StartSession is hit by the customer app after scanning a table QR code.
+// It returns the session token for the X-Session-Token header.
+func (h *TableHandler) StartSession(c *gin.Context) {
+	session, err := h.TableUsecase.StartSession(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+func storeAndArea(c *gin.Context) (uuid.UUID, *uuid.UUID, bool) {
+	storeID, err := uuid.Parse(c.Query("store_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing store_id"})
+		return uuid.Nil, nil, false
+	}
+
+	var areaID *uuid.UUID
+	if v := c.Query("area_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid area_id"})
+			return uuid.Nil, nil, false
+		}
+		areaID = &id
+	}
+	return storeID, areaID, true
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"net/http"
+
+	"pos-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TableSessionHandler covers the session lifecycle for staff, plus the bill
+// request and waiter call of customers behind RequireSession.
+type TableSessionHandler struct {
+	SessionUsecase domain.SessionUsecase
+}
+
+func NewTableSessionHandler(uc domain.SessionUsecase) *TableSessionHandler {
+	return &TableSessionHandler{
+		SessionUsecase: uc,
+	}
+}
+
+func (h *TableSessionHandler) GetSession(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	session, err := h.SessionUsecase.GetSession(c.Request.Context(), sessionID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+func (h *TableSessionHandler) RequestBill(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	session, err := h.SessionUsecase.RequestBill(c.Request.Context(), sessionID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// CloseSession fails with 409 while the session has unpaid orders.
+func (h *TableSessionHandler) CloseSession(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+	actorID, _ := uuid.Parse(c.GetString("user_id"))
+
+	session, err := h.SessionUsecase.CloseSession(c.Request.Context(), sessionID, actorID)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+func (h *TableSessionHandler) MoveSession(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	var req domain.MoveSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.SessionUsecase.MoveSession(c.Request.Context(), sessionID, &req)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// MergeSessions returns the target session, which now holds all orders.
+func (h *TableSessionHandler) MergeSessions(c *gin.Context) {
+	var req domain.MergeSessionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	actorID, _ := uuid.Parse(c.GetString("user_id"))
+
+	session, err := h.SessionUsecase.MergeSessions(c.Request.Context(), &req, actorID)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// CustomerRequestBill is the customer side of RequestBill.
+func (h *TableSessionHandler) CustomerRequestBill(c *gin.Context) {
+	session := c.MustGet(tableSessionKey).(*domain.TableSession)
+
+	updated, err := h.SessionUsecase.RequestBill(c.Request.Context(), session.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *TableSessionHandler) CustomerCallWaiter(c *gin.Context) {
+	session := c.MustGet(tableSessionKey).(*domain.TableSession)
+
+	var req domain.CallWaiterRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.SessionUsecase.CallWaiter(c.Request.Context(), session.ID, req.Note); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
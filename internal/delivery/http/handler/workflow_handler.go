@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+
+	"pos-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WorkflowHandler struct {
+	WorkflowUsecase domain.WorkflowUsecase
+}
+
+func NewWorkflowHandler(uc domain.WorkflowUsecase) *WorkflowHandler {
+	return &WorkflowHandler{
+		WorkflowUsecase: uc,
+	}
+}
+
+// GetWorkflow returns the effective graph for store_id and optional order_type.
+func (h *WorkflowHandler) GetWorkflow(c *gin.Context) {
+	storeID, err := uuid.Parse(c.Query("store_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing store_id"})
+		return
+	}
+
+	workflow, err := h.WorkflowUsecase.GetWorkflow(c.Request.Context(), storeID, c.Query("order_type"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, workflow)
+}
+
+func (h *WorkflowHandler) SetWorkflow(c *gin.Context) {
+	var req domain.SetWorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	workflow, err := h.WorkflowUsecase.SetWorkflow(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, workflow)
+}
@@ -16,7 +16,10 @@ const (
 	AuthorizationPayloadKey = "authorization_payload"
 )
 
-func AuthMiddleware(tokenMaker util.TokenMaker) gin.HandlerFunc {
+// AuthMiddleware also rejects tokens revoked by logout, and tokens of revoked
+// POS devices. When the denylist cannot be reached the request fails rather
+// than trusting the token.
+func AuthMiddleware(tokenMaker util.TokenMaker, denylist util.TokenDenylist) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(AuthorizationHeaderKey)
 		if len(authorizationHeader) == 0 {
@@ -46,14 +49,28 @@ func AuthMiddleware(tokenMaker util.TokenMaker) gin.HandlerFunc {
 			return
 		}
 
+		denied, err := util.IsTokenDenied(ctx.Request.Context(), denylist, payload)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, errorResponse(errors.New("cannot verify token revocation")))
+			return
+		}
+		if denied {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(errors.New("token has been revoked")))
+			return
+		}
+
 		ctx.Set(AuthorizationPayloadKey, payload)
-		// Set convenience keys for RoleMiddleware and Handlers
+		// Set convenience keys for handlers
 		ctx.Set("user_id", payload.UserID.String())
 		ctx.Set("roles", payload.Roles)
-		// Fallback for single role middleware if it checks "role"
+		// First role, for handlers that only look at one
 		if len(payload.Roles) > 0 {
 			ctx.Set("role", payload.Roles[0])
 		}
+		// Orders, shifts and audit logs record the POS device of PIN logins
+		if payload.DeviceID != nil {
+			ctx.Set("terminal_id", payload.DeviceID.String())
+		}
 
 		ctx.Next()
 	}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"pos-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DeviceCredentialHeader carries the credential of a registered POS device.
+	DeviceCredentialHeader = "X-Device-Credential"
+	// PosDeviceKey holds the authenticated *domain.PosDevice.
+	PosDeviceKey = "pos_device"
+)
+
+// DeviceMiddleware authenticates the POS device a request comes from. It
+// runs before any user signs in on the device.
+func DeviceMiddleware(deviceUsecase domain.PosDeviceUsecase) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		credential := ctx.GetHeader(DeviceCredentialHeader)
+		if credential == "" {
+			err := errors.New("device credential is not provided")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		device, err := deviceUsecase.AuthenticateDevice(ctx.Request.Context(), credential)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		ctx.Set(PosDeviceKey, device)
+		ctx.Set("store_id", device.StoreID.String())
+		ctx.Request = ctx.Request.WithContext(domain.WithSingleStoreAccess(ctx.Request.Context(), device.StoreID))
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"pos-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionMiddleware returns a constructor for route middlewares that check
+// one permission. The store context is narrowed to the stores where the roles
+// of the token in that store grant it, so the usecases reject every other
+// store once they know which one the request touches. Assumes AuthMiddleware
+// and StoreContextMiddleware have already run.
+func PermissionMiddleware(permissions domain.PermissionUsecase) func(permission domain.Permission) gin.HandlerFunc {
+	return func(permission domain.Permission) gin.HandlerFunc {
+		return func(ctx *gin.Context) {
+			payload, exists := ctx.Get(AuthorizationPayloadKey)
+			claims, ok := payload.(*domain.JwtCustomClaims)
+			if !exists || !ok {
+				ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Role not found in context"})
+				return
+			}
+
+			reqCtx := ctx.Request.Context()
+			access, err := permissions.StoresWithPermission(reqCtx, claims, domain.StoreAccessFromContext(reqCtx), permission)
+			if err != nil {
+				ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, errorResponse(errors.New("cannot load permissions")))
+				return
+			}
+			if access == nil {
+				ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+				return
+			}
+
+			ctx.Request = ctx.Request.WithContext(domain.WithStoreAccess(reqCtx, access))
+			ctx.Next()
+		}
+	}
+}
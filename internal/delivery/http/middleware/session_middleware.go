@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"pos-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// SessionTokenHeader carries the table session token of a customer.
+	SessionTokenHeader = "X-Session-Token"
+	// TableSessionKey holds the validated *domain.TableSession.
+	TableSessionKey = "table_session"
+)
+
+// SessionMiddleware authenticates customer requests by their table session
+// token instead of a user JWT. Store and table come from the session.
+func SessionMiddleware(sessionUsecase domain.SessionUsecase) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := ctx.GetHeader(SessionTokenHeader)
+		if token == "" {
+			err := errors.New("session token is not provided")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		session, err := sessionUsecase.ValidateSession(ctx.Request.Context(), token)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		ctx.Set(TableSessionKey, session)
+		// Convenience keys, like AuthMiddleware sets user_id
+		ctx.Set("store_id", session.StoreID.String())
+		ctx.Set("table_id", session.TableID.String())
+		ctx.Request = ctx.Request.WithContext(domain.WithSingleStoreAccess(ctx.Request.Context(), session.StoreID))
+
+		ctx.Next()
+	}
+}
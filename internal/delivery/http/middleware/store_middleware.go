@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"pos-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StoreContextMiddleware resolves the stores the caller may touch and puts
+// them in the request context, where the usecases check every store_id
+// against them. Assumes AuthMiddleware has already run.
+func StoreContextMiddleware(stores domain.StoreAccessUsecase) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload, exists := ctx.Get(AuthorizationPayloadKey)
+		claims, ok := payload.(*domain.JwtCustomClaims)
+		if !exists || !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(errors.New("authorization payload not found")))
+			return
+		}
+
+		access, err := stores.ResolveStoreAccess(ctx.Request.Context(), claims)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		ctx.Request = ctx.Request.WithContext(domain.WithStoreAccess(ctx.Request.Context(), access))
+		ctx.Next()
+	}
+}
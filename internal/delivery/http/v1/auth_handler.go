@@ -1,26 +1,54 @@
 package v1
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
+	"pos-api/internal/delivery/http/middleware"
 	"pos-api/internal/domain"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type AuthHandler struct {
 	authUsecase domain.AuthUsecase
 }
 
-func NewAuthHandler(router *gin.RouterGroup, uc domain.AuthUsecase) {
+func NewAuthHandler(router *gin.RouterGroup, uc domain.AuthUsecase, authMiddleware, storeContext gin.HandlerFunc, permission func(domain.Permission) gin.HandlerFunc) {
 	handler := &AuthHandler{
 		authUsecase: uc,
 	}
 
 	auth := router.Group("/auth")
 	{
-		auth.POST("/register", handler.register)
+		auth.POST("/register", optionalAuth(authMiddleware), handler.register)
 		auth.POST("/login", handler.login)
+		auth.POST("/login/2fa", handler.loginTwoFactor)
+		auth.POST("/login/2fa/enroll", handler.enrollTwoFactor)
+		auth.POST("/refresh", handler.refresh)
+		// Tokens of emailed links
+		auth.POST("/password/forgot", handler.forgotPassword)
+		auth.POST("/password/reset", handler.resetPassword)
+		auth.POST("/email/verify", handler.verifyEmail)
+	}
+
+	// Device sessions of the caller
+	session := router.Group("/auth", authMiddleware)
+	{
+		session.POST("/logout", handler.logout)
+		session.POST("/logout-all", handler.logoutAll)
+		session.GET("/sessions", handler.listSessions)
+		session.DELETE("/sessions/:id", handler.revokeSession)
+		session.POST("/email/verification", handler.sendVerification)
+		session.PUT("/email", handler.changeEmail)
+	}
+
+	// Owners sign staff out, e.g. when they leave
+	users := router.Group("/users", authMiddleware, storeContext, permission(domain.PermStaffManage))
+	{
+		users.POST("/:id/logout-all", handler.logoutUser)
 	}
 }
 
@@ -31,9 +59,14 @@ func (h *AuthHandler) register(ctx *gin.Context) {
 		return
 	}
 
-	profile, err := h.authUsecase.Register(ctx, &req)
+	var actor *domain.JwtCustomClaims
+	if payload, ok := ctx.Get(middleware.AuthorizationPayloadKey); ok {
+		actor = payload.(*domain.JwtCustomClaims)
+	}
+
+	profile, err := h.authUsecase.Register(ctx, actor, &req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -46,8 +79,12 @@ func (h *AuthHandler) login(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	req.ClientInfo = clientInfo(ctx)
 
 	res, err := h.authUsecase.Login(ctx, &req)
+	if writeLoginThrottled(ctx, err) {
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
@@ -55,3 +92,231 @@ func (h *AuthHandler) login(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, res)
 }
+
+func (h *AuthHandler) loginTwoFactor(ctx *gin.Context) {
+	var req domain.TwoFactorLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.ClientInfo = clientInfo(ctx)
+
+	res, err := h.authUsecase.LoginTwoFactor(ctx, &req)
+	if writeLoginThrottled(ctx, err) {
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (h *AuthHandler) enrollTwoFactor(ctx *gin.Context) {
+	var req domain.TwoFactorEnrollRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	enrollment, err := h.authUsecase.EnrollTwoFactor(ctx, &req)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, enrollment)
+}
+
+func (h *AuthHandler) refresh(ctx *gin.Context) {
+	var req domain.RefreshRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.ClientInfo = clientInfo(ctx)
+
+	res, err := h.authUsecase.Refresh(ctx, &req)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// forgotPassword always answers 202, whether or not a mail was sent.
+func (h *AuthHandler) forgotPassword(ctx *gin.Context) {
+	var req domain.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.ClientInfo = clientInfo(ctx)
+
+	if err := h.authUsecase.ForgotPassword(ctx, &req); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusAccepted)
+}
+
+func (h *AuthHandler) resetPassword(ctx *gin.Context) {
+	var req domain.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.ClientInfo = clientInfo(ctx)
+
+	if err := h.authUsecase.ResetPassword(ctx, &req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) verifyEmail(ctx *gin.Context) {
+	var req domain.VerifyEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.ClientInfo = clientInfo(ctx)
+
+	if err := h.authUsecase.VerifyEmail(ctx, &req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) sendVerification(ctx *gin.Context) {
+	if err := h.authUsecase.SendVerification(ctx, authClaims(ctx).UserID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusAccepted)
+}
+
+// changeEmail takes effect once the link sent to the new address is opened.
+func (h *AuthHandler) changeEmail(ctx *gin.Context) {
+	var req domain.ChangeEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.ClientInfo = clientInfo(ctx)
+
+	if err := h.authUsecase.ChangeEmail(ctx, authClaims(ctx).UserID, &req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusAccepted)
+}
+
+// logout takes an optional refresh_token; without it the session of the
+// access token is ended.
+func (h *AuthHandler) logout(ctx *gin.Context) {
+	var req domain.LogoutRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && ctx.Request.ContentLength > 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authUsecase.Logout(ctx, authClaims(ctx), &req); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) logoutAll(ctx *gin.Context) {
+	if err := h.authUsecase.LogoutAll(ctx, authClaims(ctx).UserID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) listSessions(ctx *gin.Context) {
+	sessions, err := h.authUsecase.ListSessions(ctx, authClaims(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sessions)
+}
+
+func (h *AuthHandler) revokeSession(ctx *gin.Context) {
+	sessionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	if err := h.authUsecase.RevokeSession(ctx, authClaims(ctx).UserID, sessionID); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) logoutUser(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.authUsecase.LogoutUser(ctx, authClaims(ctx), userID); err != nil {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// optionalAuth authenticates the request only when it carries a token.
+func optionalAuth(authMiddleware gin.HandlerFunc) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader(middleware.AuthorizationHeaderKey) == "" {
+			ctx.Next()
+			return
+		}
+		authMiddleware(ctx)
+	}
+}
+
+// writeLoginThrottled answers 429 with Retry-After when err refuses a login
+// because of too many attempts. Returns false for any other error.
+func writeLoginThrottled(ctx *gin.Context, err error) bool {
+	var throttled *domain.LoginThrottledError
+	if !errors.As(err, &throttled) {
+		return false
+	}
+	ctx.Header("Retry-After", strconv.Itoa(int(throttled.RetryAfter.Seconds())))
+	ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error(), "locked": throttled.Locked})
+	return true
+}
+
+func authClaims(ctx *gin.Context) *domain.JwtCustomClaims {
+	return ctx.MustGet(middleware.AuthorizationPayloadKey).(*domain.JwtCustomClaims)
+}
+
+func clientInfo(ctx *gin.Context) domain.ClientInfo {
+	return domain.ClientInfo{
+		IPAddress: ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	}
+}
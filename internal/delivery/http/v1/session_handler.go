@@ -12,14 +12,15 @@ type SessionHandler struct {
 	sessionUsecase domain.SessionUsecase
 }
 
-func NewSessionHandler(router *gin.RouterGroup, uc domain.SessionUsecase) {
+func NewSessionHandler(router *gin.RouterGroup, uc domain.SessionUsecase, authMiddleware, storeContext gin.HandlerFunc) {
 	handler := &SessionHandler{
 		sessionUsecase: uc,
 	}
 
 	sessions := router.Group("/table-sessions")
 	{
-		sessions.POST("", handler.createSession)
+		// Staff open sessions of their stores; customers start one by QR code
+		sessions.POST("", authMiddleware, storeContext, handler.createSession)
 		// Validation is usually done via Middleware or explicit check
 		sessions.POST("/validate", handler.validateSession)
 	}
@@ -0,0 +1,136 @@
+package v1
+
+import (
+	"net/http"
+
+	"pos-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorHandler struct {
+	twoFactorUsecase domain.TwoFactorUsecase
+}
+
+func NewTwoFactorHandler(router *gin.RouterGroup, uc domain.TwoFactorUsecase, authMiddleware, storeContext gin.HandlerFunc, permission func(domain.Permission) gin.HandlerFunc) {
+	handler := &TwoFactorHandler{
+		twoFactorUsecase: uc,
+	}
+
+	// Authenticator of the caller
+	twoFactor := router.Group("/auth/2fa", authMiddleware)
+	{
+		twoFactor.GET("", handler.status)
+		twoFactor.POST("/enroll", handler.enroll)
+		twoFactor.POST("/confirm", handler.confirm)
+		twoFactor.POST("/disable", handler.disable)
+		twoFactor.POST("/recovery-codes", handler.regenerateRecoveryCodes)
+	}
+
+	// Roles that have to use 2FA
+	roles := router.Group("/auth/2fa/roles", authMiddleware, storeContext, permission(domain.PermTwoFactorManage))
+	{
+		roles.GET("", handler.listRoleRequirements)
+		roles.PUT("/:role", handler.setRoleRequirement)
+	}
+}
+
+func (h *TwoFactorHandler) status(ctx *gin.Context) {
+	status, err := h.twoFactorUsecase.Status(ctx, authClaims(ctx).UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, status)
+}
+
+func (h *TwoFactorHandler) enroll(ctx *gin.Context) {
+	enrollment, err := h.twoFactorUsecase.Enroll(ctx, authClaims(ctx).UserID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, enrollment)
+}
+
+func (h *TwoFactorHandler) confirm(ctx *gin.Context) {
+	var req domain.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.twoFactorUsecase.Confirm(ctx, authClaims(ctx).UserID, &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, codes)
+}
+
+func (h *TwoFactorHandler) disable(ctx *gin.Context) {
+	var req domain.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.twoFactorUsecase.Disable(ctx, authClaims(ctx).UserID, &req)
+	if writeLoginThrottled(ctx, err) {
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (h *TwoFactorHandler) regenerateRecoveryCodes(ctx *gin.Context) {
+	var req domain.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.twoFactorUsecase.RegenerateRecoveryCodes(ctx, authClaims(ctx).UserID, &req)
+	if writeLoginThrottled(ctx, err) {
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, codes)
+}
+
+func (h *TwoFactorHandler) listRoleRequirements(ctx *gin.Context) {
+	roles, err := h.twoFactorUsecase.ListRoleRequirements(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, roles)
+}
+
+func (h *TwoFactorHandler) setRoleRequirement(ctx *gin.Context) {
+	var req domain.SetRoleTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	role, err := h.twoFactorUsecase.SetRoleRequirement(ctx, domain.UserRole(ctx.Param("role")), &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, role)
+}
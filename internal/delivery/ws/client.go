@@ -1,11 +1,17 @@
 package ws
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
+	"pos-api/internal/domain"
+	"pos-api/internal/util"
+
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
@@ -42,8 +48,50 @@ type Client struct {
 	// Buffered channel of outbound messages.
 	Send chan []byte
 
-	// Filter criteria (e.g., outlet_id)
-	OutletID string
+	// Stores the caller could access when connecting; events of other
+	// stores are never sent
+	Access *domain.StoreAccess
+
+	// Optional narrowing to one of them (?outlet_id=)
+	OutletID uuid.UUID
+
+	// KDS screens subscribe to a single kitchen station
+	StationID string
+
+	// Authenticated caller
+	Claims *domain.JwtCustomClaims
+
+	// Checked again on every command and ping, see tokenValid
+	denylist util.TokenDenylist
+}
+
+// tokenValid reports whether the token the socket connected with still
+// holds: it has not expired and was not revoked since (logout, deactivated
+// account, revoked device). A denylist that cannot be read counts as revoked,
+// like in AuthMiddleware.
+func (c *Client) tokenValid() bool {
+	if c.Claims.ExpiresAt != nil && !time.Now().Before(c.Claims.ExpiresAt.Time) {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), writeWait)
+	defer cancel()
+	denied, err := util.IsTokenDenied(ctx, c.denylist, c.Claims)
+	return err == nil && !denied
+}
+
+// closeRevoked tells the peer why the socket ends; it has to reconnect with a
+// new token.
+func (c *Client) closeRevoked() {
+	msg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "token expired or revoked")
+	c.Conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeWait))
+}
+
+// receives reports whether events of the store go to this client.
+func (c *Client) receives(storeID uuid.UUID) bool {
+	if c.OutletID != uuid.Nil && c.OutletID != storeID {
+		return false
+	}
+	return c.Access.Allows(storeID)
 }
 
 // writePump pumps messages from the hub to the websocket connection.
@@ -79,6 +127,10 @@ func (c *Client) writePump() {
 				return
 			}
 		case <-ticker.C:
+			if !c.tokenValid() {
+				c.closeRevoked()
+				return
+			}
 			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if err := c.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				return
@@ -97,28 +149,81 @@ func (c *Client) readPump() {
 	c.Conn.SetReadDeadline(time.Now().Add(pongWait))
 	c.Conn.SetPongHandler(func(string) error { c.Conn.SetReadDeadline(time.Now().Add(pongWait)); return nil })
 	for {
-		_, _, err := c.Conn.ReadMessage()
+		_, message, err := c.Conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("error: %v", err)
 			}
 			break
 		}
-		// We can handle incoming messages here (e.g. heartbeat or specialized commands)
+
+		if c.Hub.Dispatcher == nil {
+			continue
+		}
+		if !c.tokenValid() {
+			c.closeRevoked()
+			break
+		}
+		reply := c.Hub.Dispatcher.Handle(c.Claims, message)
+		c.Hub.Direct <- &DirectMessage{Client: c, Message: reply}
 	}
 }
 
 // ServeWs handles websocket requests from the peer.
-func ServeWs(hub *Hub, ctx *gin.Context) {
+// Browsers cannot set headers on the upgrade request, so the access token may
+// also be passed as ?token=. The socket only carries events of the caller's
+// stores, resolved like the store context of the REST API.
+func ServeWs(hub *Hub, tokenMaker util.TokenMaker, denylist util.TokenDenylist, storeAccess domain.StoreAccessUsecase, ctx *gin.Context) {
+	token := ctx.Query("token")
+	if fields := strings.Fields(ctx.GetHeader("Authorization")); token == "" && len(fields) == 2 && strings.EqualFold(fields[0], "bearer") {
+		token = fields[1]
+	}
+	if token == "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authorization token is required"})
+		return
+	}
+	claims, err := tokenMaker.VerifyToken(token)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	denied, err := util.IsTokenDenied(ctx.Request.Context(), denylist, claims)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "cannot verify token revocation"})
+		return
+	}
+	if denied {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+		return
+	}
+
+	access, err := storeAccess.ResolveStoreAccess(ctx.Request.Context(), claims)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	var outletID uuid.UUID
+	if v := ctx.Query("outlet_id"); v != "" {
+		outletID, err = uuid.Parse(v)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid outlet_id"})
+			return
+		}
+		if !access.Allows(outletID) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "no access to this store"})
+			return
+		}
+	}
+
 	conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
 	if err != nil {
 		log.Println(err)
 		return
 	}
 
-	outletID := ctx.Query("outlet_id")
+	stationID := ctx.Query("station_id")
 
-	client := &Client{Hub: hub, Conn: conn, Send: make(chan []byte, 256), OutletID: outletID}
+	client := &Client{Hub: hub, Conn: conn, Send: make(chan []byte, 256), Access: access, OutletID: outletID, StationID: stationID, Claims: claims, denylist: denylist}
 	client.Hub.Register <- client
 
 	// Allow collection of memory referenced by the caller by doing all work in
@@ -0,0 +1,223 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"pos-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// Time allowed for a single command to run against the usecases.
+const commandTimeout = 10 * time.Second
+
+type CommandType string
+
+const (
+	CmdUpdateOrderStatus CommandType = "UPDATE_ORDER_STATUS"
+	CmdStartCooking      CommandType = "START_COOKING"
+	CmdBumpTicket        CommandType = "BUMP_TICKET"
+	CmdBumpItem          CommandType = "BUMP_ITEM"
+	CmdRecallTicket      CommandType = "RECALL_TICKET"
+	CmdAckEvent          CommandType = "ACK_EVENT"
+)
+
+// Command is a client -> server message. ID is chosen by the client and
+// echoed back in the reply so tablets can correlate responses.
+type Command struct {
+	ID      string         `json:"id"`
+	Type    CommandType    `json:"type"`
+	Payload CommandPayload `json:"payload"`
+}
+
+type CommandPayload struct {
+	OrderID  string `json:"order_id,omitempty"`
+	TicketID string `json:"ticket_id,omitempty"`
+	ItemID   string `json:"item_id,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	EventID  string `json:"event_id,omitempty"`
+
+	// Optimistic locking for order commands, see the If-Match header on REST
+	ExpectedVersion *int32 `json:"expected_version,omitempty"`
+}
+
+// CommandReply is sent only to the client that issued the command.
+type CommandReply struct {
+	Type  string      `json:"type"` // COMMAND_RESULT or COMMAND_ERROR
+	ID    string      `json:"id"`
+	Data  interface{} `json:"data,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// commandPermissions mirrors the PermissionMiddleware setup of the equivalent
+// REST routes. An empty entry means any authenticated user.
+var commandPermissions = map[CommandType]domain.Permission{
+	CmdUpdateOrderStatus: domain.PermOrderUpdateStatus,
+	CmdStartCooking:      domain.PermKitchenUpdate,
+	CmdBumpTicket:        domain.PermKitchenUpdate,
+	CmdBumpItem:          domain.PermKitchenUpdate,
+	CmdRecallTicket:      domain.PermKitchenUpdate,
+	CmdAckEvent:          "",
+}
+
+// CommandDispatcher runs socket commands through the same usecases as the REST API.
+type CommandDispatcher struct {
+	orderUsecase      domain.OrderUsecase
+	kitchenUsecase    domain.KitchenUsecase
+	permissionUsecase domain.PermissionUsecase
+	storeAccess       domain.StoreAccessUsecase
+	eventSvc          domain.EventService
+}
+
+func NewCommandDispatcher(orderUC domain.OrderUsecase, kitchenUC domain.KitchenUsecase, permissionUC domain.PermissionUsecase, storeAccess domain.StoreAccessUsecase, eventSvc domain.EventService) *CommandDispatcher {
+	return &CommandDispatcher{
+		orderUsecase:      orderUC,
+		kitchenUsecase:    kitchenUC,
+		permissionUsecase: permissionUC,
+		storeAccess:       storeAccess,
+		eventSvc:          eventSvc,
+	}
+}
+
+// Handle decodes and executes one raw client message, returning the encoded reply.
+func (d *CommandDispatcher) Handle(claims *domain.JwtCustomClaims, raw []byte) []byte {
+	var cmd Command
+	if err := json.Unmarshal(raw, &cmd); err != nil {
+		return encodeReply(CommandReply{Type: "COMMAND_ERROR", Error: "invalid command format"})
+	}
+
+	data, err := d.execute(claims, &cmd)
+	if err != nil {
+		reply := CommandReply{Type: "COMMAND_ERROR", ID: cmd.ID, Error: err.Error()}
+		var conflict *domain.OrderConflictError
+		if errors.As(err, &conflict) {
+			reply.Data = conflict.Current // Let the tablet refresh without another round trip
+		}
+		return encodeReply(reply)
+	}
+	return encodeReply(CommandReply{Type: "COMMAND_RESULT", ID: cmd.ID, Data: data})
+}
+
+func (d *CommandDispatcher) execute(claims *domain.JwtCustomClaims, cmd *Command) (interface{}, error) {
+	permission, known := commandPermissions[cmd.Type]
+	if !known {
+		return nil, fmt.Errorf("unknown command type: %s", cmd.Type)
+	}
+	if claims == nil {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	// Same store scoping as the REST API, resolved per command so a role or
+	// store change applies to open sockets too
+	access, err := d.storeAccess.ResolveStoreAccess(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if permission != "" {
+		// Only the stores where the user's roles there grant the permission
+		access, err = d.permissionUsecase.StoresWithPermission(ctx, claims, access, permission)
+		if err != nil {
+			return nil, err
+		}
+		if access == nil {
+			return nil, fmt.Errorf("insufficient permissions")
+		}
+	}
+	ctx = domain.WithStoreAccess(ctx, access)
+
+	p := cmd.Payload
+	switch cmd.Type {
+	case CmdUpdateOrderStatus:
+		orderID, err := parseID("order_id", p.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		return d.orderUsecase.UpdateStatus(ctx, orderID, statusRequest(claims, domain.OrderStatus(p.Status), p))
+
+	case CmdStartCooking:
+		// Either a single station ticket, or the whole order
+		if p.TicketID != "" {
+			ticketID, err := parseID("ticket_id", p.TicketID)
+			if err != nil {
+				return nil, err
+			}
+			return d.kitchenUsecase.UpdateTicketStatus(ctx, ticketID, domain.KitchenStatusCooking, claims.UserID)
+		}
+		orderID, err := parseID("order_id", p.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		return d.orderUsecase.UpdateStatus(ctx, orderID, statusRequest(claims, domain.OrderStatusCooking, p))
+
+	case CmdBumpTicket:
+		ticketID, err := parseID("ticket_id", p.TicketID)
+		if err != nil {
+			return nil, err
+		}
+		return d.kitchenUsecase.UpdateTicketStatus(ctx, ticketID, domain.KitchenStatusReady, claims.UserID)
+
+	case CmdBumpItem:
+		itemID, err := parseID("item_id", p.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		return d.kitchenUsecase.UpdateItemStatus(ctx, itemID, domain.KitchenStatusReady, claims.UserID)
+
+	case CmdRecallTicket:
+		ticketID, err := parseID("ticket_id", p.TicketID)
+		if err != nil {
+			return nil, err
+		}
+		return d.kitchenUsecase.UpdateTicketStatus(ctx, ticketID, domain.KitchenStatusCooking, claims.UserID)
+
+	case CmdAckEvent:
+		if p.EventID == "" {
+			return nil, fmt.Errorf("event_id is required")
+		}
+		// Routed to the screens of the user's store, like the event itself
+		ack := map[string]interface{}{
+			"event_id": p.EventID,
+			"user_id":  claims.UserID,
+			"store_id": claims.StoreID,
+		}
+		if err := d.eventSvc.PublishEvent(ctx, "EVENT_ACKNOWLEDGED", ack); err != nil {
+			return nil, err
+		}
+		return ack, nil
+	}
+
+	return nil, fmt.Errorf("unknown command type: %s", cmd.Type)
+}
+
+func statusRequest(claims *domain.JwtCustomClaims, status domain.OrderStatus, p CommandPayload) *domain.UpdateOrderStatusRequest {
+	return &domain.UpdateOrderStatusRequest{
+		Status:          status,
+		Reason:          p.Reason,
+		ExpectedVersion: p.ExpectedVersion,
+		UserID:          claims.UserID,
+		Actor:           claims,
+		Source:          domain.StatusSourceWebSocket,
+		TerminalID:      claims.DeviceID,
+	}
+}
+
+func parseID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid or missing %s", field)
+	}
+	return id, nil
+}
+
+func encodeReply(reply CommandReply) []byte {
+	bytes, _ := json.Marshal(reply)
+	return bytes
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -17,6 +18,15 @@ type Hub struct {
 	// Unregister requests from clients.
 	Unregister chan *Client
 
+	// Inbound messages from Redis, fanned out to clients.
+	Broadcast chan []byte
+
+	// Command replies addressed to a single client.
+	Direct chan *DirectMessage
+
+	// Executes commands sent by clients (nil = commands disabled)
+	Dispatcher *CommandDispatcher
+
 	// Redis Integration
 	RedisClient *redis.Client
 	PubSub      *redis.PubSub
@@ -26,6 +36,8 @@ func NewHub(rdb *redis.Client) *Hub {
 	return &Hub{
 		Register:    make(chan *Client),
 		Unregister:  make(chan *Client),
+		Broadcast:   make(chan []byte, 256),
+		Direct:      make(chan *DirectMessage, 256),
 		Clients:     make(map[*Client]bool),
 		RedisClient: rdb,
 	}
@@ -39,10 +51,12 @@ func (h *Hub) Run() {
 
 	go func() {
 		for msg := range ch {
-			h.broadcast([]byte(msg.Payload))
+			h.Broadcast <- []byte(msg.Payload)
 		}
 	}()
 
+	// All access to Clients happens in this loop
+
 	for {
 		select {
 		case client := <-h.Register:
@@ -52,26 +66,66 @@ func (h *Hub) Run() {
 				delete(h.Clients, client)
 				close(client.Send)
 			}
+		case message := <-h.Broadcast:
+			h.broadcast(message)
+		case direct := <-h.Direct:
+			if _, ok := h.Clients[direct.Client]; ok {
+				h.send(direct.Client, direct.Message)
+			}
 		}
 	}
 }
 
+// DirectMessage is delivered to one client only (e.g. a command reply).
+type DirectMessage struct {
+	Client  *Client
+	Message []byte
+}
+
+// eventEnvelope is the subset of a published event used for routing.
+type eventEnvelope struct {
+	Payload struct {
+		StoreID   string `json:"store_id"`
+		StationID string `json:"station_id"`
+	} `json:"payload"`
+}
+
+// broadcast sends an event to the clients of its store. Events without a
+// store_id reach nobody, so a payload missing one cannot leak across stores.
 func (h *Hub) broadcast(message []byte) {
-	// Naive broadcast to all connected clients (KDS, POS)
-	// Filter logic should be added here based on OutletID
+	var env eventEnvelope
+	_ = json.Unmarshal(message, &env)
+	storeID, err := uuid.Parse(env.Payload.StoreID)
+	if err != nil {
+		return
+	}
+
 	for client := range h.Clients {
-		select {
-		case client.Send <- message:
-		default:
-			close(client.Send)
-			delete(h.Clients, client)
+		if !client.receives(storeID) {
+			continue
 		}
+		// Station screens skip the tickets of other stations; order-level
+		// events carry no station and reach every screen
+		if client.StationID != "" && env.Payload.StationID != "" && client.StationID != env.Payload.StationID {
+			continue
+		}
+		h.send(client, message)
+	}
+}
+
+func (h *Hub) send(client *Client, message []byte) {
+	select {
+	case client.Send <- message:
+	default:
+		close(client.Send)
+		delete(h.Clients, client)
 	}
 }
 
 // PublishEvent allows other parts of app to publish events
 func (h *Hub) PublishEvent(ctx context.Context, eventType string, payload interface{}) error {
 	msg := map[string]interface{}{
+		"id":      uuid.New().String(), // Referenced by ACK_EVENT commands
 		"type":    eventType,
 		"payload": payload,
 	}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"slices"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
@@ -8,6 +10,31 @@ import (
 type JwtCustomClaims struct {
 	UserID   uuid.UUID `json:"user_id"`
 	Username string    `json:"username"`
-	Roles    []string  `json:"roles"`
+	// Roles apply in the user's own store: global roles and those limited to
+	// that store.
+	Roles []string `json:"roles"`
+	// StoreRoles lists, for every other store where the user holds a role
+	// limited to it, all roles that apply there: those roles and SUPER_ADMIN.
+	StoreRoles map[uuid.UUID][]string `json:"store_roles,omitempty"`
+	// StoreID is the user's own store, whose permissions apply to Roles.
+	// DeviceID is set on tokens from a PIN login; the token is only valid for
+	// that POS device and its store.
+	DeviceID *uuid.UUID `json:"device_id,omitempty"`
+	StoreID  *uuid.UUID `json:"store_id,omitempty"`
 	jwt.RegisteredClaims
 }
+
+// RolesIn returns the roles that apply in a store. Global roles other than
+// SUPER_ADMIN only apply in the user's own store.
+func (c *JwtCustomClaims) RolesIn(storeID uuid.UUID) []string {
+	if roles, ok := c.StoreRoles[storeID]; ok {
+		return roles
+	}
+	if c.StoreID != nil && *c.StoreID == storeID {
+		return c.Roles
+	}
+	if slices.Contains(c.Roles, string(RoleSuperAdmin)) {
+		return []string{string(RoleSuperAdmin)}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderDraft is a held (parked) order. It lives outside the orders table until
+// it is converted, so it never reaches the kitchen or reserves stock.
+type OrderDraft struct {
+	ID         uuid.UUID          `json:"id"`
+	StoreID    uuid.UUID          `json:"store_id"`
+	CashierID  uuid.UUID          `json:"cashier_id"`
+	TerminalID string             `json:"terminal_id,omitempty"`
+	Label      string             `json:"label,omitempty"` // e.g. customer name or "Table 4"
+	Order      CreateOrderRequest `json:"order"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+}
+
+// SaveOrderDraftRequest creates or replaces a draft. Order is stored as
+// entered and only validated on conversion.
+type SaveOrderDraftRequest struct {
+	StoreID    uuid.UUID          `json:"store_id" binding:"required"`
+	TerminalID string             `json:"terminal_id" binding:"max=64"`
+	Label      string             `json:"label" binding:"max=100"`
+	Order      CreateOrderRequest `json:"order" binding:"-"`
+
+	CashierID uuid.UUID `json:"-"` // From token
+}
+
+type ListOrderDraftsFilter struct {
+	StoreID    uuid.UUID
+	CashierID  *uuid.UUID
+	TerminalID *string
+}
+
+type OrderDraftUsecase interface {
+	SaveDraft(ctx context.Context, draftID *uuid.UUID, req *SaveOrderDraftRequest) (*OrderDraft, error)
+	GetDraft(ctx context.Context, draftID uuid.UUID) (*OrderDraft, error)
+	ListDrafts(ctx context.Context, filter ListOrderDraftsFilter) ([]OrderDraft, error)
+	DiscardDraft(ctx context.Context, draftID uuid.UUID) error
+	// ConvertDraft creates the real order through CreateOrder and removes the draft.
+	// terminalID is the POS device converting it, nil outside a device token.
+	ConvertDraft(ctx context.Context, draftID uuid.UUID, terminalID *uuid.UUID) (*Order, error)
+	// PurgeExpired removes drafts that were not touched within the TTL.
+	PurgeExpired(ctx context.Context) (int64, error)
+}
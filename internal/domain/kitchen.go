@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KitchenStatus is shared by kitchen tickets and the order items routed to them.
+type KitchenStatus string
+
+const (
+	KitchenStatusNew     KitchenStatus = "NEW"
+	KitchenStatusCooking KitchenStatus = "COOKING"
+	KitchenStatusReady   KitchenStatus = "READY" // Ticket "bumped"
+)
+
+type KitchenStation struct {
+	ID        uuid.UUID `json:"id"`
+	StoreID   uuid.UUID `json:"store_id"`
+	Name      string    `json:"name"`
+	Code      string    `json:"code"`
+	IsDefault bool      `json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type KitchenStationRoute struct {
+	ID         uuid.UUID  `json:"id"`
+	StationID  uuid.UUID  `json:"station_id"`
+	ProductID  *uuid.UUID `json:"product_id,omitempty"`
+	CategoryID *uuid.UUID `json:"category_id,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// KitchenTicket groups the items of one order that are prepared at one station.
+type KitchenTicket struct {
+	ID          uuid.UUID     `json:"id"`
+	OrderID     uuid.UUID     `json:"order_id"`
+	StoreID     uuid.UUID     `json:"store_id"`
+	StationID   uuid.UUID     `json:"station_id"`
+	OrderNumber string        `json:"order_number,omitempty"`
+	OrderType   OrderType     `json:"order_type,omitempty"`
+	Status      KitchenStatus `json:"status"`
+	Items       []OrderItem   `json:"items"`
+	StartedAt   *time.Time    `json:"started_at,omitempty"`
+	BumpedAt    *time.Time    `json:"bumped_at,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+type CreateStationRequest struct {
+	StoreID   uuid.UUID `json:"store_id" binding:"required"`
+	Name      string    `json:"name" binding:"required"`
+	Code      string    `json:"code" binding:"required"`
+	IsDefault bool      `json:"is_default"`
+}
+
+type CreateStationRouteRequest struct {
+	ProductID  *uuid.UUID `json:"product_id"`
+	CategoryID *uuid.UUID `json:"category_id"`
+}
+
+type KitchenUsecase interface {
+	CreateStation(ctx context.Context, req *CreateStationRequest) (*KitchenStation, error)
+	ListStations(ctx context.Context, storeID uuid.UUID) ([]KitchenStation, error)
+	AddStationRoute(ctx context.Context, stationID uuid.UUID, req *CreateStationRouteRequest) (*KitchenStationRoute, error)
+	ListStationRoutes(ctx context.Context, stationID uuid.UUID) ([]KitchenStationRoute, error)
+	DeleteStationRoute(ctx context.Context, routeID uuid.UUID) error
+	ListStationTickets(ctx context.Context, stationID uuid.UUID) ([]KitchenTicket, error)
+	UpdateTicketStatus(ctx context.Context, ticketID uuid.UUID, status KitchenStatus, userID uuid.UUID) (*KitchenTicket, error)
+	UpdateItemStatus(ctx context.Context, itemID uuid.UUID, status KitchenStatus, userID uuid.UUID) (*KitchenTicket, error)
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +17,10 @@ const (
 	OrderStatusReady    OrderStatus = "READY"
 	OrderStatusDone     OrderStatus = "DONE"
 	OrderStatusVoided   OrderStatus = "VOIDED"
+
+	// Optional stages for full-service workflows, see Workflow
+	OrderStatusServed OrderStatus = "SERVED"
+	OrderStatusBilled OrderStatus = "BILLED"
 )
 
 type PaymentStatus string
@@ -26,33 +31,61 @@ const (
 	PaymentStatusRefunded PaymentStatus = "REFUNDED"
 )
 
+// StatusChangeSource records the channel an order status change came from.
+type StatusChangeSource string
+
+const (
+	StatusSourceREST      StatusChangeSource = "REST"
+	StatusSourceWebSocket StatusChangeSource = "WEBSOCKET"
+	StatusSourceSystem    StatusChangeSource = "SYSTEM" // Derived changes, e.g. KDS bumps
+)
+
 type Order struct {
-	ID             uuid.UUID     `json:"id"`
-	StoreID        uuid.UUID     `json:"store_id"`
-	TableSessionID *uuid.UUID    `json:"table_session_id,omitempty"`
-	CashierID      *uuid.UUID    `json:"cashier_id,omitempty"`
-	OrderNumber    string        `json:"order_number"`
-	Status         OrderStatus   `json:"status"`
-	PaymentStatus  PaymentStatus `json:"payment_status"`
-	TotalAmount    float64       `json:"total_amount"`
-	TaxAmount      float64       `json:"tax_amount"`
-	DiscountAmount float64       `json:"discount_amount"`
-	FinalAmount    float64       `json:"final_amount"`
-	Note           string        `json:"note,omitempty"`
-	Items          []OrderItem   `json:"items"`
-	CreatedAt      time.Time     `json:"created_at"`
-	UpdatedAt      time.Time     `json:"updated_at"`
+	ID                  uuid.UUID     `json:"id"`
+	StoreID             uuid.UUID     `json:"store_id"`
+	TableSessionID      *uuid.UUID    `json:"table_session_id,omitempty"`
+	CashierID           *uuid.UUID    `json:"cashier_id,omitempty"`
+	TerminalID          *uuid.UUID    `json:"terminal_id,omitempty"` // POS device it was rung up on
+	OrderNumber         string        `json:"order_number"`
+	OrderType           OrderType     `json:"order_type"`
+	Status              OrderStatus   `json:"status"`
+	PaymentStatus       PaymentStatus `json:"payment_status"`
+	TotalAmount         float64       `json:"total_amount"`
+	ServiceChargeAmount float64       `json:"service_charge_amount"`
+	TaxAmount           float64       `json:"tax_amount"`
+	DiscountAmount      float64       `json:"discount_amount"`
+	FinalAmount         float64       `json:"final_amount"`
+	Note                string        `json:"note,omitempty"`
+	Version             int32         `json:"version"`
+
+	// Type specific data
+	TableID         *uuid.UUID `json:"table_id,omitempty"`
+	CustomerName    string     `json:"customer_name,omitempty"`
+	CustomerPhone   string     `json:"customer_phone,omitempty"`
+	DeliveryAddress string     `json:"delivery_address,omitempty"`
+	Courier         string     `json:"courier,omitempty"`
+
+	// Pre-orders stay off the KDS until KitchenReleasedAt is set
+	ScheduledFor      *time.Time `json:"scheduled_for,omitempty"`
+	KitchenReleasedAt *time.Time `json:"kitchen_released_at,omitempty"`
+
+	Items     []OrderItem     `json:"items"`
+	Tickets   []KitchenTicket `json:"tickets,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
 }
 
 type OrderItem struct {
-	ID           uuid.UUID `json:"id"`
-	OrderID      uuid.UUID `json:"order_id"`
-	ProductID    uuid.UUID `json:"product_id"`
-	ProductName  string    `json:"product_name"`
-	ProductPrice float64   `json:"product_price"`
-	Quantity     int32     `json:"quantity"`
-	TotalPrice   float64   `json:"total_price"`
-	Note         string    `json:"note,omitempty"`
+	ID           uuid.UUID     `json:"id"`
+	OrderID      uuid.UUID     `json:"order_id"`
+	ProductID    uuid.UUID     `json:"product_id"`
+	ProductName  string        `json:"product_name"`
+	ProductPrice float64       `json:"product_price"`
+	Quantity     int32         `json:"quantity"`
+	TotalPrice   float64       `json:"total_price"`
+	Note         string        `json:"note,omitempty"`
+	TicketID     *uuid.UUID    `json:"ticket_id,omitempty"`
+	Status       KitchenStatus `json:"status,omitempty"`
 }
 
 type CreateOrderRequest struct {
@@ -61,6 +94,20 @@ type CreateOrderRequest struct {
 	Note           string                   `json:"note"`
 	Items          []CreateOrderItemRequest `json:"items" binding:"required,dive"`
 	IdempotencyKey string                   `json:"idempotency_key"` // Optional
+
+	// Optional; DINE_IN when a table (session) is given, TAKEAWAY otherwise
+	OrderType       OrderType  `json:"order_type"`
+	TableID         *uuid.UUID `json:"table_id"`
+	CustomerName    string     `json:"customer_name"`
+	CustomerPhone   string     `json:"customer_phone"`
+	DeliveryAddress string     `json:"delivery_address"`
+	Courier         string     `json:"courier"`
+
+	// Optional; pickup/delivery time for pre-orders, see PreorderUsecase
+	ScheduledFor *time.Time `json:"scheduled_for"`
+
+	// Filled in by the delivery layer from a POS device token
+	TerminalID *uuid.UUID `json:"-"`
 }
 
 type CreateOrderItemRequest struct {
@@ -69,9 +116,75 @@ type CreateOrderItemRequest struct {
 	Note      string    `json:"note"`
 }
 
+type UpdateOrderStatusRequest struct {
+	Status OrderStatus `json:"status" binding:"required"`
+	Reason string      `json:"reason"`
+	// Optional; the If-Match header takes precedence when both are sent.
+	ExpectedVersion *int32 `json:"expected_version"`
+
+	// Filled in by the delivery layer
+	UserID uuid.UUID `json:"-"`
+	// Actor is the caller's token; its roles in the order's store are checked
+	// against the workflow and permissions such as order.void. Not set for
+	// system changes.
+	Actor      *JwtCustomClaims   `json:"-"`
+	Source     StatusChangeSource `json:"-"`
+	TerminalID *uuid.UUID         `json:"-"`
+}
+
+// OrderConflictError is returned when an order was changed by someone else
+// between reading and writing it. Current holds the latest state.
+type OrderConflictError struct {
+	Current *Order
+}
+
+func (e *OrderConflictError) Error() string {
+	return fmt.Sprintf("order was modified concurrently (now %s, version %d)", e.Current.Status, e.Current.Version)
+}
+
+type TimelineEntryType string
+
+const (
+	TimelineOrderCreated   TimelineEntryType = "ORDER_CREATED"
+	TimelineStatusChanged  TimelineEntryType = "STATUS_CHANGED"
+	TimelineItemChanged    TimelineEntryType = "ITEM_CHANGED"
+	TimelinePaymentCreated TimelineEntryType = "PAYMENT_CREATED"
+	TimelinePaymentPaid    TimelineEntryType = "PAYMENT_PAID"
+)
+
+// TimelineEntry is one event in the life of an order. Only the fields
+// relevant to the entry type are set.
+type TimelineEntry struct {
+	Type       TimelineEntryType  `json:"type"`
+	At         time.Time          `json:"at"`
+	FromStatus string             `json:"from_status,omitempty"`
+	ToStatus   string             `json:"to_status,omitempty"`
+	ActorID    *uuid.UUID         `json:"actor_id,omitempty"`
+	ActorRole  string             `json:"actor_role,omitempty"`
+	Source     StatusChangeSource `json:"source,omitempty"`
+	Reason     string             `json:"reason,omitempty"`
+
+	OrderItemID *uuid.UUID `json:"order_item_id,omitempty"`
+	ProductName string     `json:"product_name,omitempty"`
+
+	PaymentID     *uuid.UUID `json:"payment_id,omitempty"`
+	PaymentMethod string     `json:"payment_method,omitempty"`
+	Amount        float64    `json:"amount,omitempty"`
+}
+
+type OrderTimeline struct {
+	OrderID     uuid.UUID       `json:"order_id"`
+	OrderNumber string          `json:"order_number"`
+	Status      OrderStatus     `json:"status"`
+	Entries     []TimelineEntry `json:"entries"`
+}
+
 type OrderUsecase interface {
 	CreateOrder(ctx context.Context, req *CreateOrderRequest) (*Order, error)
 	GetOrder(ctx context.Context, orderID uuid.UUID) (*Order, error)
-	UpdateStatus(ctx context.Context, orderID uuid.UUID, status OrderStatus, userID uuid.UUID, userRole string) (*Order, error)
+	UpdateStatus(ctx context.Context, orderID uuid.UUID, req *UpdateOrderStatusRequest) (*Order, error)
+	GetTimeline(ctx context.Context, orderID uuid.UUID) (*OrderTimeline, error)
 	GetOrdersBySession(ctx context.Context, sessionID uuid.UUID) ([]Order, error)
+	// PurgeIdempotencyKeys drops stored CreateOrder responses older than ttl.
+	PurgeIdempotencyKeys(ctx context.Context, ttl time.Duration) (int64, error)
 }
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OrderType string
+
+const (
+	OrderTypeDineIn   OrderType = "DINE_IN"  // Needs a table or table session
+	OrderTypeTakeaway OrderType = "TAKEAWAY" // Walk-in at the counter
+	OrderTypeDelivery OrderType = "DELIVERY" // Needs a delivery address
+	OrderTypePickup   OrderType = "PICKUP"   // Needs customer name and phone
+)
+
+// OrderTypes lists every supported order type.
+var OrderTypes = []OrderType{OrderTypeDineIn, OrderTypeTakeaway, OrderTypeDelivery, OrderTypePickup}
+
+func (t OrderType) IsValid() bool {
+	switch t {
+	case OrderTypeDineIn, OrderTypeTakeaway, OrderTypeDelivery, OrderTypePickup:
+		return true
+	default:
+		return false
+	}
+}
+
+// OrderTypeCharge holds the tax and service charge of one order type, in
+// percent. Service charge applies to the item total, tax to item total plus
+// service charge.
+type OrderTypeCharge struct {
+	StoreID           uuid.UUID `json:"store_id"`
+	OrderType         OrderType `json:"order_type"`
+	TaxRate           float64   `json:"tax_rate"`
+	ServiceChargeRate float64   `json:"service_charge_rate"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type SetOrderTypeChargesRequest struct {
+	StoreID uuid.UUID              `json:"store_id" binding:"required"`
+	Charges []OrderTypeChargeInput `json:"charges" binding:"required,dive"`
+}
+
+type OrderTypeChargeInput struct {
+	OrderType         OrderType `json:"order_type" binding:"required"`
+	TaxRate           float64   `json:"tax_rate" binding:"gte=0,lte=100"`
+	ServiceChargeRate float64   `json:"service_charge_rate" binding:"gte=0,lte=100"` // Both 0 removes the rule
+}
+
+type SalesByOrderType struct {
+	OrderType     OrderType `json:"order_type"`
+	OrdersCount   int64     `json:"orders_count"`
+	GrossSales    float64   `json:"gross_sales"`
+	ServiceCharge float64   `json:"service_charge"`
+	Tax           float64   `json:"tax"`
+	NetSales      float64   `json:"net_sales"`
+}
+
+type SalesByOrderTypeReport struct {
+	StoreID uuid.UUID          `json:"store_id"`
+	From    time.Time          `json:"from"`
+	To      time.Time          `json:"to"`
+	Types   []SalesByOrderType `json:"types"`
+}
+
+type OrderTypeUsecase interface {
+	SetCharges(ctx context.Context, req *SetOrderTypeChargesRequest) ([]OrderTypeCharge, error)
+	GetCharges(ctx context.Context, storeID uuid.UUID) ([]OrderTypeCharge, error)
+	GetSalesReport(ctx context.Context, storeID uuid.UUID, from, to time.Time) (*SalesByOrderTypeReport, error)
+}
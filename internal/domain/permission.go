@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permission names one thing a user may do. Roles are mapped to permissions
+// in the role_permissions table.
+type Permission string
+
+const (
+	PermOrderCreate       Permission = "order.create"
+	PermOrderView         Permission = "order.view"
+	PermOrderHistory      Permission = "order.history"
+	PermOrderUpdateStatus Permission = "order.update_status"
+	PermOrderVoid         Permission = "order.void"
+	PermShiftView         Permission = "shift.view"
+	PermShiftOpen         Permission = "shift.open"
+	PermShiftClose        Permission = "shift.close"
+	PermPaymentProcess    Permission = "payment.process"
+	PermKitchenView       Permission = "kitchen.view"
+	PermKitchenUpdate     Permission = "kitchen.update"
+	PermKitchenManage     Permission = "kitchen.manage"
+	PermPreorderView      Permission = "preorder.view"
+	PermTableView         Permission = "table.view"
+	PermTableManage       Permission = "table.manage"
+	PermTableService      Permission = "table.service"
+	PermTableClose        Permission = "table.close"
+	PermReservationManage Permission = "reservation.manage"
+	PermSettingsView      Permission = "settings.view"
+	PermSettingsManage    Permission = "settings.manage"
+	PermReportView        Permission = "report.view"
+	PermStaffManage       Permission = "staff.manage"
+	PermDeviceManage      Permission = "device.manage"
+	PermPermissionManage  Permission = "permission.manage"
+	PermStoreCreate       Permission = "store.create"
+	PermTwoFactorManage   Permission = "auth.2fa.manage"
+)
+
+// StoreAssignable reports whether a STORE_OWNER may give the permission to
+// the staff roles of their store. Managing accounts, devices and permissions,
+// and opening stores, stays with owners; 2FA rules apply to every store.
+func (p Permission) StoreAssignable() bool {
+	return p != PermStaffManage && p != PermDeviceManage && p != PermPermissionManage && p != PermStoreCreate &&
+		p != PermTwoFactorManage
+}
+
+type PermissionInfo struct {
+	Code        Permission `json:"code"`
+	Description string     `json:"description"`
+}
+
+// RolePermissions is what a role may do, in one store or by default.
+type RolePermissions struct {
+	Role        UserRole     `json:"role"`
+	StoreID     *uuid.UUID   `json:"store_id,omitempty"`
+	IsDefault   bool         `json:"is_default"` // The store has not customised the role
+	Permissions []Permission `json:"permissions"`
+	UpdatedAt   *time.Time   `json:"updated_at,omitempty"`
+}
+
+// SetRolePermissionsRequest replaces the permissions of a role. Without
+// StoreID it changes the default for every store, which only SUPER_ADMIN
+// may do.
+type SetRolePermissionsRequest struct {
+	StoreID     *uuid.UUID   `json:"store_id"`
+	Permissions []Permission `json:"permissions" binding:"required"`
+}
+
+type PermissionUsecase interface {
+	// HasPermission reports whether any of the roles grants the permission in
+	// the store; a nil storeID uses the defaults. SUPER_ADMIN holds every
+	// permission. The mapping is cached.
+	HasPermission(ctx context.Context, roles []string, storeID *uuid.UUID, permission Permission) (bool, error)
+	// StoresWithPermission narrows access to the stores where the roles the
+	// caller holds in that store grant the permission. It returns nil when no
+	// store is left. Callers without any store keep their empty access if
+	// their roles grant the permission by default, e.g. an owner opening a
+	// first store.
+	StoresWithPermission(ctx context.Context, claims *JwtCustomClaims, access *StoreAccess, permission Permission) (*StoreAccess, error)
+	ListPermissions(ctx context.Context) ([]PermissionInfo, error)
+	GetRolePermissions(ctx context.Context, actor *JwtCustomClaims, role UserRole, storeID *uuid.UUID) (*RolePermissions, error)
+	SetRolePermissions(ctx context.Context, actor *JwtCustomClaims, role UserRole, req *SetRolePermissionsRequest) (*RolePermissions, error)
+	// ResetRolePermissions drops the store customisation of a role, so the
+	// default applies again.
+	ResetRolePermissions(ctx context.Context, actor *JwtCustomClaims, role UserRole, storeID uuid.UUID) error
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PosDevice is a registered terminal of a store. Staff sign in on it with
+// their PIN.
+type PosDevice struct {
+	ID         uuid.UUID  `json:"id"`
+	StoreID    uuid.UUID  `json:"store_id"`
+	Name       string     `json:"name"`
+	IsActive   bool       `json:"is_active"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+type RegisterPosDeviceRequest struct {
+	StoreID uuid.UUID `json:"store_id" binding:"required"`
+	Name    string    `json:"name" binding:"required,max=100"`
+}
+
+// RegisterPosDeviceResponse carries the terminal credential. It is only
+// returned once; the terminal sends it in the X-Device-Credential header.
+type RegisterPosDeviceResponse struct {
+	Device     *PosDevice `json:"device"`
+	Credential string     `json:"credential"`
+}
+
+// PinStaff is a user who can sign in on the devices of a store.
+type PinStaff struct {
+	ID       uuid.UUID `json:"id"`
+	FullName string    `json:"full_name"`
+	Role     UserRole  `json:"role"`
+}
+
+// SetPinRequest sets the PIN of the caller, confirmed with their password.
+type SetPinRequest struct {
+	Password string `json:"password" binding:"required"`
+	Pin      string `json:"pin" binding:"required,numeric,min=4,max=6"`
+}
+
+type PinLoginRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+	Pin    string    `json:"pin" binding:"required,numeric,min=4,max=6"`
+	ClientInfo
+}
+
+// PinLoginResponse has no refresh token; staff enter their PIN again when
+// the access token expires.
+type PinLoginResponse struct {
+	AccessToken          string    `json:"access_token"`
+	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+	DeviceID             uuid.UUID `json:"device_id"`
+	StoreID              uuid.UUID `json:"store_id"`
+	Profile              *Profile  `json:"profile"`
+}
+
+type PosDeviceUsecase interface {
+	RegisterDevice(ctx context.Context, req *RegisterPosDeviceRequest, actorID uuid.UUID) (*RegisterPosDeviceResponse, error)
+	ListDevices(ctx context.Context, storeID uuid.UUID) ([]PosDevice, error)
+	// RevokeDevice deactivates the device and rejects the tokens issued on it.
+	RevokeDevice(ctx context.Context, id uuid.UUID) (*PosDevice, error)
+	// AuthenticateDevice returns the active device with the credential.
+	AuthenticateDevice(ctx context.Context, credential string) (*PosDevice, error)
+	ListStaff(ctx context.Context, device *PosDevice) ([]PinStaff, error)
+	SetPin(ctx context.Context, userID uuid.UUID, req *SetPinRequest) error
+	PinLogin(ctx context.Context, device *PosDevice, req *PinLoginRequest) (*PinLoginResponse, error)
+	// SwitchUser signs the next user in and then signs the current user of
+	// the device out. A wrong PIN keeps the current user signed in.
+	SwitchUser(ctx context.Context, device *PosDevice, current *JwtCustomClaims, req *PinLoginRequest) (*PinLoginResponse, error)
+}
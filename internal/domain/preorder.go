@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OpeningHours is the window a store accepts pre-orders for on one weekday,
+// in the store timezone. Times are "HH:MM".
+type OpeningHours struct {
+	Weekday  time.Weekday `json:"weekday" binding:"gte=0,lte=6"` // 0 = Sunday
+	OpensAt  string       `json:"opens_at" binding:"required"`
+	ClosesAt string       `json:"closes_at" binding:"required"`
+}
+
+// PreorderSettings controls scheduled orders of a store. Orders are released
+// to the KDS LeadTimeMinutes before ScheduledFor. SlotCapacity 0 = unlimited.
+// A store without opening hours accepts pre-orders at any time.
+type PreorderSettings struct {
+	StoreID         uuid.UUID      `json:"store_id"`
+	LeadTimeMinutes int32          `json:"lead_time_minutes"`
+	SlotMinutes     int32          `json:"slot_minutes"`
+	SlotCapacity    int32          `json:"slot_capacity"`
+	Timezone        string         `json:"timezone"`
+	OpeningHours    []OpeningHours `json:"opening_hours"`
+}
+
+type SetPreorderSettingsRequest struct {
+	StoreID         uuid.UUID      `json:"store_id" binding:"required"`
+	LeadTimeMinutes int32          `json:"lead_time_minutes" binding:"gte=0"`
+	SlotMinutes     int32          `json:"slot_minutes" binding:"required,gt=0"`
+	SlotCapacity    int32          `json:"slot_capacity" binding:"gte=0"`
+	Timezone        string         `json:"timezone" binding:"required"`
+	OpeningHours    []OpeningHours `json:"opening_hours" binding:"dive"`
+}
+
+type PreorderSlot struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Booked    int64     `json:"booked"`
+	Capacity  int32     `json:"capacity"` // 0 = unlimited
+	Available bool      `json:"available"`
+}
+
+type PreorderUsecase interface {
+	SetSettings(ctx context.Context, req *SetPreorderSettingsRequest) (*PreorderSettings, error)
+	GetSettings(ctx context.Context, storeID uuid.UUID) (*PreorderSettings, error)
+	// ListSlots returns the pickup slots of one day (YYYY-MM-DD, store timezone).
+	ListSlots(ctx context.Context, storeID uuid.UUID, date string) ([]PreorderSlot, error)
+	ListUpcoming(ctx context.Context, storeID uuid.UUID) ([]Order, error)
+	// ReleaseDue puts pre-orders whose lead time started on the KDS.
+	ReleaseDue(ctx context.Context) ([]Order, error)
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,35 +18,190 @@ const (
 	RoleStaff      UserRole = "STAFF"
 )
 
+// IsStaffRole reports whether a STORE_OWNER may give the role to the staff
+// of their store.
+func (r UserRole) IsStaffRole() bool {
+	return r == RoleKasir || r == RoleKitchen || r == RoleStaff
+}
+
+// ActingRole picks the role recorded in order history when a user holds
+// several: STORE_OWNER or SUPER_ADMIN win, otherwise the first role.
+func ActingRole(roles []string) string {
+	for _, r := range roles {
+		if r == string(RoleStoreOwner) || r == string(RoleSuperAdmin) {
+			return r
+		}
+	}
+	if len(roles) > 0 {
+		return roles[0]
+	}
+	return ""
+}
+
 type Profile struct {
 	ID        uuid.UUID  `json:"id"`
 	Email     string     `json:"email"`
 	FullName  string     `json:"full_name"`
 	Role      UserRole   `json:"role"`
 	StoreID   *uuid.UUID `json:"store_id,omitempty"`
+	IsActive  bool       `json:"is_active"`
+	HasPin    bool       `json:"has_pin"`
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
+	// Roles are the role assignments of the user, only set on login.
+	Roles []RoleAssignment `json:"roles,omitempty"`
+	// EmailVerified is only set on login. Owners verify their address before
+	// they can open a store.
+	EmailVerified *bool `json:"email_verified,omitempty"`
 }
 
+// RoleAssignment grants a role in the user's own store (SUPER_ADMIN: in every
+// store), or only in StoreID when it is set.
+type RoleAssignment struct {
+	ID         uuid.UUID  `json:"id"`
+	Role       UserRole   `json:"role"`
+	Name       string     `json:"name,omitempty"`
+	StoreID    *uuid.UUID `json:"store_id,omitempty"`
+	AssignedAt time.Time  `json:"assigned_at"`
+}
+
+// RegisterRequest creates an account. SUPER_ADMIN may create any role,
+// STORE_OWNER only staff roles in their own store. Without a token it only
+// creates the first SUPER_ADMIN.
 type RegisterRequest struct {
 	Email    string   `json:"email" binding:"required,email"`
 	Password string   `json:"password" binding:"required,min=6"`
 	FullName string   `json:"full_name" binding:"required"`
-	Role     UserRole `json:"role" binding:"required"`
+	Role     UserRole `json:"role" binding:"required,oneof=SUPER_ADMIN STORE_OWNER KASIR KITCHEN STAFF"`
 	StoreID  string   `json:"store_id"` // Optional
 }
 
+// ClientInfo describes the device a request comes from. Handlers fill it in.
+type ClientInfo struct {
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required"`
+	DeviceName string `json:"device_name" binding:"max=100"` // Shown in the list of logged-in devices
+	ClientInfo
 }
 
+// LoginResponse carries the tokens, or only TwoFactor when the login needs a
+// second step.
 type LoginResponse struct {
-	AccessToken string   `json:"access_token"`
-	Profile     *Profile `json:"profile"`
+	AccessToken           string              `json:"access_token,omitempty"`
+	AccessTokenExpiresAt  time.Time           `json:"access_token_expires_at,omitzero"`
+	RefreshToken          string              `json:"refresh_token,omitempty"`
+	RefreshTokenExpiresAt time.Time           `json:"refresh_token_expires_at,omitzero"`
+	Profile               *Profile            `json:"profile,omitempty"`
+	TwoFactor             *TwoFactorChallenge `json:"two_factor,omitempty"`
+	// RecoveryCodes are set when the login also completed 2FA enrolment.
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// LoginThrottledError refuses a login because of too many attempts, before
+// the password is checked.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool // Too many wrong passwords; an owner can unlock it early
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("account is locked after too many failed logins, try again in %s", e.RetryAfter)
+	}
+	return fmt.Sprintf("too many login attempts, try again in %s", e.RetryAfter)
+}
+
+// RefreshRequest trades a refresh token for a new token pair. The presented
+// refresh token is revoked.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+	ClientInfo
+}
+
+// LogoutRequest ends the device session of the refresh token, or of the
+// access token used for the request when it is empty.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ForgotPasswordRequest asks for a password reset link by email. The answer
+// is the same whether or not the email is registered.
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	ClientInfo
+}
+
+// ResetPasswordRequest sets a new password with the token of a reset link.
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+	ClientInfo
+}
+
+// VerifyEmailRequest confirms an address with the token of a verification
+// link.
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+	ClientInfo
+}
+
+// ChangeEmailRequest sends a verification link to a new address. The account
+// moves to it once the link is opened.
+type ChangeEmailRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"` // Current password
+	ClientInfo
+}
+
+// AuthSession is one logged-in device. Its ID stays the same across refreshes.
+type AuthSession struct {
+	ID              uuid.UUID `json:"id"`
+	DeviceName      string    `json:"device_name,omitempty"`
+	UserAgent       string    `json:"user_agent,omitempty"`
+	IPAddress       string    `json:"ip_address,omitempty"`
+	AuthenticatedAt time.Time `json:"authenticated_at"`
+	LastRefreshedAt time.Time `json:"last_refreshed_at"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	Current         bool      `json:"current"` // The session of the calling token
 }
 
 type AuthUsecase interface {
-	Register(ctx context.Context, req *RegisterRequest) (*Profile, error)
+	// Register takes a nil actor for anonymous requests.
+	Register(ctx context.Context, actor *JwtCustomClaims, req *RegisterRequest) (*Profile, error)
+	// Login answers with a TwoFactor challenge instead of tokens when the user
+	// has 2FA on or a role that requires it.
 	Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error)
+	// LoginTwoFactor completes a login challenge with a code.
+	LoginTwoFactor(ctx context.Context, req *TwoFactorLoginRequest) (*LoginResponse, error)
+	// EnrollTwoFactor sets up an authenticator during a login challenge, for
+	// users whose role requires 2FA.
+	EnrollTwoFactor(ctx context.Context, req *TwoFactorEnrollRequest) (*TOTPEnrollment, error)
+	Refresh(ctx context.Context, req *RefreshRequest) (*LoginResponse, error)
+	// Logout revokes the device session and denylists the calling access token.
+	Logout(ctx context.Context, claims *JwtCustomClaims, req *LogoutRequest) error
+	// LogoutAll signs the user out on every device.
+	LogoutAll(ctx context.Context, userID uuid.UUID) error
+	ListSessions(ctx context.Context, claims *JwtCustomClaims) ([]AuthSession, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	// LogoutUser signs a user out on every device on behalf of a SUPER_ADMIN,
+	// or of a STORE_OWNER of the user's store.
+	LogoutUser(ctx context.Context, actor *JwtCustomClaims, userID uuid.UUID) error
+	// PurgeRefreshTokens deletes expired refresh tokens.
+	PurgeRefreshTokens(ctx context.Context) (int64, error)
+	// ForgotPassword emails a reset link when the email belongs to an active
+	// account.
+	ForgotPassword(ctx context.Context, req *ForgotPasswordRequest) error
+	// ResetPassword also signs the user out everywhere and lifts a lockout.
+	ResetPassword(ctx context.Context, req *ResetPasswordRequest) error
+	// SendVerification emails a new verification link for the user's address.
+	SendVerification(ctx context.Context, userID uuid.UUID) error
+	VerifyEmail(ctx context.Context, req *VerifyEmailRequest) error
+	ChangeEmail(ctx context.Context, userID uuid.UUID, req *ChangeEmailRequest) error
+	// PurgeOneTimeTokens deletes expired reset and verification tokens.
+	PurgeOneTimeTokens(ctx context.Context) (int64, error)
 }
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReservationStatus string
+
+const (
+	ReservationStatusBooked    ReservationStatus = "BOOKED"
+	ReservationStatusSeated    ReservationStatus = "SEATED"
+	ReservationStatusNoShow    ReservationStatus = "NO_SHOW"
+	ReservationStatusCancelled ReservationStatus = "CANCELLED"
+)
+
+// Reservation holds a table from ReservedAt for DurationMinutes. Only BOOKED
+// reservations block the table for other bookings.
+type Reservation struct {
+	ID              uuid.UUID         `json:"id"`
+	StoreID         uuid.UUID         `json:"store_id"`
+	TableID         *uuid.UUID        `json:"table_id,omitempty"`
+	TableName       string            `json:"table_name,omitempty"`
+	CustomerName    string            `json:"customer_name"`
+	CustomerPhone   string            `json:"customer_phone"`
+	CustomerEmail   string            `json:"customer_email,omitempty"`
+	PartySize       int32             `json:"party_size"`
+	ReservedAt      time.Time         `json:"reserved_at"`
+	DurationMinutes int32             `json:"duration_minutes"`
+	EndsAt          time.Time         `json:"ends_at"`
+	Status          ReservationStatus `json:"status"`
+	Note            string            `json:"note,omitempty"`
+	TableSessionID  *uuid.UUID        `json:"table_session_id,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+// ReservationRequest books a table. Without TableID the smallest free table
+// that fits the party is assigned. DurationMinutes 0 = store default.
+type ReservationRequest struct {
+	StoreID         uuid.UUID  `json:"store_id" binding:"required"`
+	TableID         *uuid.UUID `json:"table_id"`
+	CustomerName    string     `json:"customer_name" binding:"required,max=100"`
+	CustomerPhone   string     `json:"customer_phone" binding:"required,max=30"`
+	CustomerEmail   string     `json:"customer_email" binding:"omitempty,email,max=255"`
+	PartySize       int32      `json:"party_size" binding:"required,gt=0"`
+	ReservedAt      time.Time  `json:"reserved_at" binding:"required"`
+	DurationMinutes int32      `json:"duration_minutes" binding:"gte=0,lte=720"`
+	Note            string     `json:"note" binding:"max=500"`
+}
+
+// SeatRequest picks the table to seat the guests at. It defaults to the
+// reserved or offered table.
+type SeatRequest struct {
+	TableID *uuid.UUID `json:"table_id"`
+}
+
+type WaitlistStatus string
+
+const (
+	WaitlistStatusWaiting   WaitlistStatus = "WAITING"
+	WaitlistStatusNotified  WaitlistStatus = "NOTIFIED" // A table is ready
+	WaitlistStatusSeated    WaitlistStatus = "SEATED"
+	WaitlistStatusCancelled WaitlistStatus = "CANCELLED"
+)
+
+type WaitlistEntry struct {
+	ID                uuid.UUID      `json:"id"`
+	StoreID           uuid.UUID      `json:"store_id"`
+	CustomerName      string         `json:"customer_name"`
+	CustomerPhone     string         `json:"customer_phone,omitempty"`
+	PartySize         int32          `json:"party_size"`
+	QuotedWaitMinutes int32          `json:"quoted_wait_minutes"`
+	Status            WaitlistStatus `json:"status"`
+	Note              string         `json:"note,omitempty"`
+	Position          int            `json:"position,omitempty"` // Place in the queue, from 1
+	TableID           *uuid.UUID     `json:"table_id,omitempty"`
+	TableName         string         `json:"table_name,omitempty"`
+	TableSessionID    *uuid.UUID     `json:"table_session_id,omitempty"`
+	NotifiedAt        *time.Time     `json:"notified_at,omitempty"`
+	SeatedAt          *time.Time     `json:"seated_at,omitempty"`
+	CreatedAt         time.Time      `json:"created_at"`
+}
+
+// IsWaiting reports whether the party still waits for a table.
+func (e *WaitlistEntry) IsWaiting() bool {
+	return e.Status == WaitlistStatusWaiting || e.Status == WaitlistStatusNotified
+}
+
+// WaitlistRequest adds a walk-in party. Without QuotedWaitMinutes the wait
+// is estimated from the parties ahead.
+type WaitlistRequest struct {
+	StoreID           uuid.UUID `json:"store_id" binding:"required"`
+	CustomerName      string    `json:"customer_name" binding:"required,max=100"`
+	CustomerPhone     string    `json:"customer_phone" binding:"max=30"`
+	PartySize         int32     `json:"party_size" binding:"required,gt=0"`
+	QuotedWaitMinutes *int32    `json:"quoted_wait_minutes" binding:"omitempty,gte=0"`
+	Note              string    `json:"note" binding:"max=500"`
+}
+
+type NotifyWaitlistRequest struct {
+	TableID uuid.UUID `json:"table_id" binding:"required"`
+}
+
+type ReservationUsecase interface {
+	CreateReservation(ctx context.Context, req *ReservationRequest, actorID uuid.UUID) (*Reservation, error)
+	// ListReservations returns the reservations of one day (YYYY-MM-DD, store timezone).
+	ListReservations(ctx context.Context, storeID uuid.UUID, date string) ([]Reservation, error)
+	GetReservation(ctx context.Context, id uuid.UUID) (*Reservation, error)
+	// UpdateReservation changes a BOOKED reservation; StoreID is ignored.
+	UpdateReservation(ctx context.Context, id uuid.UUID, req *ReservationRequest) (*Reservation, error)
+	CancelReservation(ctx context.Context, id uuid.UUID) (*Reservation, error)
+	MarkNoShow(ctx context.Context, id uuid.UUID) (*Reservation, error)
+	// SeatReservation opens a table session for the guests.
+	SeatReservation(ctx context.Context, id uuid.UUID, req *SeatRequest) (*Reservation, error)
+	// ExpireNoShows marks reservations not seated within grace as NO_SHOW.
+	ExpireNoShows(ctx context.Context, grace time.Duration) (int64, error)
+}
+
+type WaitlistUsecase interface {
+	AddToWaitlist(ctx context.Context, req *WaitlistRequest) (*WaitlistEntry, error)
+	// ListWaitlist returns the waiting and notified parties in queue order.
+	ListWaitlist(ctx context.Context, storeID uuid.UUID) ([]WaitlistEntry, error)
+	// NotifyReady offers a table and publishes a TABLE_READY event.
+	NotifyReady(ctx context.Context, id uuid.UUID, req *NotifyWaitlistRequest) (*WaitlistEntry, error)
+	SeatEntry(ctx context.Context, id uuid.UUID, req *SeatRequest) (*WaitlistEntry, error)
+	CancelEntry(ctx context.Context, id uuid.UUID) (*WaitlistEntry, error)
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SelfOrderSettings controls customer ordering through the table QR code.
+// Without AutoAccept, customer orders wait in NEW for staff confirmation.
+type SelfOrderSettings struct {
+	StoreID    uuid.UUID `json:"store_id"`
+	Enabled    bool      `json:"enabled"`
+	AutoAccept bool      `json:"auto_accept"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type SetSelfOrderSettingsRequest struct {
+	StoreID    uuid.UUID `json:"store_id" binding:"required"`
+	Enabled    bool      `json:"enabled"`
+	AutoAccept bool      `json:"auto_accept"`
+}
+
+type MenuCategory struct {
+	CategoryID *uuid.UUID `json:"category_id,omitempty"`
+	Name       string     `json:"name"`
+	Products   []Product  `json:"products"`
+}
+
+type Menu struct {
+	StoreID    uuid.UUID      `json:"store_id"`
+	TableName  string         `json:"table_name"`
+	Categories []MenuCategory `json:"categories"`
+}
+
+// CustomerOrderRequest is what a customer may send; store, table and order
+// type come from the session.
+type CustomerOrderRequest struct {
+	Note           string                   `json:"note"`
+	Items          []CreateOrderItemRequest `json:"items" binding:"required,min=1,dive"`
+	IdempotencyKey string                   `json:"idempotency_key"`
+}
+
+// SessionBill is the running bill of a table session. VOIDED orders are left out.
+type SessionBill struct {
+	SessionID     uuid.UUID `json:"session_id"`
+	TableName     string    `json:"table_name"`
+	Orders        []Order   `json:"orders"`
+	Subtotal      float64   `json:"subtotal"`
+	ServiceCharge float64   `json:"service_charge"`
+	Tax           float64   `json:"tax"`
+	Discount      float64   `json:"discount"`
+	Total         float64   `json:"total"`
+	Paid          float64   `json:"paid"`
+	Outstanding   float64   `json:"outstanding"`
+}
+
+type SelfOrderUsecase interface {
+	GetMenu(ctx context.Context, session *TableSession) (*Menu, error)
+	PlaceOrder(ctx context.Context, session *TableSession, req *CustomerOrderRequest) (*Order, error)
+	GetBill(ctx context.Context, session *TableSession) (*SessionBill, error)
+
+	GetSettings(ctx context.Context, storeID uuid.UUID) (*SelfOrderSettings, error)
+	SetSettings(ctx context.Context, req *SetSelfOrderSettingsRequest) (*SelfOrderSettings, error)
+}
@@ -7,23 +7,85 @@ import (
 	"github.com/google/uuid"
 )
 
+type SessionStatus string
+
+const (
+	SessionStatusOpen          SessionStatus = "OPEN"
+	SessionStatusBillRequested SessionStatus = "BILL_REQUESTED"
+	SessionStatusClosed        SessionStatus = "CLOSED"
+	SessionStatusMerged        SessionStatus = "MERGED" // Orders moved into MergedIntoID
+)
+
 type TableSession struct {
-	ID        uuid.UUID `json:"id"`
-	TableID   uuid.UUID `json:"table_id"`
-	StoreID   uuid.UUID `json:"store_id"`
-	TableName string    `json:"table_name,omitempty"`
-	Token     string    `json:"token"`
-	ExpiresAt time.Time `json:"expires_at"`
-	IsActive  bool      `json:"is_active"`
+	ID              uuid.UUID     `json:"id"`
+	TableID         uuid.UUID     `json:"table_id"`
+	StoreID         uuid.UUID     `json:"store_id"`
+	TableName       string        `json:"table_name,omitempty"`
+	StoreName       string        `json:"store_name,omitempty"`
+	Token           string        `json:"token,omitempty"`
+	ExpiresAt       time.Time     `json:"expires_at"`
+	IsActive        bool          `json:"is_active"`
+	Status          SessionStatus `json:"status"`
+	BillRequestedAt *time.Time    `json:"bill_requested_at,omitempty"`
+	ClosedAt        *time.Time    `json:"closed_at,omitempty"`
+	MergedIntoID    *uuid.UUID    `json:"merged_into_id,omitempty"`
+	CreatedAt       time.Time     `json:"created_at"`
 }
 
 type CreateSessionRequest struct {
 	TableID uuid.UUID `json:"table_id" binding:"required"`
 }
 
+type MoveSessionRequest struct {
+	TableID uuid.UUID `json:"table_id" binding:"required"`
+}
+
+// MergeSessionsRequest moves all orders of the source session into the
+// target session and closes the source.
+type MergeSessionsRequest struct {
+	SourceSessionID uuid.UUID `json:"source_session_id" binding:"required"`
+	TargetSessionID uuid.UUID `json:"target_session_id" binding:"required"`
+}
+
+type CallWaiterRequest struct {
+	Note string `json:"note" binding:"max=200"`
+}
+
+// WaiterCall is the payload of the WAITER_CALLED event.
+type WaiterCall struct {
+	SessionID uuid.UUID `json:"session_id"`
+	StoreID   uuid.UUID `json:"store_id"`
+	TableID   uuid.UUID `json:"table_id"`
+	TableName string    `json:"table_name"`
+	Note      string    `json:"note,omitempty"`
+	CalledAt  time.Time `json:"called_at"`
+}
+
+// SeatedParty is the booking of guests seated by staff: a reservation or a
+// waitlist entry.
+type SeatedParty struct {
+	ReservationID   *uuid.UUID
+	WaitlistEntryID *uuid.UUID
+}
+
 type SessionUsecase interface {
+	// CreateSession returns the open session of the table, or starts one.
 	CreateSession(ctx context.Context, tableID uuid.UUID) (*TableSession, error)
+	// SeatTable starts a session for guests seated by staff; it fails while
+	// the table is occupied. The party is marked seated in the same
+	// transaction, so no session is left when that fails.
+	SeatTable(ctx context.Context, tableID uuid.UUID, party SeatedParty) (*TableSession, error)
+	// ValidateSession resolves a customer token; expired or closed sessions fail.
 	ValidateSession(ctx context.Context, token string) (*TableSession, error)
+	GetSession(ctx context.Context, sessionID uuid.UUID) (*TableSession, error)
+	RequestBill(ctx context.Context, sessionID uuid.UUID) (*TableSession, error)
+	CallWaiter(ctx context.Context, sessionID uuid.UUID, note string) error
+	// CloseSession frees the table; every order must be paid, voided or moved.
+	CloseSession(ctx context.Context, sessionID, actorID uuid.UUID) (*TableSession, error)
+	MoveSession(ctx context.Context, sessionID uuid.UUID, req *MoveSessionRequest) (*TableSession, error)
+	MergeSessions(ctx context.Context, req *MergeSessionsRequest, actorID uuid.UUID) (*TableSession, error)
+	// ExpireSessions closes expired sessions that have nothing left to pay.
+	ExpireSessions(ctx context.Context) (int64, error)
 }
 
 type SessionRepository interface {
@@ -16,12 +16,14 @@ type Shift struct {
 	OpeningCash  float64    `json:"opening_cash"`
 	ClosingCash  *float64   `json:"closing_cash,omitempty"`
 	ExpectedCash *float64   `json:"expected_cash,omitempty"`
+	TerminalID   *uuid.UUID `json:"terminal_id,omitempty"` // POS device it was opened on
 }
 
 type OpenShiftRequest struct {
-	UserID      uuid.UUID `json:"user_id"` // Usually from token, or implicit
-	StoreID     uuid.UUID `json:"store_id" binding:"required"`
-	OpeningCash float64   `json:"opening_cash" binding:"required,gte=0"`
+	UserID      uuid.UUID  `json:"user_id"` // Usually from token, or implicit
+	StoreID     uuid.UUID  `json:"store_id" binding:"required"`
+	OpeningCash float64    `json:"opening_cash" binding:"required,gte=0"`
+	TerminalID  *uuid.UUID `json:"-"` // From a POS device token
 }
 
 type CloseShiftRequest struct {
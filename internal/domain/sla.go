@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SLATarget is the maximum time an order may stay in one status at a store.
+type SLATarget struct {
+	StoreID       uuid.UUID   `json:"store_id"`
+	Status        OrderStatus `json:"status"`
+	TargetSeconds int32       `json:"target_seconds"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+type SetSLATargetsRequest struct {
+	StoreID uuid.UUID        `json:"store_id" binding:"required"`
+	Targets []SLATargetInput `json:"targets" binding:"required,dive"`
+}
+
+type SLATargetInput struct {
+	Status        OrderStatus `json:"status" binding:"required"`
+	TargetSeconds int32       `json:"target_seconds" binding:"gte=0"` // 0 removes the target
+}
+
+// OverdueOrder is the payload of the ORDER_OVERDUE event.
+type OverdueOrder struct {
+	OrderID         uuid.UUID   `json:"order_id"`
+	StoreID         uuid.UUID   `json:"store_id"`
+	OrderNumber     string      `json:"order_number"`
+	Status          OrderStatus `json:"status"`
+	StatusChangedAt time.Time   `json:"status_changed_at"`
+	TargetSeconds   int32       `json:"target_seconds"`
+	OverdueSeconds  int64       `json:"overdue_seconds"`
+}
+
+type StationPrepTime struct {
+	StationID      uuid.UUID `json:"station_id"`
+	StationName    string    `json:"station_name"`
+	TicketsCount   int64     `json:"tickets_count"`
+	AvgPrepSeconds float64   `json:"avg_prep_seconds"`
+}
+
+type ProductPrepTime struct {
+	ProductID      uuid.UUID `json:"product_id"`
+	ProductName    string    `json:"product_name"`
+	ItemsCount     int64     `json:"items_count"`
+	AvgPrepSeconds float64   `json:"avg_prep_seconds"`
+}
+
+type PrepTimeReport struct {
+	StoreID  uuid.UUID         `json:"store_id"`
+	From     time.Time         `json:"from"`
+	To       time.Time         `json:"to"`
+	Stations []StationPrepTime `json:"stations"`
+	Products []ProductPrepTime `json:"products"`
+}
+
+type SLAUsecase interface {
+	SetTargets(ctx context.Context, req *SetSLATargetsRequest) ([]SLATarget, error)
+	GetTargets(ctx context.Context, storeID uuid.UUID) ([]SLATarget, error)
+	// CheckOverdue publishes ORDER_OVERDUE once per order and status.
+	CheckOverdue(ctx context.Context) ([]OverdueOrder, error)
+	GetPrepTimeReport(ctx context.Context, storeID uuid.UUID, from, to time.Time) (*PrepTimeReport, error)
+}
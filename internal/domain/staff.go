@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// InviteStaffRequest creates a staff account with a temporary password.
+// STORE_OWNER invites into their own store; SUPER_ADMIN must give StoreID.
+type InviteStaffRequest struct {
+	Email    string     `json:"email" binding:"required,email"`
+	FullName string     `json:"full_name" binding:"required,max=100"`
+	Role     UserRole   `json:"role" binding:"required,oneof=STORE_OWNER KASIR KITCHEN STAFF"`
+	StoreID  *uuid.UUID `json:"store_id"`
+	Pin      string     `json:"pin" binding:"omitempty,numeric,min=4,max=6"` // Optional, for POS devices
+}
+
+// StaffCredentials carries a generated password. It is only returned once.
+type StaffCredentials struct {
+	Profile           *Profile `json:"profile"`
+	TemporaryPassword string   `json:"temporary_password"`
+}
+
+type AssignStoreRequest struct {
+	StoreID uuid.UUID `json:"store_id" binding:"required"`
+}
+
+type ChangeRoleRequest struct {
+	Role UserRole `json:"role" binding:"required,oneof=SUPER_ADMIN STORE_OWNER KASIR KITCHEN STAFF"`
+}
+
+// AssignRoleRequest adds a role next to the ones the user holds. STORE_OWNER
+// can only give staff roles, limited to their own store.
+type AssignRoleRequest struct {
+	Role    UserRole   `json:"role" binding:"required,oneof=SUPER_ADMIN STORE_OWNER KASIR KITCHEN STAFF"`
+	StoreID *uuid.UUID `json:"store_id"` // Empty gives the role in every store
+}
+
+type ResetPinRequest struct {
+	Pin string `json:"pin" binding:"required,numeric,min=4,max=6"`
+}
+
+// StaffUsecase manages users on behalf of a SUPER_ADMIN, or of a STORE_OWNER
+// for the staff roles of their own store. Changes that affect what a user may
+// do revoke the user's tokens, so they sign in again.
+type StaffUsecase interface {
+	// ListStaff lists the users of a store; storeID is ignored for STORE_OWNER.
+	ListStaff(ctx context.Context, actor *JwtCustomClaims, storeID *uuid.UUID) ([]Profile, error)
+	InviteStaff(ctx context.Context, actor *JwtCustomClaims, req *InviteStaffRequest) (*StaffCredentials, error)
+	// AssignStore moves a user to a store. STORE_OWNER can only take in users
+	// without a store.
+	AssignStore(ctx context.Context, actor *JwtCustomClaims, userID uuid.UUID, req *AssignStoreRequest) (*Profile, error)
+	// ChangeRole replaces the global roles of the user; roles limited to a
+	// store are kept.
+	ChangeRole(ctx context.Context, actor *JwtCustomClaims, userID uuid.UUID, req *ChangeRoleRequest) (*Profile, error)
+	ListRoles(ctx context.Context, actor *JwtCustomClaims, userID uuid.UUID) ([]RoleAssignment, error)
+	AssignRole(ctx context.Context, actor *JwtCustomClaims, userID uuid.UUID, req *AssignRoleRequest) (*RoleAssignment, error)
+	RevokeRole(ctx context.Context, actor *JwtCustomClaims, userID, assignmentID uuid.UUID) error
+	Deactivate(ctx context.Context, actor *JwtCustomClaims, userID uuid.UUID) (*Profile, error)
+	Reactivate(ctx context.Context, actor *JwtCustomClaims, userID uuid.UUID) (*Profile, error)
+	// ResetPassword also lifts a lockout after too many wrong passwords.
+	ResetPassword(ctx context.Context, actor *JwtCustomClaims, userID uuid.UUID) (*StaffCredentials, error)
+	// ResetPin also lifts a lockout after too many wrong PINs.
+	ResetPin(ctx context.Context, actor *JwtCustomClaims, userID uuid.UUID, req *ResetPinRequest) error
+	// Unlock lifts the lockouts after too many wrong passwords or PINs.
+	Unlock(ctx context.Context, actor *JwtCustomClaims, userID uuid.UUID) error
+	// ResetTwoFactor removes the authenticator and recovery codes of a user.
+	ResetTwoFactor(ctx context.Context, actor *JwtCustomClaims, userID uuid.UUID) error
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,3 +21,9 @@ type CreateStoreRequest struct {
 	Address string `json:"address"`
 	Phone   string `json:"phone"`
 }
+
+type StoreUsecase interface {
+	// CreateStore opens a store. A STORE_OWNER needs a verified email address
+	// and becomes the owner of the new store.
+	CreateStore(ctx context.Context, actor *JwtCustomClaims, req *CreateStoreRequest) (*Store, error)
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// StoreAccess lists the stores a request may touch. It is resolved from the
+// caller's profile by the store context middleware.
+type StoreAccess struct {
+	AllStores bool // SUPER_ADMIN
+	StoreIDs  []uuid.UUID
+}
+
+func (a *StoreAccess) Allows(storeID uuid.UUID) bool {
+	return a.AllStores || slices.Contains(a.StoreIDs, storeID)
+}
+
+type storeAccessKey struct{}
+
+func WithStoreAccess(ctx context.Context, access *StoreAccess) context.Context {
+	return context.WithValue(ctx, storeAccessKey{}, access)
+}
+
+// WithUnscopedAccess lets ctx reach every store. It marks callers that act
+// for no user: background jobs, and the lookup of a device credential, QR
+// code or session token before it tells the store. SUPER_ADMIN gets the same
+// access from the store context middleware.
+func WithUnscopedAccess(ctx context.Context) context.Context {
+	return WithStoreAccess(ctx, &StoreAccess{AllStores: true})
+}
+
+// WithSingleStoreAccess limits ctx to one store. Public endpoints use it once
+// a table session, QR code or device credential tells them the store.
+func WithSingleStoreAccess(ctx context.Context, storeID uuid.UUID) context.Context {
+	return WithStoreAccess(ctx, &StoreAccess{StoreIDs: []uuid.UUID{storeID}})
+}
+
+// StoreAccessFromContext returns nil when no store context was set. Store
+// checks treat that as no access at all.
+func StoreAccessFromContext(ctx context.Context) *StoreAccess {
+	access, _ := ctx.Value(storeAccessKey{}).(*StoreAccess)
+	return access
+}
+
+type StoreAccessUsecase interface {
+	// ResolveStoreAccess returns the caller's own store and the stores where
+	// they hold a role limited to it. Tokens from a PIN login only reach the
+	// device store.
+	ResolveStoreAccess(ctx context.Context, claims *JwtCustomClaims) (*StoreAccess, error)
+}
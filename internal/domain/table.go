@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FloorArea struct {
+	ID        uuid.UUID `json:"id"`
+	StoreID   uuid.UUID `json:"store_id"`
+	Name      string    `json:"name"`
+	SortOrder int32     `json:"sort_order"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type FloorAreaRequest struct {
+	StoreID   uuid.UUID `json:"store_id" binding:"required"`
+	Name      string    `json:"name" binding:"required,max=50"`
+	SortOrder int32     `json:"sort_order"`
+}
+
+// Table is a restaurant table. PosX/PosY place it on the floor plan of its
+// area; QRURL is what the printed QR code encodes.
+type Table struct {
+	ID        uuid.UUID  `json:"id"`
+	StoreID   uuid.UUID  `json:"store_id"`
+	AreaID    *uuid.UUID `json:"area_id,omitempty"`
+	Name      string     `json:"name"`
+	Capacity  int32      `json:"capacity"`
+	PosX      int32      `json:"pos_x"`
+	PosY      int32      `json:"pos_y"`
+	QRCode    string     `json:"qr_code"`
+	QRURL     string     `json:"qr_url"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type TableState string
+
+const (
+	TableStateFree          TableState = "FREE"
+	TableStateOccupied      TableState = "OCCUPIED"
+	TableStateBillRequested TableState = "BILL_REQUESTED"
+	TableStateDirty         TableState = "DIRTY" // Session closed, not cleaned yet
+)
+
+// TableStatus is one tile of the live table board. It is also the payload
+// of the TABLE_STATUS_CHANGED event.
+type TableStatus struct {
+	TableID          uuid.UUID  `json:"table_id"`
+	StoreID          uuid.UUID  `json:"store_id"`
+	AreaID           *uuid.UUID `json:"area_id,omitempty"`
+	Name             string     `json:"name"`
+	Capacity         int32      `json:"capacity"`
+	PosX             int32      `json:"pos_x"`
+	PosY             int32      `json:"pos_y"`
+	State            TableState `json:"state"`
+	SessionID        *uuid.UUID `json:"session_id,omitempty"`
+	SessionStartedAt *time.Time `json:"session_started_at,omitempty"`
+	BillRequestedAt  *time.Time `json:"bill_requested_at,omitempty"`
+	OpenOrders       int64      `json:"open_orders"`
+}
+
+type CreateTableRequest struct {
+	StoreID  uuid.UUID  `json:"store_id" binding:"required"`
+	AreaID   *uuid.UUID `json:"area_id"`
+	Name     string     `json:"name" binding:"required,max=50"`
+	Capacity int32      `json:"capacity" binding:"gte=0"`
+	PosX     int32      `json:"pos_x"`
+	PosY     int32      `json:"pos_y"`
+}
+
+type UpdateTableRequest struct {
+	AreaID   *uuid.UUID `json:"area_id"`
+	Name     string     `json:"name" binding:"required,max=50"`
+	Capacity int32      `json:"capacity" binding:"gte=0"`
+	PosX     int32      `json:"pos_x"`
+	PosY     int32      `json:"pos_y"`
+}
+
+type TableUsecase interface {
+	CreateArea(ctx context.Context, req *FloorAreaRequest) (*FloorArea, error)
+	ListAreas(ctx context.Context, storeID uuid.UUID) ([]FloorArea, error)
+	UpdateArea(ctx context.Context, areaID uuid.UUID, req *FloorAreaRequest) (*FloorArea, error)
+	DeleteArea(ctx context.Context, areaID uuid.UUID) error
+
+	CreateTable(ctx context.Context, req *CreateTableRequest) (*Table, error)
+	GetTable(ctx context.Context, tableID uuid.UUID) (*Table, error)
+	// ListTables returns the tables of a store, optionally of one area only.
+	ListTables(ctx context.Context, storeID uuid.UUID, areaID *uuid.UUID) ([]Table, error)
+	UpdateTable(ctx context.Context, tableID uuid.UUID, req *UpdateTableRequest) (*Table, error)
+	DeleteTable(ctx context.Context, tableID uuid.UUID) error
+
+	QRCodePNG(ctx context.Context, tableID uuid.UUID, scale int) ([]byte, error)
+	QRCodeSVG(ctx context.Context, tableID uuid.UUID, size int) (string, error)
+	// QRSheetPDF lays out the QR codes of a store (or area) on A4 pages.
+	QRSheetPDF(ctx context.Context, storeID uuid.UUID, areaID *uuid.UUID) ([]byte, error)
+	// GetBoard returns the state of every table of a store.
+	GetBoard(ctx context.Context, storeID uuid.UUID) ([]TableStatus, error)
+	// MarkClean turns a DIRTY table back to FREE.
+	MarkClean(ctx context.Context, tableID uuid.UUID) (*TableStatus, error)
+
+	// StartSession is called when a customer scans a table QR code. It joins
+	// the active session of the table or starts a new one.
+	StartSession(ctx context.Context, qrCode string) (*TableSession, error)
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TOTPEnrollment is a new authenticator secret. It only protects logins once
+// it is confirmed with a code.
+type TOTPEnrollment struct {
+	Secret     string `json:"secret"`      // For typing into the app by hand
+	OtpauthURL string `json:"otpauth_url"` // otpauth://totp/...
+	QRCode     string `json:"qr_code"`     // PNG data URI of OtpauthURL
+}
+
+type TwoFactorStatus struct {
+	Enabled           bool  `json:"enabled"`
+	Required          bool  `json:"required"` // A role of the user requires 2FA
+	RecoveryCodesLeft int64 `json:"recovery_codes_left"`
+}
+
+// TwoFactorCodeRequest carries a code from the authenticator app. Where noted
+// a recovery code is accepted too.
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// RecoveryCodes are shown once; only their hashes are stored.
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorChallenge is returned by Login instead of tokens when the user has
+// to give a second factor.
+type TwoFactorChallenge struct {
+	ChallengeToken string    `json:"challenge_token"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	// The user has no authenticator yet but a role requires one: enrol with
+	// the challenge first, the code then also confirms the enrolment.
+	EnrollmentRequired bool `json:"enrollment_required"`
+}
+
+// TwoFactorLoginRequest completes a two-step login with an authenticator or
+// recovery code.
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+	ClientInfo
+}
+
+type TwoFactorEnrollRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+// RoleTwoFactor tells whether users with the role have to use 2FA.
+type RoleTwoFactor struct {
+	Role     UserRole `json:"role"`
+	Name     string   `json:"name"`
+	Required bool     `json:"required"`
+}
+
+type SetRoleTwoFactorRequest struct {
+	Required *bool `json:"required" binding:"required"`
+}
+
+// TwoFactorUsecase manages the TOTP authenticator of the calling user, and
+// for SUPER_ADMIN which roles require one.
+type TwoFactorUsecase interface {
+	Status(ctx context.Context, userID uuid.UUID) (*TwoFactorStatus, error)
+	// Enroll starts, or restarts, setting up an authenticator.
+	Enroll(ctx context.Context, userID uuid.UUID) (*TOTPEnrollment, error)
+	// Confirm switches 2FA on with the first code and returns the recovery
+	// codes.
+	Confirm(ctx context.Context, userID uuid.UUID, req *TwoFactorCodeRequest) (*RecoveryCodes, error)
+	// Disable takes an authenticator or recovery code. It is refused while a
+	// role of the user requires 2FA.
+	Disable(ctx context.Context, userID uuid.UUID, req *TwoFactorCodeRequest) error
+	// RegenerateRecoveryCodes replaces the recovery codes.
+	RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, req *TwoFactorCodeRequest) (*RecoveryCodes, error)
+	ListRoleRequirements(ctx context.Context) ([]RoleTwoFactor, error)
+	SetRoleRequirement(ctx context.Context, role UserRole, req *SetRoleTwoFactorRequest) (*RoleTwoFactor, error)
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type WorkflowSideEffect string
+
+const (
+	SideEffectNotify      WorkflowSideEffect = "NOTIFY"       // Publish ORDER_STATUS_UPDATED to the hub
+	SideEffectDeductStock WorkflowSideEffect = "DEDUCT_STOCK" // Once per order, on the first transition that has it
+)
+
+// WorkflowTransition is one edge of an order workflow graph.
+// An empty AllowedRoles list means any role that can reach the endpoint.
+type WorkflowTransition struct {
+	From         OrderStatus          `json:"from" binding:"required"`
+	To           OrderStatus          `json:"to" binding:"required"`
+	AllowedRoles []UserRole           `json:"allowed_roles"`
+	SideEffects  []WorkflowSideEffect `json:"side_effects"`
+}
+
+// AllowsAnyRole reports whether a user holding roles may take this
+// transition, i.e. whether any of them is allowed. SUPER_ADMIN is always
+// allowed.
+func (t *WorkflowTransition) AllowsAnyRole(roles []string) bool {
+	if len(t.AllowedRoles) == 0 {
+		return true
+	}
+	for _, role := range roles {
+		if role == string(RoleSuperAdmin) {
+			return true
+		}
+		for _, r := range t.AllowedRoles {
+			if string(r) == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t *WorkflowTransition) Has(effect WorkflowSideEffect) bool {
+	for _, e := range t.SideEffects {
+		if e == effect {
+			return true
+		}
+	}
+	return false
+}
+
+// Workflow is the order status graph of a store for one order type.
+// OrderType "" applies to all order types.
+type Workflow struct {
+	StoreID     uuid.UUID            `json:"store_id"`
+	OrderType   string               `json:"order_type"`
+	IsDefault   bool                 `json:"is_default"` // No custom graph configured
+	Transitions []WorkflowTransition `json:"transitions"`
+}
+
+// Find returns the transition from -> to, or nil when the graph has no such edge.
+func (w *Workflow) Find(from, to OrderStatus) *WorkflowTransition {
+	for i := range w.Transitions {
+		if w.Transitions[i].From == from && w.Transitions[i].To == to {
+			return &w.Transitions[i]
+		}
+	}
+	return nil
+}
+
+// SetWorkflowRequest replaces the graph of a store/order type.
+// An empty Transitions list reverts to the default graph.
+type SetWorkflowRequest struct {
+	StoreID     uuid.UUID            `json:"store_id" binding:"required"`
+	OrderType   string               `json:"order_type"`
+	Transitions []WorkflowTransition `json:"transitions" binding:"dive"`
+}
+
+type WorkflowUsecase interface {
+	GetWorkflow(ctx context.Context, storeID uuid.UUID, orderType string) (*Workflow, error)
+	SetWorkflow(ctx context.Context, req *SetWorkflowRequest) (*Workflow, error)
+}
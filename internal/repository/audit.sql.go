@@ -13,20 +13,21 @@ import (
 
 const createAuditLog = `-- name: CreateAuditLog :one
 INSERT INTO audit_logs (
-    user_id, action, entity, entity_id, "before", "after", ip_address
+    user_id, action, entity, entity_id, "before", "after", ip_address, terminal_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
-) RETURNING id, user_id, action, entity, entity_id, details, ip_address, created_at, before, after
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, user_id, action, entity, entity_id, details, ip_address, created_at, before, after, terminal_id
 `
 
 type CreateAuditLogParams struct {
-	UserID    pgtype.UUID `json:"user_id"`
-	Action    string      `json:"action"`
-	Entity    pgtype.Text `json:"entity"`
-	EntityID  pgtype.UUID `json:"entity_id"`
-	Before    []byte      `json:"before"`
-	After     []byte      `json:"after"`
-	IpAddress pgtype.Text `json:"ip_address"`
+	UserID     pgtype.UUID `json:"user_id"`
+	Action     string      `json:"action"`
+	Entity     pgtype.Text `json:"entity"`
+	EntityID   pgtype.UUID `json:"entity_id"`
+	Before     []byte      `json:"before"`
+	After      []byte      `json:"after"`
+	IpAddress  pgtype.Text `json:"ip_address"`
+	TerminalID pgtype.UUID `json:"terminal_id"`
 }
 
 func (q *Queries) CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) (AuditLog, error) {
@@ -38,6 +39,7 @@ func (q *Queries) CreateAuditLog(ctx context.Context, arg CreateAuditLogParams)
 		arg.Before,
 		arg.After,
 		arg.IpAddress,
+		arg.TerminalID,
 	)
 	var i AuditLog
 	err := row.Scan(
@@ -51,12 +53,13 @@ func (q *Queries) CreateAuditLog(ctx context.Context, arg CreateAuditLogParams)
 		&i.CreatedAt,
 		&i.Before,
 		&i.After,
+		&i.TerminalID,
 	)
 	return i, err
 }
 
 const listAuditLogs = `-- name: ListAuditLogs :many
-SELECT id, user_id, action, entity, entity_id, details, ip_address, created_at, before, after FROM audit_logs
+SELECT id, user_id, action, entity, entity_id, details, ip_address, created_at, before, after, terminal_id FROM audit_logs
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
 `
@@ -86,6 +89,7 @@ func (q *Queries) ListAuditLogs(ctx context.Context, arg ListAuditLogsParams) ([
 			&i.CreatedAt,
 			&i.Before,
 			&i.After,
+			&i.TerminalID,
 		); err != nil {
 			return nil, err
 		}
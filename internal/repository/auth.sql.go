@@ -7,14 +7,34 @@ package repository
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countRecentOneTimeTokens = `-- name: CountRecentOneTimeTokens :one
+SELECT COUNT(*) FROM auth.one_time_tokens
+WHERE user_id = $1 AND purpose = $2 AND created_at > $3
+`
+
+type CountRecentOneTimeTokensParams struct {
+	UserID    pgtype.UUID        `json:"user_id"`
+	Purpose   string             `json:"purpose"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) CountRecentOneTimeTokens(ctx context.Context, arg CountRecentOneTimeTokensParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countRecentOneTimeTokens, arg.UserID, arg.Purpose, arg.CreatedAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createAuthUser = `-- name: CreateAuthUser :one
 INSERT INTO auth.users (
     email, encrypted_password
 ) VALUES (
     $1, $2
-) RETURNING id, email, encrypted_password, created_at
+) RETURNING id, email, encrypted_password, created_at, email_verified_at
 `
 
 type CreateAuthUserParams struct {
@@ -30,12 +50,69 @@ func (q *Queries) CreateAuthUser(ctx context.Context, arg CreateAuthUserParams)
 		&i.Email,
 		&i.EncryptedPassword,
 		&i.CreatedAt,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const createOneTimeToken = `-- name: CreateOneTimeToken :one
+INSERT INTO auth.one_time_tokens (
+    user_id, purpose, email, token_hash, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING id, user_id, purpose, email, token_hash, expires_at, used_at, created_at
+`
+
+type CreateOneTimeTokenParams struct {
+	UserID    pgtype.UUID        `json:"user_id"`
+	Purpose   string             `json:"purpose"`
+	Email     string             `json:"email"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreateOneTimeToken(ctx context.Context, arg CreateOneTimeTokenParams) (AuthOneTimeToken, error) {
+	row := q.db.QueryRow(ctx, createOneTimeToken,
+		arg.UserID,
+		arg.Purpose,
+		arg.Email,
+		arg.TokenHash,
+		arg.ExpiresAt,
+	)
+	var i AuthOneTimeToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Purpose,
+		&i.Email,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAuthUser = `-- name: GetAuthUser :one
+SELECT id, email, encrypted_password, created_at, email_verified_at FROM auth.users
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetAuthUser(ctx context.Context, id pgtype.UUID) (AuthUser, error) {
+	row := q.db.QueryRow(ctx, getAuthUser, id)
+	var i AuthUser
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.EncryptedPassword,
+		&i.CreatedAt,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
 
 const getAuthUserByEmail = `-- name: GetAuthUserByEmail :one
-SELECT id, email, encrypted_password, created_at FROM auth.users
+SELECT id, email, encrypted_password, created_at, email_verified_at FROM auth.users
 WHERE email = $1 LIMIT 1
 `
 
@@ -47,6 +124,99 @@ func (q *Queries) GetAuthUserByEmail(ctx context.Context, email string) (AuthUse
 		&i.Email,
 		&i.EncryptedPassword,
 		&i.CreatedAt,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const purgeOneTimeTokens = `-- name: PurgeOneTimeTokens :execrows
+DELETE FROM auth.one_time_tokens
+WHERE expires_at < $1
+`
+
+func (q *Queries) PurgeOneTimeTokens(ctx context.Context, expiresAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeOneTimeTokens, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeOneTimeTokens = `-- name: RevokeOneTimeTokens :exec
+UPDATE auth.one_time_tokens
+SET used_at = NOW()
+WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+`
+
+type RevokeOneTimeTokensParams struct {
+	UserID  pgtype.UUID `json:"user_id"`
+	Purpose string      `json:"purpose"`
+}
+
+// Only the latest link sent for a purpose works.
+func (q *Queries) RevokeOneTimeTokens(ctx context.Context, arg RevokeOneTimeTokensParams) error {
+	_, err := q.db.Exec(ctx, revokeOneTimeTokens, arg.UserID, arg.Purpose)
+	return err
+}
+
+const setAuthUserEmailVerified = `-- name: SetAuthUserEmailVerified :exec
+UPDATE auth.users
+SET email = $2, email_verified_at = NOW()
+WHERE id = $1
+`
+
+type SetAuthUserEmailVerifiedParams struct {
+	ID    pgtype.UUID `json:"id"`
+	Email string      `json:"email"`
+}
+
+// Sets the address too, it changes when a new one is verified.
+func (q *Queries) SetAuthUserEmailVerified(ctx context.Context, arg SetAuthUserEmailVerifiedParams) error {
+	_, err := q.db.Exec(ctx, setAuthUserEmailVerified, arg.ID, arg.Email)
+	return err
+}
+
+const updateAuthUserPassword = `-- name: UpdateAuthUserPassword :exec
+UPDATE auth.users
+SET encrypted_password = $2
+WHERE id = $1
+`
+
+type UpdateAuthUserPasswordParams struct {
+	ID                pgtype.UUID `json:"id"`
+	EncryptedPassword string      `json:"encrypted_password"`
+}
+
+func (q *Queries) UpdateAuthUserPassword(ctx context.Context, arg UpdateAuthUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, updateAuthUserPassword, arg.ID, arg.EncryptedPassword)
+	return err
+}
+
+const useOneTimeToken = `-- name: UseOneTimeToken :one
+UPDATE auth.one_time_tokens
+SET used_at = NOW()
+WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+RETURNING id, user_id, purpose, email, token_hash, expires_at, used_at, created_at
+`
+
+type UseOneTimeTokenParams struct {
+	TokenHash string `json:"token_hash"`
+	Purpose   string `json:"purpose"`
+}
+
+// Marks the token used; no row when it is unknown, used or expired.
+func (q *Queries) UseOneTimeToken(ctx context.Context, arg UseOneTimeTokenParams) (AuthOneTimeToken, error) {
+	row := q.db.QueryRow(ctx, useOneTimeToken, arg.TokenHash, arg.Purpose)
+	var i AuthOneTimeToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Purpose,
+		&i.Email,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
 	)
 	return i, err
 }
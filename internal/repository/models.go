@@ -9,16 +9,28 @@ import (
 )
 
 type AuditLog struct {
+	ID         pgtype.UUID        `json:"id"`
+	UserID     pgtype.UUID        `json:"user_id"`
+	Action     string             `json:"action"`
+	Entity     pgtype.Text        `json:"entity"`
+	EntityID   pgtype.UUID        `json:"entity_id"`
+	Details    []byte             `json:"details"`
+	IpAddress  pgtype.Text        `json:"ip_address"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	Before     []byte             `json:"before"`
+	After      []byte             `json:"after"`
+	TerminalID pgtype.UUID        `json:"terminal_id"`
+}
+
+type AuthOneTimeToken struct {
 	ID        pgtype.UUID        `json:"id"`
 	UserID    pgtype.UUID        `json:"user_id"`
-	Action    string             `json:"action"`
-	Entity    pgtype.Text        `json:"entity"`
-	EntityID  pgtype.UUID        `json:"entity_id"`
-	Details   []byte             `json:"details"`
-	IpAddress pgtype.Text        `json:"ip_address"`
+	Purpose   string             `json:"purpose"`
+	Email     string             `json:"email"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	Before    []byte             `json:"before"`
-	After     []byte             `json:"after"`
 }
 
 type AuthUser struct {
@@ -26,6 +38,7 @@ type AuthUser struct {
 	Email             string             `json:"email"`
 	EncryptedPassword string             `json:"encrypted_password"`
 	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	EmailVerifiedAt   pgtype.Timestamptz `json:"email_verified_at"`
 }
 
 type Category struct {
@@ -35,6 +48,14 @@ type Category struct {
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
+type FloorArea struct {
+	ID        pgtype.UUID        `json:"id"`
+	StoreID   pgtype.UUID        `json:"store_id"`
+	Name      string             `json:"name"`
+	SortOrder int32              `json:"sort_order"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
 type IdempotencyKey struct {
 	Key            string             `json:"key"`
 	ResponseStatus int32              `json:"response_status"`
@@ -42,21 +63,78 @@ type IdempotencyKey struct {
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
 }
 
+type KitchenStation struct {
+	ID        pgtype.UUID        `json:"id"`
+	StoreID   pgtype.UUID        `json:"store_id"`
+	Name      string             `json:"name"`
+	Code      string             `json:"code"`
+	IsDefault bool               `json:"is_default"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type KitchenStationRoute struct {
+	ID         pgtype.UUID        `json:"id"`
+	StationID  pgtype.UUID        `json:"station_id"`
+	ProductID  pgtype.UUID        `json:"product_id"`
+	CategoryID pgtype.UUID        `json:"category_id"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type KitchenTicket struct {
+	ID        pgtype.UUID        `json:"id"`
+	OrderID   pgtype.UUID        `json:"order_id"`
+	StationID pgtype.UUID        `json:"station_id"`
+	Status    string             `json:"status"`
+	BumpedAt  pgtype.Timestamptz `json:"bumped_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	StartedAt pgtype.Timestamptz `json:"started_at"`
+}
+
 type Order struct {
-	ID             pgtype.UUID        `json:"id"`
-	StoreID        pgtype.UUID        `json:"store_id"`
-	TableSessionID pgtype.UUID        `json:"table_session_id"`
-	CashierID      pgtype.UUID        `json:"cashier_id"`
-	OrderNumber    string             `json:"order_number"`
-	Status         string             `json:"status"`
-	PaymentStatus  string             `json:"payment_status"`
-	TotalAmount    pgtype.Numeric     `json:"total_amount"`
-	TaxAmount      pgtype.Numeric     `json:"tax_amount"`
-	DiscountAmount pgtype.Numeric     `json:"discount_amount"`
-	FinalAmount    pgtype.Numeric     `json:"final_amount"`
-	Note           pgtype.Text        `json:"note"`
-	CreatedAt      pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	ID                    pgtype.UUID        `json:"id"`
+	StoreID               pgtype.UUID        `json:"store_id"`
+	TableSessionID        pgtype.UUID        `json:"table_session_id"`
+	CashierID             pgtype.UUID        `json:"cashier_id"`
+	OrderNumber           string             `json:"order_number"`
+	Status                string             `json:"status"`
+	PaymentStatus         string             `json:"payment_status"`
+	TotalAmount           pgtype.Numeric     `json:"total_amount"`
+	TaxAmount             pgtype.Numeric     `json:"tax_amount"`
+	DiscountAmount        pgtype.Numeric     `json:"discount_amount"`
+	FinalAmount           pgtype.Numeric     `json:"final_amount"`
+	Note                  pgtype.Text        `json:"note"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt             pgtype.Timestamptz `json:"updated_at"`
+	AcceptedAt            pgtype.Timestamptz `json:"accepted_at"`
+	CookingAt             pgtype.Timestamptz `json:"cooking_at"`
+	ReadyAt               pgtype.Timestamptz `json:"ready_at"`
+	CompletedAt           pgtype.Timestamptz `json:"completed_at"`
+	StatusChangedAt       pgtype.Timestamptz `json:"status_changed_at"`
+	OverdueNotifiedStatus pgtype.Text        `json:"overdue_notified_status"`
+	Version               int32              `json:"version"`
+	OrderType             string             `json:"order_type"`
+	TableID               pgtype.UUID        `json:"table_id"`
+	CustomerName          pgtype.Text        `json:"customer_name"`
+	CustomerPhone         pgtype.Text        `json:"customer_phone"`
+	DeliveryAddress       pgtype.Text        `json:"delivery_address"`
+	Courier               pgtype.Text        `json:"courier"`
+	ServiceChargeAmount   pgtype.Numeric     `json:"service_charge_amount"`
+	ScheduledFor          pgtype.Timestamptz `json:"scheduled_for"`
+	KitchenReleasedAt     pgtype.Timestamptz `json:"kitchen_released_at"`
+	TerminalID            pgtype.UUID        `json:"terminal_id"`
+}
+
+type OrderDraft struct {
+	ID         pgtype.UUID        `json:"id"`
+	StoreID    pgtype.UUID        `json:"store_id"`
+	CashierID  pgtype.UUID        `json:"cashier_id"`
+	TerminalID string             `json:"terminal_id"`
+	Label      string             `json:"label"`
+	Payload    []byte             `json:"payload"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
 }
 
 type OrderItem struct {
@@ -68,6 +146,42 @@ type OrderItem struct {
 	Quantity     int32          `json:"quantity"`
 	TotalPrice   pgtype.Numeric `json:"total_price"`
 	Note         pgtype.Text    `json:"note"`
+	TicketID     pgtype.UUID    `json:"ticket_id"`
+	Status       string         `json:"status"`
+}
+
+type OrderItemEvent struct {
+	ID          pgtype.UUID        `json:"id"`
+	OrderID     pgtype.UUID        `json:"order_id"`
+	OrderItemID pgtype.UUID        `json:"order_item_id"`
+	EventType   string             `json:"event_type"`
+	FromStatus  pgtype.Text        `json:"from_status"`
+	ToStatus    pgtype.Text        `json:"to_status"`
+	ActorID     pgtype.UUID        `json:"actor_id"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type OrderStatusHistory struct {
+	ID         pgtype.UUID        `json:"id"`
+	OrderID    pgtype.UUID        `json:"order_id"`
+	FromStatus pgtype.Text        `json:"from_status"`
+	ToStatus   string             `json:"to_status"`
+	ActorID    pgtype.UUID        `json:"actor_id"`
+	ActorRole  pgtype.Text        `json:"actor_role"`
+	Source     string             `json:"source"`
+	Reason     pgtype.Text        `json:"reason"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type OrderWorkflowTransition struct {
+	ID           pgtype.UUID        `json:"id"`
+	StoreID      pgtype.UUID        `json:"store_id"`
+	OrderType    string             `json:"order_type"`
+	FromStatus   string             `json:"from_status"`
+	ToStatus     string             `json:"to_status"`
+	AllowedRoles []string           `json:"allowed_roles"`
+	SideEffects  []string           `json:"side_effects"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
 }
 
 type Payment struct {
@@ -82,6 +196,23 @@ type Payment struct {
 	QrisUrl         pgtype.Text        `json:"qris_url"`
 }
 
+type Permission struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+type PosDevice struct {
+	ID             pgtype.UUID        `json:"id"`
+	StoreID        pgtype.UUID        `json:"store_id"`
+	Name           string             `json:"name"`
+	CredentialHash string             `json:"credential_hash"`
+	IsActive       bool               `json:"is_active"`
+	LastSeenAt     pgtype.Timestamptz `json:"last_seen_at"`
+	CreatedBy      pgtype.UUID        `json:"created_by"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	RevokedAt      pgtype.Timestamptz `json:"revoked_at"`
+}
+
 type Product struct {
 	ID          pgtype.UUID        `json:"id"`
 	Name        string             `json:"name"`
@@ -99,20 +230,69 @@ type Product struct {
 }
 
 type Profile struct {
-	ID           pgtype.UUID        `json:"id"`
-	Username     string             `json:"username"`
-	PasswordHash string             `json:"password_hash"`
-	Role         string             `json:"role"`
-	CreatedAt    pgtype.Timestamptz `json:"created_at"`
-	FullName     pgtype.Text        `json:"full_name"`
-	StoreID      pgtype.UUID        `json:"store_id"`
-	Email        pgtype.Text        `json:"email"`
+	ID            pgtype.UUID        `json:"id"`
+	Username      string             `json:"username"`
+	PasswordHash  string             `json:"password_hash"`
+	Role          string             `json:"role"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	FullName      pgtype.Text        `json:"full_name"`
+	StoreID       pgtype.UUID        `json:"store_id"`
+	Email         pgtype.Text        `json:"email"`
+	PinHash       pgtype.Text        `json:"pin_hash"`
+	IsActive      bool               `json:"is_active"`
+	DeactivatedAt pgtype.Timestamptz `json:"deactivated_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+type RefreshToken struct {
+	ID              pgtype.UUID        `json:"id"`
+	UserID          pgtype.UUID        `json:"user_id"`
+	FamilyID        pgtype.UUID        `json:"family_id"`
+	TokenHash       string             `json:"token_hash"`
+	DeviceName      pgtype.Text        `json:"device_name"`
+	UserAgent       pgtype.Text        `json:"user_agent"`
+	IpAddress       pgtype.Text        `json:"ip_address"`
+	AccessJti       pgtype.Text        `json:"access_jti"`
+	AccessExpiresAt pgtype.Timestamptz `json:"access_expires_at"`
+	AuthenticatedAt pgtype.Timestamptz `json:"authenticated_at"`
+	ExpiresAt       pgtype.Timestamptz `json:"expires_at"`
+	RevokedAt       pgtype.Timestamptz `json:"revoked_at"`
+	ReplacedByID    pgtype.UUID        `json:"replaced_by_id"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+}
+
+type Reservation struct {
+	ID              pgtype.UUID        `json:"id"`
+	StoreID         pgtype.UUID        `json:"store_id"`
+	TableID         pgtype.UUID        `json:"table_id"`
+	CustomerName    string             `json:"customer_name"`
+	CustomerPhone   string             `json:"customer_phone"`
+	CustomerEmail   pgtype.Text        `json:"customer_email"`
+	PartySize       int32              `json:"party_size"`
+	ReservedAt      pgtype.Timestamptz `json:"reserved_at"`
+	DurationMinutes int32              `json:"duration_minutes"`
+	Status          string             `json:"status"`
+	Note            pgtype.Text        `json:"note"`
+	TableSessionID  pgtype.UUID        `json:"table_session_id"`
+	CreatedBy       pgtype.UUID        `json:"created_by"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
 }
 
 type Role struct {
-	Code        string      `json:"code"`
-	Name        string      `json:"name"`
-	Description pgtype.Text `json:"description"`
+	Code              string      `json:"code"`
+	Name              string      `json:"name"`
+	Description       pgtype.Text `json:"description"`
+	RequiresTwoFactor bool        `json:"requires_two_factor"`
+}
+
+type RolePermission struct {
+	ID          pgtype.UUID        `json:"id"`
+	RoleCode    string             `json:"role_code"`
+	StoreID     pgtype.UUID        `json:"store_id"`
+	Permissions []string           `json:"permissions"`
+	UpdatedBy   pgtype.UUID        `json:"updated_by"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
 }
 
 type Shift struct {
@@ -124,6 +304,7 @@ type Shift struct {
 	OpeningCash  pgtype.Numeric     `json:"opening_cash"`
 	ClosingCash  pgtype.Numeric     `json:"closing_cash"`
 	ExpectedCash pgtype.Numeric     `json:"expected_cash"`
+	TerminalID   pgtype.UUID        `json:"terminal_id"`
 }
 
 type StockMovement struct {
@@ -144,21 +325,77 @@ type Store struct {
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 }
 
+type StoreOpeningHour struct {
+	StoreID  pgtype.UUID `json:"store_id"`
+	Weekday  int16       `json:"weekday"`
+	OpensAt  pgtype.Time `json:"opens_at"`
+	ClosesAt pgtype.Time `json:"closes_at"`
+}
+
+type StoreOrderTypeCharge struct {
+	StoreID           pgtype.UUID        `json:"store_id"`
+	OrderType         string             `json:"order_type"`
+	TaxRate           pgtype.Numeric     `json:"tax_rate"`
+	ServiceChargeRate pgtype.Numeric     `json:"service_charge_rate"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+}
+
+type StorePreorderSetting struct {
+	StoreID         pgtype.UUID        `json:"store_id"`
+	LeadTimeMinutes int32              `json:"lead_time_minutes"`
+	SlotMinutes     int32              `json:"slot_minutes"`
+	SlotCapacity    int32              `json:"slot_capacity"`
+	Timezone        string             `json:"timezone"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+}
+
+type StoreSelfOrderSetting struct {
+	StoreID    pgtype.UUID        `json:"store_id"`
+	Enabled    bool               `json:"enabled"`
+	AutoAccept bool               `json:"auto_accept"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+type StoreSlaTarget struct {
+	StoreID       pgtype.UUID        `json:"store_id"`
+	Status        string             `json:"status"`
+	TargetSeconds int32              `json:"target_seconds"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Table struct {
-	ID        pgtype.UUID        `json:"id"`
-	StoreID   pgtype.UUID        `json:"store_id"`
-	Name      string             `json:"name"`
-	Capacity  pgtype.Int4        `json:"capacity"`
-	QrCode    pgtype.Text        `json:"qr_code"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ID            pgtype.UUID        `json:"id"`
+	StoreID       pgtype.UUID        `json:"store_id"`
+	Name          string             `json:"name"`
+	Capacity      pgtype.Int4        `json:"capacity"`
+	QrCode        pgtype.Text        `json:"qr_code"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	AreaID        pgtype.UUID        `json:"area_id"`
+	PosX          int32              `json:"pos_x"`
+	PosY          int32              `json:"pos_y"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	NeedsCleaning bool               `json:"needs_cleaning"`
 }
 
 type TableSession struct {
+	ID              pgtype.UUID        `json:"id"`
+	TableID         pgtype.UUID        `json:"table_id"`
+	Token           string             `json:"token"`
+	ExpiresAt       pgtype.Timestamptz `json:"expires_at"`
+	IsActive        pgtype.Bool        `json:"is_active"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	Status          string             `json:"status"`
+	BillRequestedAt pgtype.Timestamptz `json:"bill_requested_at"`
+	ClosedAt        pgtype.Timestamptz `json:"closed_at"`
+	ClosedBy        pgtype.UUID        `json:"closed_by"`
+	MergedIntoID    pgtype.UUID        `json:"merged_into_id"`
+}
+
+type UserRecoveryCode struct {
 	ID        pgtype.UUID        `json:"id"`
-	TableID   pgtype.UUID        `json:"table_id"`
-	Token     string             `json:"token"`
-	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
-	IsActive  pgtype.Bool        `json:"is_active"`
+	UserID    pgtype.UUID        `json:"user_id"`
+	CodeHash  string             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
@@ -166,4 +403,31 @@ type UserRole struct {
 	UserID     pgtype.UUID        `json:"user_id"`
 	RoleCode   string             `json:"role_code"`
 	AssignedAt pgtype.Timestamptz `json:"assigned_at"`
+	ID         pgtype.UUID        `json:"id"`
+	StoreID    pgtype.UUID        `json:"store_id"`
+	AssignedBy pgtype.UUID        `json:"assigned_by"`
+}
+
+type UserTotp struct {
+	UserID       pgtype.UUID        `json:"user_id"`
+	Secret       string             `json:"secret"`
+	ConfirmedAt  pgtype.Timestamptz `json:"confirmed_at"`
+	LastUsedStep int64              `json:"last_used_step"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type WaitlistEntry struct {
+	ID                pgtype.UUID        `json:"id"`
+	StoreID           pgtype.UUID        `json:"store_id"`
+	CustomerName      string             `json:"customer_name"`
+	CustomerPhone     pgtype.Text        `json:"customer_phone"`
+	PartySize         int32              `json:"party_size"`
+	QuotedWaitMinutes int32              `json:"quoted_wait_minutes"`
+	Status            string             `json:"status"`
+	Note              pgtype.Text        `json:"note"`
+	TableID           pgtype.UUID        `json:"table_id"`
+	TableSessionID    pgtype.UUID        `json:"table_session_id"`
+	NotifiedAt        pgtype.Timestamptz `json:"notified_at"`
+	SeatedAt          pgtype.Timestamptz `json:"seated_at"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
 }
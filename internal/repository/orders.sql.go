@@ -14,24 +14,36 @@ import (
 const createOrder = `-- name: CreateOrder :one
 INSERT INTO orders (
     store_id, table_session_id, cashier_id, order_number, 
-    total_amount, tax_amount, discount_amount, final_amount, note, status, payment_status
+    total_amount, tax_amount, discount_amount, final_amount, note, status, payment_status,
+    order_type, table_id, customer_name, customer_phone, delivery_address, courier, service_charge_amount,
+    scheduled_for, kitchen_released_at, terminal_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
-) RETURNING id, store_id, table_session_id, cashier_id, order_number, status, payment_status, total_amount, tax_amount, discount_amount, final_amount, note, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21
+) RETURNING id, store_id, table_session_id, cashier_id, order_number, status, payment_status, total_amount, tax_amount, discount_amount, final_amount, note, created_at, updated_at, accepted_at, cooking_at, ready_at, completed_at, status_changed_at, overdue_notified_status, version, order_type, table_id, customer_name, customer_phone, delivery_address, courier, service_charge_amount, scheduled_for, kitchen_released_at, terminal_id
 `
 
 type CreateOrderParams struct {
-	StoreID        pgtype.UUID    `json:"store_id"`
-	TableSessionID pgtype.UUID    `json:"table_session_id"`
-	CashierID      pgtype.UUID    `json:"cashier_id"`
-	OrderNumber    string         `json:"order_number"`
-	TotalAmount    pgtype.Numeric `json:"total_amount"`
-	TaxAmount      pgtype.Numeric `json:"tax_amount"`
-	DiscountAmount pgtype.Numeric `json:"discount_amount"`
-	FinalAmount    pgtype.Numeric `json:"final_amount"`
-	Note           pgtype.Text    `json:"note"`
-	Status         string         `json:"status"`
-	PaymentStatus  string         `json:"payment_status"`
+	StoreID             pgtype.UUID        `json:"store_id"`
+	TableSessionID      pgtype.UUID        `json:"table_session_id"`
+	CashierID           pgtype.UUID        `json:"cashier_id"`
+	OrderNumber         string             `json:"order_number"`
+	TotalAmount         pgtype.Numeric     `json:"total_amount"`
+	TaxAmount           pgtype.Numeric     `json:"tax_amount"`
+	DiscountAmount      pgtype.Numeric     `json:"discount_amount"`
+	FinalAmount         pgtype.Numeric     `json:"final_amount"`
+	Note                pgtype.Text        `json:"note"`
+	Status              string             `json:"status"`
+	PaymentStatus       string             `json:"payment_status"`
+	OrderType           string             `json:"order_type"`
+	TableID             pgtype.UUID        `json:"table_id"`
+	CustomerName        pgtype.Text        `json:"customer_name"`
+	CustomerPhone       pgtype.Text        `json:"customer_phone"`
+	DeliveryAddress     pgtype.Text        `json:"delivery_address"`
+	Courier             pgtype.Text        `json:"courier"`
+	ServiceChargeAmount pgtype.Numeric     `json:"service_charge_amount"`
+	ScheduledFor        pgtype.Timestamptz `json:"scheduled_for"`
+	KitchenReleasedAt   pgtype.Timestamptz `json:"kitchen_released_at"`
+	TerminalID          pgtype.UUID        `json:"terminal_id"`
 }
 
 func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error) {
@@ -47,6 +59,16 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 		arg.Note,
 		arg.Status,
 		arg.PaymentStatus,
+		arg.OrderType,
+		arg.TableID,
+		arg.CustomerName,
+		arg.CustomerPhone,
+		arg.DeliveryAddress,
+		arg.Courier,
+		arg.ServiceChargeAmount,
+		arg.ScheduledFor,
+		arg.KitchenReleasedAt,
+		arg.TerminalID,
 	)
 	var i Order
 	err := row.Scan(
@@ -64,16 +86,33 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 		&i.Note,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AcceptedAt,
+		&i.CookingAt,
+		&i.ReadyAt,
+		&i.CompletedAt,
+		&i.StatusChangedAt,
+		&i.OverdueNotifiedStatus,
+		&i.Version,
+		&i.OrderType,
+		&i.TableID,
+		&i.CustomerName,
+		&i.CustomerPhone,
+		&i.DeliveryAddress,
+		&i.Courier,
+		&i.ServiceChargeAmount,
+		&i.ScheduledFor,
+		&i.KitchenReleasedAt,
+		&i.TerminalID,
 	)
 	return i, err
 }
 
 const createOrderItem = `-- name: CreateOrderItem :one
 INSERT INTO order_items (
-    order_id, product_id, product_name, product_price, quantity, total_price, note
+    order_id, product_id, product_name, product_price, quantity, total_price, note, ticket_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
-) RETURNING id, order_id, product_id, product_name, product_price, quantity, total_price, note
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, order_id, product_id, product_name, product_price, quantity, total_price, note, ticket_id, status
 `
 
 type CreateOrderItemParams struct {
@@ -84,6 +123,7 @@ type CreateOrderItemParams struct {
 	Quantity     int32          `json:"quantity"`
 	TotalPrice   pgtype.Numeric `json:"total_price"`
 	Note         pgtype.Text    `json:"note"`
+	TicketID     pgtype.UUID    `json:"ticket_id"`
 }
 
 func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (OrderItem, error) {
@@ -95,6 +135,7 @@ func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams
 		arg.Quantity,
 		arg.TotalPrice,
 		arg.Note,
+		arg.TicketID,
 	)
 	var i OrderItem
 	err := row.Scan(
@@ -106,12 +147,14 @@ func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams
 		&i.Quantity,
 		&i.TotalPrice,
 		&i.Note,
+		&i.TicketID,
+		&i.Status,
 	)
 	return i, err
 }
 
 const getOrder = `-- name: GetOrder :one
-SELECT id, store_id, table_session_id, cashier_id, order_number, status, payment_status, total_amount, tax_amount, discount_amount, final_amount, note, created_at, updated_at FROM orders
+SELECT id, store_id, table_session_id, cashier_id, order_number, status, payment_status, total_amount, tax_amount, discount_amount, final_amount, note, created_at, updated_at, accepted_at, cooking_at, ready_at, completed_at, status_changed_at, overdue_notified_status, version, order_type, table_id, customer_name, customer_phone, delivery_address, courier, service_charge_amount, scheduled_for, kitchen_released_at, terminal_id FROM orders
 WHERE id = $1 LIMIT 1
 `
 
@@ -133,12 +176,74 @@ func (q *Queries) GetOrder(ctx context.Context, id pgtype.UUID) (Order, error) {
 		&i.Note,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AcceptedAt,
+		&i.CookingAt,
+		&i.ReadyAt,
+		&i.CompletedAt,
+		&i.StatusChangedAt,
+		&i.OverdueNotifiedStatus,
+		&i.Version,
+		&i.OrderType,
+		&i.TableID,
+		&i.CustomerName,
+		&i.CustomerPhone,
+		&i.DeliveryAddress,
+		&i.Courier,
+		&i.ServiceChargeAmount,
+		&i.ScheduledFor,
+		&i.KitchenReleasedAt,
+		&i.TerminalID,
+	)
+	return i, err
+}
+
+const getOrderForUpdate = `-- name: GetOrderForUpdate :one
+SELECT id, store_id, table_session_id, cashier_id, order_number, status, payment_status, total_amount, tax_amount, discount_amount, final_amount, note, created_at, updated_at, accepted_at, cooking_at, ready_at, completed_at, status_changed_at, overdue_notified_status, version, order_type, table_id, customer_name, customer_phone, delivery_address, courier, service_charge_amount, scheduled_for, kitchen_released_at, terminal_id FROM orders
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetOrderForUpdate(ctx context.Context, id pgtype.UUID) (Order, error) {
+	row := q.db.QueryRow(ctx, getOrderForUpdate, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.TableSessionID,
+		&i.CashierID,
+		&i.OrderNumber,
+		&i.Status,
+		&i.PaymentStatus,
+		&i.TotalAmount,
+		&i.TaxAmount,
+		&i.DiscountAmount,
+		&i.FinalAmount,
+		&i.Note,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AcceptedAt,
+		&i.CookingAt,
+		&i.ReadyAt,
+		&i.CompletedAt,
+		&i.StatusChangedAt,
+		&i.OverdueNotifiedStatus,
+		&i.Version,
+		&i.OrderType,
+		&i.TableID,
+		&i.CustomerName,
+		&i.CustomerPhone,
+		&i.DeliveryAddress,
+		&i.Courier,
+		&i.ServiceChargeAmount,
+		&i.ScheduledFor,
+		&i.KitchenReleasedAt,
+		&i.TerminalID,
 	)
 	return i, err
 }
 
 const getOrdersBySession = `-- name: GetOrdersBySession :many
-SELECT id, store_id, table_session_id, cashier_id, order_number, status, payment_status, total_amount, tax_amount, discount_amount, final_amount, note, created_at, updated_at FROM orders
+SELECT id, store_id, table_session_id, cashier_id, order_number, status, payment_status, total_amount, tax_amount, discount_amount, final_amount, note, created_at, updated_at, accepted_at, cooking_at, ready_at, completed_at, status_changed_at, overdue_notified_status, version, order_type, table_id, customer_name, customer_phone, delivery_address, courier, service_charge_amount, scheduled_for, kitchen_released_at, terminal_id FROM orders
 WHERE table_session_id = $1
 ORDER BY created_at DESC
 `
@@ -167,6 +272,60 @@ func (q *Queries) GetOrdersBySession(ctx context.Context, tableSessionID pgtype.
 			&i.Note,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AcceptedAt,
+			&i.CookingAt,
+			&i.ReadyAt,
+			&i.CompletedAt,
+			&i.StatusChangedAt,
+			&i.OverdueNotifiedStatus,
+			&i.Version,
+			&i.OrderType,
+			&i.TableID,
+			&i.CustomerName,
+			&i.CustomerPhone,
+			&i.DeliveryAddress,
+			&i.Courier,
+			&i.ServiceChargeAmount,
+			&i.ScheduledFor,
+			&i.KitchenReleasedAt,
+			&i.TerminalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrderItems = `-- name: ListOrderItems :many
+SELECT id, order_id, product_id, product_name, product_price, quantity, total_price, note, ticket_id, status FROM order_items
+WHERE order_id = $1
+ORDER BY id
+`
+
+func (q *Queries) ListOrderItems(ctx context.Context, orderID pgtype.UUID) ([]OrderItem, error) {
+	rows, err := q.db.Query(ctx, listOrderItems, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OrderItem
+	for rows.Next() {
+		var i OrderItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.ProductID,
+			&i.ProductName,
+			&i.ProductPrice,
+			&i.Quantity,
+			&i.TotalPrice,
+			&i.Note,
+			&i.TicketID,
+			&i.Status,
 		); err != nil {
 			return nil, err
 		}
@@ -179,7 +338,7 @@ func (q *Queries) GetOrdersBySession(ctx context.Context, tableSessionID pgtype.
 }
 
 const listOrdersByStore = `-- name: ListOrdersByStore :many
-SELECT id, store_id, table_session_id, cashier_id, order_number, status, payment_status, total_amount, tax_amount, discount_amount, final_amount, note, created_at, updated_at FROM orders
+SELECT id, store_id, table_session_id, cashier_id, order_number, status, payment_status, total_amount, tax_amount, discount_amount, final_amount, note, created_at, updated_at, accepted_at, cooking_at, ready_at, completed_at, status_changed_at, overdue_notified_status, version, order_type, table_id, customer_name, customer_phone, delivery_address, courier, service_charge_amount, scheduled_for, kitchen_released_at, terminal_id FROM orders
 WHERE store_id = $1 
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -215,6 +374,23 @@ func (q *Queries) ListOrdersByStore(ctx context.Context, arg ListOrdersByStorePa
 			&i.Note,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AcceptedAt,
+			&i.CookingAt,
+			&i.ReadyAt,
+			&i.CompletedAt,
+			&i.StatusChangedAt,
+			&i.OverdueNotifiedStatus,
+			&i.Version,
+			&i.OrderType,
+			&i.TableID,
+			&i.CustomerName,
+			&i.CustomerPhone,
+			&i.DeliveryAddress,
+			&i.Courier,
+			&i.ServiceChargeAmount,
+			&i.ScheduledFor,
+			&i.KitchenReleasedAt,
+			&i.TerminalID,
 		); err != nil {
 			return nil, err
 		}
@@ -228,18 +404,30 @@ func (q *Queries) ListOrdersByStore(ctx context.Context, arg ListOrdersByStorePa
 
 const updateOrderPaymentStatus = `-- name: UpdateOrderPaymentStatus :one
 UPDATE orders
-SET payment_status = $2, updated_at = NOW()
-WHERE id = $1
-RETURNING id, store_id, table_session_id, cashier_id, order_number, status, payment_status, total_amount, tax_amount, discount_amount, final_amount, note, created_at, updated_at
+SET payment_status = $1,
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = $2
+  AND version = $3
+  AND payment_status = $4
+RETURNING id, store_id, table_session_id, cashier_id, order_number, status, payment_status, total_amount, tax_amount, discount_amount, final_amount, note, created_at, updated_at, accepted_at, cooking_at, ready_at, completed_at, status_changed_at, overdue_notified_status, version, order_type, table_id, customer_name, customer_phone, delivery_address, courier, service_charge_amount, scheduled_for, kitchen_released_at, terminal_id
 `
 
 type UpdateOrderPaymentStatusParams struct {
-	ID            pgtype.UUID `json:"id"`
-	PaymentStatus string      `json:"payment_status"`
+	PaymentStatus         string      `json:"payment_status"`
+	ID                    pgtype.UUID `json:"id"`
+	ExpectedVersion       int32       `json:"expected_version"`
+	ExpectedPaymentStatus string      `json:"expected_payment_status"`
 }
 
+// Compare-and-swap: no row is returned when the order changed since it was read.
 func (q *Queries) UpdateOrderPaymentStatus(ctx context.Context, arg UpdateOrderPaymentStatusParams) (Order, error) {
-	row := q.db.QueryRow(ctx, updateOrderPaymentStatus, arg.ID, arg.PaymentStatus)
+	row := q.db.QueryRow(ctx, updateOrderPaymentStatus,
+		arg.PaymentStatus,
+		arg.ID,
+		arg.ExpectedVersion,
+		arg.ExpectedPaymentStatus,
+	)
 	var i Order
 	err := row.Scan(
 		&i.ID,
@@ -256,24 +444,58 @@ func (q *Queries) UpdateOrderPaymentStatus(ctx context.Context, arg UpdateOrderP
 		&i.Note,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AcceptedAt,
+		&i.CookingAt,
+		&i.ReadyAt,
+		&i.CompletedAt,
+		&i.StatusChangedAt,
+		&i.OverdueNotifiedStatus,
+		&i.Version,
+		&i.OrderType,
+		&i.TableID,
+		&i.CustomerName,
+		&i.CustomerPhone,
+		&i.DeliveryAddress,
+		&i.Courier,
+		&i.ServiceChargeAmount,
+		&i.ScheduledFor,
+		&i.KitchenReleasedAt,
+		&i.TerminalID,
 	)
 	return i, err
 }
 
 const updateOrderStatus = `-- name: UpdateOrderStatus :one
 UPDATE orders
-SET status = $2, updated_at = NOW()
-WHERE id = $1
-RETURNING id, store_id, table_session_id, cashier_id, order_number, status, payment_status, total_amount, tax_amount, discount_amount, final_amount, note, created_at, updated_at
+SET status = $1,
+    version = version + 1,
+    updated_at = NOW(),
+    status_changed_at = NOW(),
+    accepted_at = CASE WHEN $1 = 'ACCEPTED' THEN NOW() ELSE accepted_at END,
+    cooking_at = CASE WHEN $1 = 'COOKING' AND cooking_at IS NULL THEN NOW() ELSE cooking_at END,
+    ready_at = CASE WHEN $1 = 'READY' THEN NOW() ELSE ready_at END,
+    completed_at = CASE WHEN $1 IN ('DONE', 'VOIDED') THEN NOW() ELSE completed_at END
+WHERE id = $2
+  AND version = $3
+  AND status = $4
+RETURNING id, store_id, table_session_id, cashier_id, order_number, status, payment_status, total_amount, tax_amount, discount_amount, final_amount, note, created_at, updated_at, accepted_at, cooking_at, ready_at, completed_at, status_changed_at, overdue_notified_status, version, order_type, table_id, customer_name, customer_phone, delivery_address, courier, service_charge_amount, scheduled_for, kitchen_released_at, terminal_id
 `
 
 type UpdateOrderStatusParams struct {
-	ID     pgtype.UUID `json:"id"`
-	Status string      `json:"status"`
+	Status          string      `json:"status"`
+	ID              pgtype.UUID `json:"id"`
+	ExpectedVersion int32       `json:"expected_version"`
+	ExpectedStatus  string      `json:"expected_status"`
 }
 
+// Compare-and-swap: no row is returned when the order changed since it was read.
 func (q *Queries) UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (Order, error) {
-	row := q.db.QueryRow(ctx, updateOrderStatus, arg.ID, arg.Status)
+	row := q.db.QueryRow(ctx, updateOrderStatus,
+		arg.Status,
+		arg.ID,
+		arg.ExpectedVersion,
+		arg.ExpectedStatus,
+	)
 	var i Order
 	err := row.Scan(
 		&i.ID,
@@ -290,6 +512,23 @@ func (q *Queries) UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusPa
 		&i.Note,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AcceptedAt,
+		&i.CookingAt,
+		&i.ReadyAt,
+		&i.CompletedAt,
+		&i.StatusChangedAt,
+		&i.OverdueNotifiedStatus,
+		&i.Version,
+		&i.OrderType,
+		&i.TableID,
+		&i.CustomerName,
+		&i.CustomerPhone,
+		&i.DeliveryAddress,
+		&i.Courier,
+		&i.ServiceChargeAmount,
+		&i.ScheduledFor,
+		&i.KitchenReleasedAt,
+		&i.TerminalID,
 	)
 	return i, err
 }
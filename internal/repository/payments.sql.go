@@ -72,6 +72,42 @@ func (q *Queries) GetPaymentByOrder(ctx context.Context, orderID pgtype.UUID) (P
 	return i, err
 }
 
+const listPaymentsByOrder = `-- name: ListPaymentsByOrder :many
+SELECT id, order_id, payment_method, amount, reference_number, status, paid_at, created_at, qris_url FROM payments
+WHERE order_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListPaymentsByOrder(ctx context.Context, orderID pgtype.UUID) ([]Payment, error) {
+	rows, err := q.db.Query(ctx, listPaymentsByOrder, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Payment
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.PaymentMethod,
+			&i.Amount,
+			&i.ReferenceNumber,
+			&i.Status,
+			&i.PaidAt,
+			&i.CreatedAt,
+			&i.QrisUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updatePaymentQRIS = `-- name: UpdatePaymentQRIS :exec
 UPDATE payments
 SET qris_url = $2
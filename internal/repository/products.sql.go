@@ -11,6 +11,23 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countStockMovementsByReference = `-- name: CountStockMovementsByReference :one
+SELECT COUNT(*) FROM stock_movements
+WHERE reference_id = $1 AND type = $2
+`
+
+type CountStockMovementsByReferenceParams struct {
+	ReferenceID pgtype.UUID `json:"reference_id"`
+	Type        string      `json:"type"`
+}
+
+func (q *Queries) CountStockMovementsByReference(ctx context.Context, arg CountStockMovementsByReferenceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countStockMovementsByReference, arg.ReferenceID, arg.Type)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createProduct = `-- name: CreateProduct :one
 INSERT INTO products (
     store_id, category_id, name, description, sku, price, stock, image_url, is_available
@@ -62,6 +79,40 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 	return i, err
 }
 
+const createStockMovement = `-- name: CreateStockMovement :one
+INSERT INTO stock_movements (
+    product_id, quantity, type, reference_id
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING id, product_id, quantity, type, reference_id, created_at
+`
+
+type CreateStockMovementParams struct {
+	ProductID   pgtype.UUID `json:"product_id"`
+	Quantity    int32       `json:"quantity"`
+	Type        string      `json:"type"`
+	ReferenceID pgtype.UUID `json:"reference_id"`
+}
+
+func (q *Queries) CreateStockMovement(ctx context.Context, arg CreateStockMovementParams) (StockMovement, error) {
+	row := q.db.QueryRow(ctx, createStockMovement,
+		arg.ProductID,
+		arg.Quantity,
+		arg.Type,
+		arg.ReferenceID,
+	)
+	var i StockMovement
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Quantity,
+		&i.Type,
+		&i.ReferenceID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getProduct = `-- name: GetProduct :one
 SELECT id, name, price, stock, category, created_at, updated_at, store_id, category_id, image_url, is_available, description, sku FROM products
 WHERE id = $1 LIMIT 1
@@ -88,6 +139,67 @@ func (q *Queries) GetProduct(ctx context.Context, id pgtype.UUID) (Product, erro
 	return i, err
 }
 
+const listMenuProducts = `-- name: ListMenuProducts :many
+SELECT p.id, p.name, p.price, p.stock, p.category, p.created_at, p.updated_at, p.store_id, p.category_id, p.image_url, p.is_available, p.description, p.sku, c.name AS category_name
+FROM products p
+LEFT JOIN categories c ON p.category_id = c.id
+WHERE p.store_id = $1 AND p.is_available = TRUE
+ORDER BY c.name NULLS LAST, p.name
+`
+
+type ListMenuProductsRow struct {
+	ID           pgtype.UUID        `json:"id"`
+	Name         string             `json:"name"`
+	Price        pgtype.Numeric     `json:"price"`
+	Stock        int32              `json:"stock"`
+	Category     pgtype.Text        `json:"category"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	StoreID      pgtype.UUID        `json:"store_id"`
+	CategoryID   pgtype.UUID        `json:"category_id"`
+	ImageUrl     pgtype.Text        `json:"image_url"`
+	IsAvailable  pgtype.Bool        `json:"is_available"`
+	Description  pgtype.Text        `json:"description"`
+	Sku          pgtype.Text        `json:"sku"`
+	CategoryName pgtype.Text        `json:"category_name"`
+}
+
+// Available products of a store for the customer menu, grouped by category.
+func (q *Queries) ListMenuProducts(ctx context.Context, storeID pgtype.UUID) ([]ListMenuProductsRow, error) {
+	rows, err := q.db.Query(ctx, listMenuProducts, storeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMenuProductsRow
+	for rows.Next() {
+		var i ListMenuProductsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Price,
+			&i.Stock,
+			&i.Category,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StoreID,
+			&i.CategoryID,
+			&i.ImageUrl,
+			&i.IsAvailable,
+			&i.Description,
+			&i.Sku,
+			&i.CategoryName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listProducts = `-- name: ListProducts :many
 SELECT id, name, price, stock, category, created_at, updated_at, store_id, category_id, image_url, is_available, description, sku FROM products
 WHERE store_id = $1
@@ -16,7 +16,7 @@ INSERT INTO profiles (
     id, email, full_name, role, store_id
 ) VALUES (
     $1, $2, $3, $4, $5
-) RETURNING id, username, password_hash, role, created_at, full_name, store_id, email
+) RETURNING id, username, password_hash, role, created_at, full_name, store_id, email, pin_hash, is_active, deactivated_at, updated_at
 `
 
 type CreateProfileParams struct {
@@ -45,12 +45,16 @@ func (q *Queries) CreateProfile(ctx context.Context, arg CreateProfileParams) (P
 		&i.FullName,
 		&i.StoreID,
 		&i.Email,
+		&i.PinHash,
+		&i.IsActive,
+		&i.DeactivatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
 const getProfile = `-- name: GetProfile :one
-SELECT id, username, password_hash, role, created_at, full_name, store_id, email FROM profiles
+SELECT id, username, password_hash, role, created_at, full_name, store_id, email, pin_hash, is_active, deactivated_at, updated_at FROM profiles
 WHERE id = $1 LIMIT 1
 `
 
@@ -66,12 +70,16 @@ func (q *Queries) GetProfile(ctx context.Context, id pgtype.UUID) (Profile, erro
 		&i.FullName,
 		&i.StoreID,
 		&i.Email,
+		&i.PinHash,
+		&i.IsActive,
+		&i.DeactivatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
 const getProfileByEmail = `-- name: GetProfileByEmail :one
-SELECT id, username, password_hash, role, created_at, full_name, store_id, email FROM profiles
+SELECT id, username, password_hash, role, created_at, full_name, store_id, email, pin_hash, is_active, deactivated_at, updated_at FROM profiles
 WHERE email = $1 LIMIT 1
 `
 
@@ -87,6 +95,212 @@ func (q *Queries) GetProfileByEmail(ctx context.Context, email pgtype.Text) (Pro
 		&i.FullName,
 		&i.StoreID,
 		&i.Email,
+		&i.PinHash,
+		&i.IsActive,
+		&i.DeactivatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listPinStaff = `-- name: ListPinStaff :many
+SELECT id, full_name, role FROM profiles
+WHERE store_id = $1 AND pin_hash IS NOT NULL AND is_active
+ORDER BY full_name
+`
+
+type ListPinStaffRow struct {
+	ID       pgtype.UUID `json:"id"`
+	FullName pgtype.Text `json:"full_name"`
+	Role     string      `json:"role"`
+}
+
+// Staff of a store who can sign in on its POS devices.
+func (q *Queries) ListPinStaff(ctx context.Context, storeID pgtype.UUID) ([]ListPinStaffRow, error) {
+	rows, err := q.db.Query(ctx, listPinStaff, storeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPinStaffRow
+	for rows.Next() {
+		var i ListPinStaffRow
+		if err := rows.Scan(&i.ID, &i.FullName, &i.Role); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStoreProfiles = `-- name: ListStoreProfiles :many
+SELECT id, username, password_hash, role, created_at, full_name, store_id, email, pin_hash, is_active, deactivated_at, updated_at FROM profiles
+WHERE store_id = $1
+ORDER BY full_name
+`
+
+func (q *Queries) ListStoreProfiles(ctx context.Context, storeID pgtype.UUID) ([]Profile, error) {
+	rows, err := q.db.Query(ctx, listStoreProfiles, storeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Profile
+	for rows.Next() {
+		var i Profile
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.PasswordHash,
+			&i.Role,
+			&i.CreatedAt,
+			&i.FullName,
+			&i.StoreID,
+			&i.Email,
+			&i.PinHash,
+			&i.IsActive,
+			&i.DeactivatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setProfileActive = `-- name: SetProfileActive :one
+UPDATE profiles
+SET is_active = $2,
+    deactivated_at = CASE WHEN $2 THEN NULL ELSE NOW() END,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, username, password_hash, role, created_at, full_name, store_id, email, pin_hash, is_active, deactivated_at, updated_at
+`
+
+type SetProfileActiveParams struct {
+	ID       pgtype.UUID `json:"id"`
+	IsActive bool        `json:"is_active"`
+}
+
+func (q *Queries) SetProfileActive(ctx context.Context, arg SetProfileActiveParams) (Profile, error) {
+	row := q.db.QueryRow(ctx, setProfileActive, arg.ID, arg.IsActive)
+	var i Profile
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.Role,
+		&i.CreatedAt,
+		&i.FullName,
+		&i.StoreID,
+		&i.Email,
+		&i.PinHash,
+		&i.IsActive,
+		&i.DeactivatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const setProfilePin = `-- name: SetProfilePin :exec
+UPDATE profiles
+SET pin_hash = $2
+WHERE id = $1
+`
+
+type SetProfilePinParams struct {
+	ID      pgtype.UUID `json:"id"`
+	PinHash pgtype.Text `json:"pin_hash"`
+}
+
+func (q *Queries) SetProfilePin(ctx context.Context, arg SetProfilePinParams) error {
+	_, err := q.db.Exec(ctx, setProfilePin, arg.ID, arg.PinHash)
+	return err
+}
+
+const updateProfileEmail = `-- name: UpdateProfileEmail :exec
+UPDATE profiles
+SET email = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateProfileEmailParams struct {
+	ID    pgtype.UUID `json:"id"`
+	Email pgtype.Text `json:"email"`
+}
+
+func (q *Queries) UpdateProfileEmail(ctx context.Context, arg UpdateProfileEmailParams) error {
+	_, err := q.db.Exec(ctx, updateProfileEmail, arg.ID, arg.Email)
+	return err
+}
+
+const updateProfileRole = `-- name: UpdateProfileRole :one
+UPDATE profiles
+SET role = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, username, password_hash, role, created_at, full_name, store_id, email, pin_hash, is_active, deactivated_at, updated_at
+`
+
+type UpdateProfileRoleParams struct {
+	ID   pgtype.UUID `json:"id"`
+	Role string      `json:"role"`
+}
+
+func (q *Queries) UpdateProfileRole(ctx context.Context, arg UpdateProfileRoleParams) (Profile, error) {
+	row := q.db.QueryRow(ctx, updateProfileRole, arg.ID, arg.Role)
+	var i Profile
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.Role,
+		&i.CreatedAt,
+		&i.FullName,
+		&i.StoreID,
+		&i.Email,
+		&i.PinHash,
+		&i.IsActive,
+		&i.DeactivatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateProfileStore = `-- name: UpdateProfileStore :one
+UPDATE profiles
+SET store_id = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, username, password_hash, role, created_at, full_name, store_id, email, pin_hash, is_active, deactivated_at, updated_at
+`
+
+type UpdateProfileStoreParams struct {
+	ID      pgtype.UUID `json:"id"`
+	StoreID pgtype.UUID `json:"store_id"`
+}
+
+func (q *Queries) UpdateProfileStore(ctx context.Context, arg UpdateProfileStoreParams) (Profile, error) {
+	row := q.db.QueryRow(ctx, updateProfileStore, arg.ID, arg.StoreID)
+	var i Profile
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.Role,
+		&i.CreatedAt,
+		&i.FullName,
+		&i.StoreID,
+		&i.Email,
+		&i.PinHash,
+		&i.IsActive,
+		&i.DeactivatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
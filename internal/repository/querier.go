@@ -11,46 +11,245 @@ import (
 )
 
 type Querier interface {
-	AssignRoleToUser(ctx context.Context, arg AssignRoleToUserParams) error
+	AssignRoleToUser(ctx context.Context, arg AssignRoleToUserParams) (UserRole, error)
+	CancelWaitlistEntry(ctx context.Context, id pgtype.UUID) (WaitlistEntry, error)
+	CloseSession(ctx context.Context, arg CloseSessionParams) (TableSession, error)
 	CloseShift(ctx context.Context, arg CloseShiftParams) (Shift, error)
+	ConfirmUserTotp(ctx context.Context, arg ConfirmUserTotpParams) error
+	CountOpenKitchenTickets(ctx context.Context, orderID pgtype.UUID) (int64, error)
+	CountOpenTicketItems(ctx context.Context, ticketID pgtype.UUID) (int64, error)
+	CountPreordersInSlot(ctx context.Context, arg CountPreordersInSlotParams) (int64, error)
+	CountRecentOneTimeTokens(ctx context.Context, arg CountRecentOneTimeTokensParams) (int64, error)
+	// Codes left.
+	CountRecoveryCodes(ctx context.Context, userID pgtype.UUID) (int64, error)
+	// Booked reservations of the table overlapping [starts_at, ends_at).
+	CountReservationConflicts(ctx context.Context, arg CountReservationConflictsParams) (int64, error)
+	CountStockMovementsByReference(ctx context.Context, arg CountStockMovementsByReferenceParams) (int64, error)
+	// Orders that still block closing the session: not voided and not paid.
+	CountUnsettledSessionOrders(ctx context.Context, tableSessionID pgtype.UUID) (int64, error)
+	CountUsersWithRole(ctx context.Context, roleCode string) (int64, error)
+	CountWaitingParties(ctx context.Context, storeID pgtype.UUID) (int64, error)
 	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) (AuditLog, error)
 	CreateAuthUser(ctx context.Context, arg CreateAuthUserParams) (AuthUser, error)
+	CreateFloorArea(ctx context.Context, arg CreateFloorAreaParams) (FloorArea, error)
 	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	CreateKitchenStation(ctx context.Context, arg CreateKitchenStationParams) (KitchenStation, error)
+	CreateKitchenStationRoute(ctx context.Context, arg CreateKitchenStationRouteParams) (KitchenStationRoute, error)
+	CreateKitchenTicket(ctx context.Context, arg CreateKitchenTicketParams) (KitchenTicket, error)
+	CreateOneTimeToken(ctx context.Context, arg CreateOneTimeTokenParams) (AuthOneTimeToken, error)
+	CreateOpeningHours(ctx context.Context, arg CreateOpeningHoursParams) (StoreOpeningHour, error)
 	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
+	CreateOrderDraft(ctx context.Context, arg CreateOrderDraftParams) (OrderDraft, error)
 	CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (OrderItem, error)
+	CreateOrderItemEvent(ctx context.Context, arg CreateOrderItemEventParams) (OrderItemEvent, error)
+	CreateOrderStatusHistory(ctx context.Context, arg CreateOrderStatusHistoryParams) (OrderStatusHistory, error)
 	CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error)
+	CreatePosDevice(ctx context.Context, arg CreatePosDeviceParams) (PosDevice, error)
 	CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error)
 	CreateProfile(ctx context.Context, arg CreateProfileParams) (Profile, error)
+	CreateRecoveryCodes(ctx context.Context, arg CreateRecoveryCodesParams) error
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
+	CreateReservation(ctx context.Context, arg CreateReservationParams) (Reservation, error)
 	CreateRole(ctx context.Context, arg CreateRoleParams) (Role, error)
+	CreateRolePermissions(ctx context.Context, arg CreateRolePermissionsParams) (RolePermission, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (TableSession, error)
 	CreateShift(ctx context.Context, arg CreateShiftParams) (Shift, error)
+	CreateStockMovement(ctx context.Context, arg CreateStockMovementParams) (StockMovement, error)
 	CreateStore(ctx context.Context, arg CreateStoreParams) (Store, error)
+	CreateTable(ctx context.Context, arg CreateTableParams) (Table, error)
+	// Starts or restarts enrolment; no row is returned when 2FA is already on.
+	CreateUserTotp(ctx context.Context, arg CreateUserTotpParams) (UserTotp, error)
+	CreateWaitlistEntry(ctx context.Context, arg CreateWaitlistEntryParams) (WaitlistEntry, error)
+	CreateWorkflowTransition(ctx context.Context, arg CreateWorkflowTransitionParams) (OrderWorkflowTransition, error)
+	DeleteExpiredOrderDrafts(ctx context.Context) (int64, error)
+	DeleteFloorArea(ctx context.Context, id pgtype.UUID) error
+	DeleteKitchenStationRoute(ctx context.Context, id pgtype.UUID) error
+	DeleteOpeningHours(ctx context.Context, storeID pgtype.UUID) error
+	DeleteOrderDraft(ctx context.Context, id pgtype.UUID) (int64, error)
+	DeleteOrderTypeCharge(ctx context.Context, arg DeleteOrderTypeChargeParams) error
+	DeleteRecoveryCodes(ctx context.Context, userID pgtype.UUID) error
+	DeleteRolePermissions(ctx context.Context, arg DeleteRolePermissionsParams) error
+	DeleteSLATarget(ctx context.Context, arg DeleteSLATargetParams) error
 	DeleteStore(ctx context.Context, id pgtype.UUID) error
+	DeleteTable(ctx context.Context, id pgtype.UUID) error
+	DeleteUserGlobalRoles(ctx context.Context, userID pgtype.UUID) error
+	DeleteUserTotp(ctx context.Context, userID pgtype.UUID) error
+	DeleteWorkflowTransitions(ctx context.Context, arg DeleteWorkflowTransitionsParams) error
+	ExtendSession(ctx context.Context, arg ExtendSessionParams) (TableSession, error)
+	// Smallest table that fits the party and has no overlapping booking.
+	FindFreeTable(ctx context.Context, arg FindFreeTableParams) (Table, error)
+	// The open session of a table, even when its token has expired.
+	GetActiveSessionByTable(ctx context.Context, tableID pgtype.UUID) (TableSession, error)
+	// Expired and closed sessions are not found.
+	GetActiveSessionByToken(ctx context.Context, token string) (GetActiveSessionByTokenRow, error)
+	GetAuthUser(ctx context.Context, id pgtype.UUID) (AuthUser, error)
 	GetAuthUserByEmail(ctx context.Context, email string) (AuthUser, error)
 	GetCurrentShift(ctx context.Context, userID pgtype.UUID) (Shift, error)
+	GetFloorArea(ctx context.Context, id pgtype.UUID) (FloorArea, error)
 	GetIdempotencyKey(ctx context.Context, key string) (IdempotencyKey, error)
+	GetKitchenStation(ctx context.Context, id pgtype.UUID) (KitchenStation, error)
+	GetKitchenStationRoute(ctx context.Context, id pgtype.UUID) (KitchenStationRoute, error)
+	GetKitchenTicket(ctx context.Context, id pgtype.UUID) (KitchenTicket, error)
 	GetOrder(ctx context.Context, id pgtype.UUID) (Order, error)
+	GetOrderDraft(ctx context.Context, id pgtype.UUID) (OrderDraft, error)
+	GetOrderForUpdate(ctx context.Context, id pgtype.UUID) (Order, error)
+	GetOrderItem(ctx context.Context, id pgtype.UUID) (OrderItem, error)
+	GetOrderTypeCharge(ctx context.Context, arg GetOrderTypeChargeParams) (StoreOrderTypeCharge, error)
 	GetOrdersBySession(ctx context.Context, tableSessionID pgtype.UUID) ([]Order, error)
 	GetPaymentByOrder(ctx context.Context, orderID pgtype.UUID) (Payment, error)
+	GetPosDevice(ctx context.Context, id pgtype.UUID) (PosDevice, error)
+	GetPosDeviceByCredential(ctx context.Context, credentialHash string) (PosDevice, error)
+	GetPreorderSettings(ctx context.Context, storeID pgtype.UUID) (StorePreorderSetting, error)
 	GetProduct(ctx context.Context, id pgtype.UUID) (Product, error)
+	// Station ticket timing when the item was routed, otherwise order-level timing.
+	GetProductPrepTimes(ctx context.Context, arg GetProductPrepTimesParams) ([]GetProductPrepTimesRow, error)
 	GetProfile(ctx context.Context, id pgtype.UUID) (Profile, error)
 	GetProfileByEmail(ctx context.Context, email pgtype.Text) (Profile, error)
+	GetRefreshTokenByAccessJTI(ctx context.Context, arg GetRefreshTokenByAccessJTIParams) (RefreshToken, error)
+	GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (RefreshToken, error)
+	GetReservation(ctx context.Context, id pgtype.UUID) (GetReservationRow, error)
+	GetReservationForUpdate(ctx context.Context, id pgtype.UUID) (Reservation, error)
 	GetRole(ctx context.Context, code string) (Role, error)
-	GetSessionByToken(ctx context.Context, token string) (TableSession, error)
+	GetRolePermissions(ctx context.Context, arg GetRolePermissionsParams) (RolePermission, error)
+	GetSalesByOrderType(ctx context.Context, arg GetSalesByOrderTypeParams) ([]GetSalesByOrderTypeRow, error)
+	GetSelfOrderSettings(ctx context.Context, storeID pgtype.UUID) (StoreSelfOrderSetting, error)
+	GetSession(ctx context.Context, id pgtype.UUID) (GetSessionRow, error)
+	GetSessionForUpdate(ctx context.Context, id pgtype.UUID) (TableSession, error)
+	GetShiftForUpdate(ctx context.Context, id pgtype.UUID) (Shift, error)
+	GetStationPrepTimes(ctx context.Context, arg GetStationPrepTimesParams) ([]GetStationPrepTimesRow, error)
 	GetStore(ctx context.Context, id pgtype.UUID) (Store, error)
+	GetTable(ctx context.Context, id pgtype.UUID) (Table, error)
+	GetTableByQRCode(ctx context.Context, qrCode pgtype.Text) (Table, error)
+	GetTableForUpdate(ctx context.Context, id pgtype.UUID) (Table, error)
 	GetTableSessions(ctx context.Context, storeID pgtype.UUID) ([]GetTableSessionsRow, error)
+	GetTableStatus(ctx context.Context, id pgtype.UUID) (GetTableStatusRow, error)
+	// Global roles first, then roles limited to a store.
 	GetUserRoles(ctx context.Context, userID pgtype.UUID) ([]GetUserRolesRow, error)
+	GetUserTotp(ctx context.Context, userID pgtype.UUID) (UserTotp, error)
+	GetWaitlistEntry(ctx context.Context, id pgtype.UUID) (GetWaitlistEntryRow, error)
+	ListActiveKitchenTickets(ctx context.Context, stationID pgtype.UUID) ([]ListActiveKitchenTicketsRow, error)
+	ListActiveRefreshTokens(ctx context.Context, userID pgtype.UUID) ([]RefreshToken, error)
 	ListAuditLogs(ctx context.Context, arg ListAuditLogsParams) ([]AuditLog, error)
+	// Stores without settings use the default 15 minute lead time.
+	ListDuePreorders(ctx context.Context) ([]Order, error)
+	ListFloorAreas(ctx context.Context, storeID pgtype.UUID) ([]FloorArea, error)
+	ListKitchenStationRoutes(ctx context.Context, stationID pgtype.UUID) ([]KitchenStationRoute, error)
+	ListKitchenStations(ctx context.Context, storeID pgtype.UUID) ([]KitchenStation, error)
+	// Available products of a store for the customer menu, grouped by category.
+	ListMenuProducts(ctx context.Context, storeID pgtype.UUID) ([]ListMenuProductsRow, error)
+	ListOpeningHours(ctx context.Context, storeID pgtype.UUID) ([]StoreOpeningHour, error)
+	// Optional filters: cashier and terminal.
+	ListOrderDrafts(ctx context.Context, arg ListOrderDraftsParams) ([]OrderDraft, error)
+	ListOrderItemEvents(ctx context.Context, orderID pgtype.UUID) ([]ListOrderItemEventsRow, error)
+	ListOrderItems(ctx context.Context, orderID pgtype.UUID) ([]OrderItem, error)
+	ListOrderKitchenTickets(ctx context.Context, orderID pgtype.UUID) ([]KitchenTicket, error)
+	ListOrderStatusHistory(ctx context.Context, orderID pgtype.UUID) ([]OrderStatusHistory, error)
+	ListOrderTypeCharges(ctx context.Context, storeID pgtype.UUID) ([]StoreOrderTypeCharge, error)
 	ListOrdersByStore(ctx context.Context, arg ListOrdersByStoreParams) ([]Order, error)
+	// Orders whose current stage exceeded the store target and were not escalated yet.
+	ListOverdueOrders(ctx context.Context) ([]ListOverdueOrdersRow, error)
+	ListPaymentsByOrder(ctx context.Context, orderID pgtype.UUID) ([]Payment, error)
+	ListPermissions(ctx context.Context) ([]Permission, error)
+	// Staff of a store who can sign in on its POS devices.
+	ListPinStaff(ctx context.Context, storeID pgtype.UUID) ([]ListPinStaffRow, error)
+	ListPosDevices(ctx context.Context, storeID pgtype.UUID) ([]PosDevice, error)
+	ListPreorderTimes(ctx context.Context, arg ListPreorderTimesParams) ([]pgtype.Timestamptz, error)
 	ListProducts(ctx context.Context, arg ListProductsParams) ([]Product, error)
+	ListReservations(ctx context.Context, arg ListReservationsParams) ([]ListReservationsRow, error)
+	// Every row, the permission cache holds the whole table.
+	ListRolePermissions(ctx context.Context) ([]RolePermission, error)
 	ListRoles(ctx context.Context) ([]Role, error)
+	ListSLATargets(ctx context.Context, storeID pgtype.UUID) ([]StoreSlaTarget, error)
 	ListShifts(ctx context.Context, arg ListShiftsParams) ([]Shift, error)
+	ListStoreProfiles(ctx context.Context, storeID pgtype.UUID) ([]Profile, error)
 	ListStores(ctx context.Context, arg ListStoresParams) ([]Store, error)
+	ListTableStatuses(ctx context.Context, storeID pgtype.UUID) ([]ListTableStatusesRow, error)
+	ListTables(ctx context.Context, storeID pgtype.UUID) ([]Table, error)
+	ListTicketItems(ctx context.Context, ticketID pgtype.UUID) ([]OrderItem, error)
+	ListUpcomingPreorders(ctx context.Context, storeID pgtype.UUID) ([]Order, error)
+	ListWaitlist(ctx context.Context, storeID pgtype.UUID) ([]ListWaitlistRow, error)
+	// Type specific transitions first, then the ones for all order types.
+	ListWorkflowTransitions(ctx context.Context, arg ListWorkflowTransitionsParams) ([]OrderWorkflowTransition, error)
+	// Expired open sessions, locked so no order can be added while the sweeper
+	// decides. Sessions another transaction holds are left for the next run.
+	LockExpiredSessions(ctx context.Context) ([]TableSession, error)
+	// Serializes capacity checks for one slot until the transaction ends.
+	LockPreorderSlot(ctx context.Context, lockKey string) error
+	// Serializes table assignment of one store until the transaction ends.
+	LockReservations(ctx context.Context, lockKey string) error
+	// Serializes checks on who holds a role until the transaction ends.
+	LockRoleAssignments(ctx context.Context, lockKey string) error
+	// Locks the orders of a session in a fixed order before they are moved.
+	LockSessionOrders(ctx context.Context, tableSessionID pgtype.UUID) ([]pgtype.UUID, error)
+	MarkNoShowReservations(ctx context.Context, reservedAt pgtype.Timestamptz) (int64, error)
+	// Escalation bookkeeping only, so the order version is left untouched.
+	MarkOrderOverdueNotified(ctx context.Context, arg MarkOrderOverdueNotifiedParams) (int64, error)
+	MoveSession(ctx context.Context, arg MoveSessionParams) (TableSession, error)
+	// Bumps the version so clients holding a moved order must reload it.
+	MoveSessionOrders(ctx context.Context, arg MoveSessionOrdersParams) (int64, error)
+	// Notifying again offers another table.
+	NotifyWaitlistEntry(ctx context.Context, arg NotifyWaitlistEntryParams) (WaitlistEntry, error)
+	PurgeIdempotencyKeys(ctx context.Context, createdAt pgtype.Timestamptz) (int64, error)
+	PurgeOneTimeTokens(ctx context.Context, expiresAt pgtype.Timestamptz) (int64, error)
+	PurgeRefreshTokens(ctx context.Context, expiresAt pgtype.Timestamptz) (int64, error)
+	// The NEW stage SLA starts when the kitchen can see the order. Bumps the
+	// version so clients holding the pre-order must reload it.
+	ReleasePreorder(ctx context.Context, id pgtype.UUID) (int64, error)
+	RequestSessionBill(ctx context.Context, id pgtype.UUID) (TableSession, error)
+	// Product route first, then category route, then the store's default station.
+	ResolveKitchenStation(ctx context.Context, arg ResolveKitchenStationParams) (KitchenStation, error)
+	// Only the latest link sent for a purpose works.
+	RevokeOneTimeTokens(ctx context.Context, arg RevokeOneTimeTokensParams) error
+	RevokePosDevice(ctx context.Context, id pgtype.UUID) (PosDevice, error)
+	// Returns the access tokens to denylist.
+	RevokeRefreshTokenFamily(ctx context.Context, arg RevokeRefreshTokenFamilyParams) ([]RevokeRefreshTokenFamilyRow, error)
+	RevokeUserRefreshTokens(ctx context.Context, userID pgtype.UUID) ([]RevokeUserRefreshTokensRow, error)
+	RevokeUserRole(ctx context.Context, arg RevokeUserRoleParams) (UserRole, error)
+	RotateRefreshToken(ctx context.Context, arg RotateRefreshTokenParams) error
+	SeatReservation(ctx context.Context, arg SeatReservationParams) (Reservation, error)
+	SeatWaitlistEntry(ctx context.Context, arg SeatWaitlistEntryParams) (WaitlistEntry, error)
+	// Sets the address too, it changes when a new one is verified.
+	SetAuthUserEmailVerified(ctx context.Context, arg SetAuthUserEmailVerifiedParams) error
+	SetProfileActive(ctx context.Context, arg SetProfileActiveParams) (Profile, error)
+	SetProfilePin(ctx context.Context, arg SetProfilePinParams) error
+	SetReservationStatus(ctx context.Context, arg SetReservationStatusParams) (Reservation, error)
+	SetRoleTwoFactor(ctx context.Context, arg SetRoleTwoFactorParams) (Role, error)
+	// Row-level security only shows rows of these stores (comma separated
+	// UUIDs, '*' for every store) until the connection is scoped again.
+	SetStoreScope(ctx context.Context, storeIds string) error
+	SetTableNeedsCleaning(ctx context.Context, arg SetTableNeedsCleaningParams) error
+	TouchPosDevice(ctx context.Context, id pgtype.UUID) error
+	// Session level lock: held until the connection closes.
+	TryJobLock(ctx context.Context, lockKey string) (bool, error)
+	UpdateAuthUserPassword(ctx context.Context, arg UpdateAuthUserPasswordParams) error
+	UpdateFloorArea(ctx context.Context, arg UpdateFloorAreaParams) (FloorArea, error)
+	UpdateKitchenTicketStatus(ctx context.Context, arg UpdateKitchenTicketStatusParams) (KitchenTicket, error)
+	UpdateOrderDraft(ctx context.Context, arg UpdateOrderDraftParams) (OrderDraft, error)
+	UpdateOrderItemStatus(ctx context.Context, arg UpdateOrderItemStatusParams) (OrderItem, error)
+	// Compare-and-swap: no row is returned when the order changed since it was read.
 	UpdateOrderPaymentStatus(ctx context.Context, arg UpdateOrderPaymentStatusParams) (Order, error)
+	// Compare-and-swap: no row is returned when the order changed since it was read.
 	UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (Order, error)
 	UpdatePaymentQRIS(ctx context.Context, arg UpdatePaymentQRISParams) error
 	UpdateProductStock(ctx context.Context, arg UpdateProductStockParams) (Product, error)
+	UpdateProfileEmail(ctx context.Context, arg UpdateProfileEmailParams) error
+	UpdateProfileRole(ctx context.Context, arg UpdateProfileRoleParams) (Profile, error)
+	UpdateProfileStore(ctx context.Context, arg UpdateProfileStoreParams) (Profile, error)
+	UpdateReservation(ctx context.Context, arg UpdateReservationParams) (Reservation, error)
 	UpdateStore(ctx context.Context, arg UpdateStoreParams) (Store, error)
+	UpdateTable(ctx context.Context, arg UpdateTableParams) (Table, error)
+	UpdateTicketItemsStatus(ctx context.Context, arg UpdateTicketItemsStatusParams) error
+	UpsertOrderTypeCharge(ctx context.Context, arg UpsertOrderTypeChargeParams) (StoreOrderTypeCharge, error)
+	UpsertPreorderSettings(ctx context.Context, arg UpsertPreorderSettingsParams) (StorePreorderSetting, error)
+	UpsertSLATarget(ctx context.Context, arg UpsertSLATargetParams) (StoreSlaTarget, error)
+	UpsertSelfOrderSettings(ctx context.Context, arg UpsertSelfOrderSettingsParams) (StoreSelfOrderSetting, error)
+	// Marks the token used; no row when it is unknown, used or expired.
+	UseOneTimeToken(ctx context.Context, arg UseOneTimeTokenParams) (AuthOneTimeToken, error)
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error)
+	// Compare-and-swap: no row changes when the code was used already.
+	UseTotpStep(ctx context.Context, arg UseTotpStepParams) (int64, error)
+	// Whether any role of the user, global or in a store, requires 2FA.
+	UserRequiresTwoFactor(ctx context.Context, userID pgtype.UUID) (bool, error)
 }
 
 var _ Querier = (*Queries)(nil)
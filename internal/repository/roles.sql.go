@@ -11,25 +11,64 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const assignRoleToUser = `-- name: AssignRoleToUser :exec
-INSERT INTO user_roles (user_id, role_code)
-VALUES ($1, $2)
+const assignRoleToUser = `-- name: AssignRoleToUser :one
+INSERT INTO user_roles (user_id, role_code, store_id, assigned_by)
+VALUES ($1, $2, $3, $4)
+RETURNING user_id, role_code, assigned_at, id, store_id, assigned_by
 `
 
 type AssignRoleToUserParams struct {
-	UserID   pgtype.UUID `json:"user_id"`
-	RoleCode string      `json:"role_code"`
+	UserID     pgtype.UUID `json:"user_id"`
+	RoleCode   string      `json:"role_code"`
+	StoreID    pgtype.UUID `json:"store_id"`
+	AssignedBy pgtype.UUID `json:"assigned_by"`
 }
 
-func (q *Queries) AssignRoleToUser(ctx context.Context, arg AssignRoleToUserParams) error {
-	_, err := q.db.Exec(ctx, assignRoleToUser, arg.UserID, arg.RoleCode)
+func (q *Queries) AssignRoleToUser(ctx context.Context, arg AssignRoleToUserParams) (UserRole, error) {
+	row := q.db.QueryRow(ctx, assignRoleToUser,
+		arg.UserID,
+		arg.RoleCode,
+		arg.StoreID,
+		arg.AssignedBy,
+	)
+	var i UserRole
+	err := row.Scan(
+		&i.UserID,
+		&i.RoleCode,
+		&i.AssignedAt,
+		&i.ID,
+		&i.StoreID,
+		&i.AssignedBy,
+	)
+	return i, err
+}
+
+const countUsersWithRole = `-- name: CountUsersWithRole :one
+SELECT COUNT(DISTINCT user_id) FROM user_roles
+WHERE role_code = $1
+`
+
+func (q *Queries) CountUsersWithRole(ctx context.Context, roleCode string) (int64, error) {
+	row := q.db.QueryRow(ctx, countUsersWithRole, roleCode)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const lockRoleAssignments = `-- name: LockRoleAssignments :exec
+SELECT pg_advisory_xact_lock(hashtext($1::text))
+`
+
+// Serializes checks on who holds a role until the transaction ends.
+func (q *Queries) LockRoleAssignments(ctx context.Context, lockKey string) error {
+	_, err := q.db.Exec(ctx, lockRoleAssignments, lockKey)
 	return err
 }
 
 const createRole = `-- name: CreateRole :one
 INSERT INTO roles (code, name, description)
 VALUES ($1, $2, $3)
-RETURNING code, name, description
+RETURNING code, name, description, requires_two_factor
 `
 
 type CreateRoleParams struct {
@@ -41,34 +80,59 @@ type CreateRoleParams struct {
 func (q *Queries) CreateRole(ctx context.Context, arg CreateRoleParams) (Role, error) {
 	row := q.db.QueryRow(ctx, createRole, arg.Code, arg.Name, arg.Description)
 	var i Role
-	err := row.Scan(&i.Code, &i.Name, &i.Description)
+	err := row.Scan(
+		&i.Code,
+		&i.Name,
+		&i.Description,
+		&i.RequiresTwoFactor,
+	)
 	return i, err
 }
 
+const deleteUserGlobalRoles = `-- name: DeleteUserGlobalRoles :exec
+DELETE FROM user_roles
+WHERE user_id = $1 AND store_id IS NULL
+`
+
+func (q *Queries) DeleteUserGlobalRoles(ctx context.Context, userID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteUserGlobalRoles, userID)
+	return err
+}
+
 const getRole = `-- name: GetRole :one
-SELECT code, name, description FROM roles
+SELECT code, name, description, requires_two_factor FROM roles
 WHERE code = $1 LIMIT 1
 `
 
 func (q *Queries) GetRole(ctx context.Context, code string) (Role, error) {
 	row := q.db.QueryRow(ctx, getRole, code)
 	var i Role
-	err := row.Scan(&i.Code, &i.Name, &i.Description)
+	err := row.Scan(
+		&i.Code,
+		&i.Name,
+		&i.Description,
+		&i.RequiresTwoFactor,
+	)
 	return i, err
 }
 
 const getUserRoles = `-- name: GetUserRoles :many
-SELECT r.code, r.name 
-FROM roles r
-JOIN user_roles ur ON r.code = ur.role_code
+SELECT ur.id, ur.role_code, r.name, ur.store_id, ur.assigned_at
+FROM user_roles ur
+JOIN roles r ON r.code = ur.role_code
 WHERE ur.user_id = $1
+ORDER BY ur.store_id NULLS FIRST, ur.role_code
 `
 
 type GetUserRolesRow struct {
-	Code string `json:"code"`
-	Name string `json:"name"`
+	ID         pgtype.UUID        `json:"id"`
+	RoleCode   string             `json:"role_code"`
+	Name       string             `json:"name"`
+	StoreID    pgtype.UUID        `json:"store_id"`
+	AssignedAt pgtype.Timestamptz `json:"assigned_at"`
 }
 
+// Global roles first, then roles limited to a store.
 func (q *Queries) GetUserRoles(ctx context.Context, userID pgtype.UUID) ([]GetUserRolesRow, error) {
 	rows, err := q.db.Query(ctx, getUserRoles, userID)
 	if err != nil {
@@ -78,7 +142,13 @@ func (q *Queries) GetUserRoles(ctx context.Context, userID pgtype.UUID) ([]GetUs
 	var items []GetUserRolesRow
 	for rows.Next() {
 		var i GetUserRolesRow
-		if err := rows.Scan(&i.Code, &i.Name); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoleCode,
+			&i.Name,
+			&i.StoreID,
+			&i.AssignedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -90,7 +160,7 @@ func (q *Queries) GetUserRoles(ctx context.Context, userID pgtype.UUID) ([]GetUs
 }
 
 const listRoles = `-- name: ListRoles :many
-SELECT code, name, description FROM roles
+SELECT code, name, description, requires_two_factor FROM roles
 ORDER BY name
 `
 
@@ -103,7 +173,12 @@ func (q *Queries) ListRoles(ctx context.Context) ([]Role, error) {
 	var items []Role
 	for rows.Next() {
 		var i Role
-		if err := rows.Scan(&i.Code, &i.Name, &i.Description); err != nil {
+		if err := rows.Scan(
+			&i.Code,
+			&i.Name,
+			&i.Description,
+			&i.RequiresTwoFactor,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -113,3 +188,68 @@ func (q *Queries) ListRoles(ctx context.Context) ([]Role, error) {
 	}
 	return items, nil
 }
+
+const revokeUserRole = `-- name: RevokeUserRole :one
+DELETE FROM user_roles
+WHERE id = $1 AND user_id = $2
+RETURNING user_id, role_code, assigned_at, id, store_id, assigned_by
+`
+
+type RevokeUserRoleParams struct {
+	ID     pgtype.UUID `json:"id"`
+	UserID pgtype.UUID `json:"user_id"`
+}
+
+func (q *Queries) RevokeUserRole(ctx context.Context, arg RevokeUserRoleParams) (UserRole, error) {
+	row := q.db.QueryRow(ctx, revokeUserRole, arg.ID, arg.UserID)
+	var i UserRole
+	err := row.Scan(
+		&i.UserID,
+		&i.RoleCode,
+		&i.AssignedAt,
+		&i.ID,
+		&i.StoreID,
+		&i.AssignedBy,
+	)
+	return i, err
+}
+
+const setRoleTwoFactor = `-- name: SetRoleTwoFactor :one
+UPDATE roles
+SET requires_two_factor = $2
+WHERE code = $1
+RETURNING code, name, description, requires_two_factor
+`
+
+type SetRoleTwoFactorParams struct {
+	Code              string `json:"code"`
+	RequiresTwoFactor bool   `json:"requires_two_factor"`
+}
+
+func (q *Queries) SetRoleTwoFactor(ctx context.Context, arg SetRoleTwoFactorParams) (Role, error) {
+	row := q.db.QueryRow(ctx, setRoleTwoFactor, arg.Code, arg.RequiresTwoFactor)
+	var i Role
+	err := row.Scan(
+		&i.Code,
+		&i.Name,
+		&i.Description,
+		&i.RequiresTwoFactor,
+	)
+	return i, err
+}
+
+const userRequiresTwoFactor = `-- name: UserRequiresTwoFactor :one
+SELECT EXISTS (
+    SELECT 1 FROM user_roles ur
+    JOIN roles r ON r.code = ur.role_code
+    WHERE ur.user_id = $1 AND r.requires_two_factor
+)
+`
+
+// Whether any role of the user, global or in a store, requires 2FA.
+func (q *Queries) UserRequiresTwoFactor(ctx context.Context, userID pgtype.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, userRequiresTwoFactor, userID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
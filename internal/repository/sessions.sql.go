@@ -11,12 +11,63 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const closeSession = `-- name: CloseSession :one
+UPDATE table_sessions
+SET is_active = FALSE, status = $2, closed_at = NOW(), closed_by = $3, merged_into_id = $4
+WHERE id = $1 AND is_active = TRUE
+RETURNING id, table_id, token, expires_at, is_active, created_at, status, bill_requested_at, closed_at, closed_by, merged_into_id
+`
+
+type CloseSessionParams struct {
+	ID           pgtype.UUID `json:"id"`
+	Status       string      `json:"status"`
+	ClosedBy     pgtype.UUID `json:"closed_by"`
+	MergedIntoID pgtype.UUID `json:"merged_into_id"`
+}
+
+func (q *Queries) CloseSession(ctx context.Context, arg CloseSessionParams) (TableSession, error) {
+	row := q.db.QueryRow(ctx, closeSession,
+		arg.ID,
+		arg.Status,
+		arg.ClosedBy,
+		arg.MergedIntoID,
+	)
+	var i TableSession
+	err := row.Scan(
+		&i.ID,
+		&i.TableID,
+		&i.Token,
+		&i.ExpiresAt,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.Status,
+		&i.BillRequestedAt,
+		&i.ClosedAt,
+		&i.ClosedBy,
+		&i.MergedIntoID,
+	)
+	return i, err
+}
+
+const countUnsettledSessionOrders = `-- name: CountUnsettledSessionOrders :one
+SELECT COUNT(*) FROM orders
+WHERE table_session_id = $1 AND status <> 'VOIDED' AND payment_status <> 'PAID'
+`
+
+// Orders that still block closing the session: not voided and not paid.
+func (q *Queries) CountUnsettledSessionOrders(ctx context.Context, tableSessionID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countUnsettledSessionOrders, tableSessionID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO table_sessions (
     table_id, token, expires_at
 ) VALUES (
     $1, $2, $3
-) RETURNING id, table_id, token, expires_at, is_active, created_at
+) RETURNING id, table_id, token, expires_at, is_active, created_at, status, bill_requested_at, closed_at, closed_by, merged_into_id
 `
 
 type CreateSessionParams struct {
@@ -35,17 +86,176 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (T
 		&i.ExpiresAt,
 		&i.IsActive,
 		&i.CreatedAt,
+		&i.Status,
+		&i.BillRequestedAt,
+		&i.ClosedAt,
+		&i.ClosedBy,
+		&i.MergedIntoID,
+	)
+	return i, err
+}
+
+const extendSession = `-- name: ExtendSession :one
+UPDATE table_sessions
+SET expires_at = $2
+WHERE id = $1
+RETURNING id, table_id, token, expires_at, is_active, created_at, status, bill_requested_at, closed_at, closed_by, merged_into_id
+`
+
+type ExtendSessionParams struct {
+	ID        pgtype.UUID        `json:"id"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) ExtendSession(ctx context.Context, arg ExtendSessionParams) (TableSession, error) {
+	row := q.db.QueryRow(ctx, extendSession, arg.ID, arg.ExpiresAt)
+	var i TableSession
+	err := row.Scan(
+		&i.ID,
+		&i.TableID,
+		&i.Token,
+		&i.ExpiresAt,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.Status,
+		&i.BillRequestedAt,
+		&i.ClosedAt,
+		&i.ClosedBy,
+		&i.MergedIntoID,
+	)
+	return i, err
+}
+
+const getActiveSessionByTable = `-- name: GetActiveSessionByTable :one
+SELECT id, table_id, token, expires_at, is_active, created_at, status, bill_requested_at, closed_at, closed_by, merged_into_id FROM table_sessions
+WHERE table_id = $1 AND is_active = TRUE
+LIMIT 1
+`
+
+// The open session of a table, even when its token has expired.
+func (q *Queries) GetActiveSessionByTable(ctx context.Context, tableID pgtype.UUID) (TableSession, error) {
+	row := q.db.QueryRow(ctx, getActiveSessionByTable, tableID)
+	var i TableSession
+	err := row.Scan(
+		&i.ID,
+		&i.TableID,
+		&i.Token,
+		&i.ExpiresAt,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.Status,
+		&i.BillRequestedAt,
+		&i.ClosedAt,
+		&i.ClosedBy,
+		&i.MergedIntoID,
+	)
+	return i, err
+}
+
+const getActiveSessionByToken = `-- name: GetActiveSessionByToken :one
+SELECT ts.id, ts.table_id, ts.token, ts.expires_at, ts.is_active, ts.created_at, ts.status, ts.bill_requested_at, ts.closed_at, ts.closed_by, ts.merged_into_id, t.store_id, t.name AS table_name, s.name AS store_name
+FROM table_sessions ts
+JOIN tables t ON ts.table_id = t.id
+JOIN stores s ON t.store_id = s.id
+WHERE ts.token = $1 AND ts.is_active = TRUE AND ts.expires_at > NOW()
+LIMIT 1
+`
+
+type GetActiveSessionByTokenRow struct {
+	ID              pgtype.UUID        `json:"id"`
+	TableID         pgtype.UUID        `json:"table_id"`
+	Token           string             `json:"token"`
+	ExpiresAt       pgtype.Timestamptz `json:"expires_at"`
+	IsActive        pgtype.Bool        `json:"is_active"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	Status          string             `json:"status"`
+	BillRequestedAt pgtype.Timestamptz `json:"bill_requested_at"`
+	ClosedAt        pgtype.Timestamptz `json:"closed_at"`
+	ClosedBy        pgtype.UUID        `json:"closed_by"`
+	MergedIntoID    pgtype.UUID        `json:"merged_into_id"`
+	StoreID         pgtype.UUID        `json:"store_id"`
+	TableName       string             `json:"table_name"`
+	StoreName       string             `json:"store_name"`
+}
+
+// Expired and closed sessions are not found.
+func (q *Queries) GetActiveSessionByToken(ctx context.Context, token string) (GetActiveSessionByTokenRow, error) {
+	row := q.db.QueryRow(ctx, getActiveSessionByToken, token)
+	var i GetActiveSessionByTokenRow
+	err := row.Scan(
+		&i.ID,
+		&i.TableID,
+		&i.Token,
+		&i.ExpiresAt,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.Status,
+		&i.BillRequestedAt,
+		&i.ClosedAt,
+		&i.ClosedBy,
+		&i.MergedIntoID,
+		&i.StoreID,
+		&i.TableName,
+		&i.StoreName,
+	)
+	return i, err
+}
+
+const getSession = `-- name: GetSession :one
+SELECT ts.id, ts.table_id, ts.token, ts.expires_at, ts.is_active, ts.created_at, ts.status, ts.bill_requested_at, ts.closed_at, ts.closed_by, ts.merged_into_id, t.store_id, t.name AS table_name, s.name AS store_name
+FROM table_sessions ts
+JOIN tables t ON ts.table_id = t.id
+JOIN stores s ON t.store_id = s.id
+WHERE ts.id = $1
+`
+
+type GetSessionRow struct {
+	ID              pgtype.UUID        `json:"id"`
+	TableID         pgtype.UUID        `json:"table_id"`
+	Token           string             `json:"token"`
+	ExpiresAt       pgtype.Timestamptz `json:"expires_at"`
+	IsActive        pgtype.Bool        `json:"is_active"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	Status          string             `json:"status"`
+	BillRequestedAt pgtype.Timestamptz `json:"bill_requested_at"`
+	ClosedAt        pgtype.Timestamptz `json:"closed_at"`
+	ClosedBy        pgtype.UUID        `json:"closed_by"`
+	MergedIntoID    pgtype.UUID        `json:"merged_into_id"`
+	StoreID         pgtype.UUID        `json:"store_id"`
+	TableName       string             `json:"table_name"`
+	StoreName       string             `json:"store_name"`
+}
+
+func (q *Queries) GetSession(ctx context.Context, id pgtype.UUID) (GetSessionRow, error) {
+	row := q.db.QueryRow(ctx, getSession, id)
+	var i GetSessionRow
+	err := row.Scan(
+		&i.ID,
+		&i.TableID,
+		&i.Token,
+		&i.ExpiresAt,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.Status,
+		&i.BillRequestedAt,
+		&i.ClosedAt,
+		&i.ClosedBy,
+		&i.MergedIntoID,
+		&i.StoreID,
+		&i.TableName,
+		&i.StoreName,
 	)
 	return i, err
 }
 
-const getSessionByToken = `-- name: GetSessionByToken :one
-SELECT id, table_id, token, expires_at, is_active, created_at FROM table_sessions
-WHERE token = $1 LIMIT 1
+const getSessionForUpdate = `-- name: GetSessionForUpdate :one
+SELECT id, table_id, token, expires_at, is_active, created_at, status, bill_requested_at, closed_at, closed_by, merged_into_id FROM table_sessions
+WHERE id = $1
+FOR UPDATE
 `
 
-func (q *Queries) GetSessionByToken(ctx context.Context, token string) (TableSession, error) {
-	row := q.db.QueryRow(ctx, getSessionByToken, token)
+func (q *Queries) GetSessionForUpdate(ctx context.Context, id pgtype.UUID) (TableSession, error) {
+	row := q.db.QueryRow(ctx, getSessionForUpdate, id)
 	var i TableSession
 	err := row.Scan(
 		&i.ID,
@@ -54,12 +264,17 @@ func (q *Queries) GetSessionByToken(ctx context.Context, token string) (TableSes
 		&i.ExpiresAt,
 		&i.IsActive,
 		&i.CreatedAt,
+		&i.Status,
+		&i.BillRequestedAt,
+		&i.ClosedAt,
+		&i.ClosedBy,
+		&i.MergedIntoID,
 	)
 	return i, err
 }
 
 const getTableSessions = `-- name: GetTableSessions :many
-SELECT ts.id, ts.table_id, ts.token, ts.expires_at, ts.is_active, ts.created_at, t.store_id, t.name as table_name 
+SELECT ts.id, ts.table_id, ts.token, ts.expires_at, ts.is_active, ts.created_at, ts.status, ts.bill_requested_at, ts.closed_at, ts.closed_by, ts.merged_into_id, t.store_id, t.name as table_name 
 FROM table_sessions ts
 JOIN tables t ON ts.table_id = t.id
 WHERE t.store_id = $1
@@ -67,14 +282,19 @@ ORDER BY ts.created_at DESC
 `
 
 type GetTableSessionsRow struct {
-	ID        pgtype.UUID        `json:"id"`
-	TableID   pgtype.UUID        `json:"table_id"`
-	Token     string             `json:"token"`
-	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
-	IsActive  pgtype.Bool        `json:"is_active"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	StoreID   pgtype.UUID        `json:"store_id"`
-	TableName string             `json:"table_name"`
+	ID              pgtype.UUID        `json:"id"`
+	TableID         pgtype.UUID        `json:"table_id"`
+	Token           string             `json:"token"`
+	ExpiresAt       pgtype.Timestamptz `json:"expires_at"`
+	IsActive        pgtype.Bool        `json:"is_active"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	Status          string             `json:"status"`
+	BillRequestedAt pgtype.Timestamptz `json:"bill_requested_at"`
+	ClosedAt        pgtype.Timestamptz `json:"closed_at"`
+	ClosedBy        pgtype.UUID        `json:"closed_by"`
+	MergedIntoID    pgtype.UUID        `json:"merged_into_id"`
+	StoreID         pgtype.UUID        `json:"store_id"`
+	TableName       string             `json:"table_name"`
 }
 
 func (q *Queries) GetTableSessions(ctx context.Context, storeID pgtype.UUID) ([]GetTableSessionsRow, error) {
@@ -93,6 +313,11 @@ func (q *Queries) GetTableSessions(ctx context.Context, storeID pgtype.UUID) ([]
 			&i.ExpiresAt,
 			&i.IsActive,
 			&i.CreatedAt,
+			&i.Status,
+			&i.BillRequestedAt,
+			&i.ClosedAt,
+			&i.ClosedBy,
+			&i.MergedIntoID,
 			&i.StoreID,
 			&i.TableName,
 		); err != nil {
@@ -105,3 +330,153 @@ func (q *Queries) GetTableSessions(ctx context.Context, storeID pgtype.UUID) ([]
 	}
 	return items, nil
 }
+
+const moveSession = `-- name: MoveSession :one
+UPDATE table_sessions
+SET table_id = $2
+WHERE id = $1
+RETURNING id, table_id, token, expires_at, is_active, created_at, status, bill_requested_at, closed_at, closed_by, merged_into_id
+`
+
+type MoveSessionParams struct {
+	ID      pgtype.UUID `json:"id"`
+	TableID pgtype.UUID `json:"table_id"`
+}
+
+func (q *Queries) MoveSession(ctx context.Context, arg MoveSessionParams) (TableSession, error) {
+	row := q.db.QueryRow(ctx, moveSession, arg.ID, arg.TableID)
+	var i TableSession
+	err := row.Scan(
+		&i.ID,
+		&i.TableID,
+		&i.Token,
+		&i.ExpiresAt,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.Status,
+		&i.BillRequestedAt,
+		&i.ClosedAt,
+		&i.ClosedBy,
+		&i.MergedIntoID,
+	)
+	return i, err
+}
+
+const lockExpiredSessions = `-- name: LockExpiredSessions :many
+SELECT id, table_id, token, expires_at, is_active, created_at, status, bill_requested_at, closed_at, closed_by, merged_into_id FROM table_sessions
+WHERE is_active = TRUE AND expires_at <= NOW()
+ORDER BY id
+FOR UPDATE SKIP LOCKED
+`
+
+// Expired open sessions, locked so no order can be added while the sweeper
+// decides. Sessions another transaction holds are left for the next run.
+func (q *Queries) LockExpiredSessions(ctx context.Context) ([]TableSession, error) {
+	rows, err := q.db.Query(ctx, lockExpiredSessions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TableSession
+	for rows.Next() {
+		var i TableSession
+		if err := rows.Scan(
+			&i.ID,
+			&i.TableID,
+			&i.Token,
+			&i.ExpiresAt,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.Status,
+			&i.BillRequestedAt,
+			&i.ClosedAt,
+			&i.ClosedBy,
+			&i.MergedIntoID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockSessionOrders = `-- name: LockSessionOrders :many
+SELECT id FROM orders
+WHERE table_session_id = $1
+ORDER BY id
+FOR UPDATE
+`
+
+// Locks the orders of a session in a fixed order before they are moved.
+func (q *Queries) LockSessionOrders(ctx context.Context, tableSessionID pgtype.UUID) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, lockSessionOrders, tableSessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveSessionOrders = `-- name: MoveSessionOrders :execrows
+UPDATE orders
+SET table_session_id = $1,
+    table_id = $2,
+    version = version + 1,
+    updated_at = NOW()
+WHERE table_session_id = $3
+`
+
+type MoveSessionOrdersParams struct {
+	TargetSessionID pgtype.UUID `json:"target_session_id"`
+	TargetTableID   pgtype.UUID `json:"target_table_id"`
+	SourceSessionID pgtype.UUID `json:"source_session_id"`
+}
+
+// Bumps the version so clients holding a moved order must reload it.
+func (q *Queries) MoveSessionOrders(ctx context.Context, arg MoveSessionOrdersParams) (int64, error) {
+	result, err := q.db.Exec(ctx, moveSessionOrders, arg.TargetSessionID, arg.TargetTableID, arg.SourceSessionID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const requestSessionBill = `-- name: RequestSessionBill :one
+UPDATE table_sessions
+SET status = 'BILL_REQUESTED', bill_requested_at = NOW()
+WHERE id = $1 AND is_active = TRUE
+RETURNING id, table_id, token, expires_at, is_active, created_at, status, bill_requested_at, closed_at, closed_by, merged_into_id
+`
+
+func (q *Queries) RequestSessionBill(ctx context.Context, id pgtype.UUID) (TableSession, error) {
+	row := q.db.QueryRow(ctx, requestSessionBill, id)
+	var i TableSession
+	err := row.Scan(
+		&i.ID,
+		&i.TableID,
+		&i.Token,
+		&i.ExpiresAt,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.Status,
+		&i.BillRequestedAt,
+		&i.ClosedAt,
+		&i.ClosedBy,
+		&i.MergedIntoID,
+	)
+	return i, err
+}
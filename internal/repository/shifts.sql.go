@@ -17,7 +17,7 @@ SET closed_at = NOW(),
     closing_cash = $2,
     expected_cash = $3
 WHERE id = $1
-RETURNING id, user_id, store_id, opened_at, closed_at, opening_cash, closing_cash, expected_cash
+RETURNING id, user_id, store_id, opened_at, closed_at, opening_cash, closing_cash, expected_cash, terminal_id
 `
 
 type CloseShiftParams struct {
@@ -38,26 +38,33 @@ func (q *Queries) CloseShift(ctx context.Context, arg CloseShiftParams) (Shift,
 		&i.OpeningCash,
 		&i.ClosingCash,
 		&i.ExpectedCash,
+		&i.TerminalID,
 	)
 	return i, err
 }
 
 const createShift = `-- name: CreateShift :one
 INSERT INTO shifts (
-    user_id, store_id, opening_cash
+    user_id, store_id, opening_cash, terminal_id
 ) VALUES (
-    $1, $2, $3
-) RETURNING id, user_id, store_id, opened_at, closed_at, opening_cash, closing_cash, expected_cash
+    $1, $2, $3, $4
+) RETURNING id, user_id, store_id, opened_at, closed_at, opening_cash, closing_cash, expected_cash, terminal_id
 `
 
 type CreateShiftParams struct {
 	UserID      pgtype.UUID    `json:"user_id"`
 	StoreID     pgtype.UUID    `json:"store_id"`
 	OpeningCash pgtype.Numeric `json:"opening_cash"`
+	TerminalID  pgtype.UUID    `json:"terminal_id"`
 }
 
 func (q *Queries) CreateShift(ctx context.Context, arg CreateShiftParams) (Shift, error) {
-	row := q.db.QueryRow(ctx, createShift, arg.UserID, arg.StoreID, arg.OpeningCash)
+	row := q.db.QueryRow(ctx, createShift,
+		arg.UserID,
+		arg.StoreID,
+		arg.OpeningCash,
+		arg.TerminalID,
+	)
 	var i Shift
 	err := row.Scan(
 		&i.ID,
@@ -68,12 +75,13 @@ func (q *Queries) CreateShift(ctx context.Context, arg CreateShiftParams) (Shift
 		&i.OpeningCash,
 		&i.ClosingCash,
 		&i.ExpectedCash,
+		&i.TerminalID,
 	)
 	return i, err
 }
 
 const getCurrentShift = `-- name: GetCurrentShift :one
-SELECT id, user_id, store_id, opened_at, closed_at, opening_cash, closing_cash, expected_cash FROM shifts
+SELECT id, user_id, store_id, opened_at, closed_at, opening_cash, closing_cash, expected_cash, terminal_id FROM shifts
 WHERE user_id = $1 AND closed_at IS NULL
 LIMIT 1
 `
@@ -90,12 +98,36 @@ func (q *Queries) GetCurrentShift(ctx context.Context, userID pgtype.UUID) (Shif
 		&i.OpeningCash,
 		&i.ClosingCash,
 		&i.ExpectedCash,
+		&i.TerminalID,
+	)
+	return i, err
+}
+
+const getShiftForUpdate = `-- name: GetShiftForUpdate :one
+SELECT id, user_id, store_id, opened_at, closed_at, opening_cash, closing_cash, expected_cash, terminal_id FROM shifts
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetShiftForUpdate(ctx context.Context, id pgtype.UUID) (Shift, error) {
+	row := q.db.QueryRow(ctx, getShiftForUpdate, id)
+	var i Shift
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.StoreID,
+		&i.OpenedAt,
+		&i.ClosedAt,
+		&i.OpeningCash,
+		&i.ClosingCash,
+		&i.ExpectedCash,
+		&i.TerminalID,
 	)
 	return i, err
 }
 
 const listShifts = `-- name: ListShifts :many
-SELECT id, user_id, store_id, opened_at, closed_at, opening_cash, closing_cash, expected_cash FROM shifts
+SELECT id, user_id, store_id, opened_at, closed_at, opening_cash, closing_cash, expected_cash, terminal_id FROM shifts
 WHERE store_id = $1
 ORDER BY opened_at DESC
 LIMIT $2 OFFSET $3
@@ -125,6 +157,7 @@ func (q *Queries) ListShifts(ctx context.Context, arg ListShiftsParams) ([]Shift
 			&i.OpeningCash,
 			&i.ClosingCash,
 			&i.ExpectedCash,
+			&i.TerminalID,
 		); err != nil {
 			return nil, err
 		}
@@ -3,7 +3,11 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"pos-api/internal/domain"
+
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -41,3 +45,33 @@ func (store *SQLStore) ExecTx(ctx context.Context, fn func(*Queries) error) erro
 
 	return tx.Commit(ctx)
 }
+
+// ScopeConnections passes the stores of the caller to the row-level security
+// policies. Every connection taken from the pool, for a transaction or a
+// single query, is scoped to the store context of the ctx it is taken for.
+// Only access to every store (SUPER_ADMIN, jobs) sees every row; without a
+// store context the connection sees no store at all.
+func ScopeConnections(config *pgxpool.Config) {
+	config.PrepareConn = func(ctx context.Context, conn *pgx.Conn) (bool, error) {
+		if err := New(conn).SetStoreScope(ctx, storeScope(ctx)); err != nil {
+			// The connection may still carry the scope of its last user
+			return false, err
+		}
+		return true, nil
+	}
+}
+
+func storeScope(ctx context.Context) string {
+	access := domain.StoreAccessFromContext(ctx)
+	if access == nil {
+		return ""
+	}
+	if access.AllStores {
+		return "*"
+	}
+	ids := make([]string, 0, len(access.StoreIDs))
+	for _, id := range access.StoreIDs {
+		ids = append(ids, id.String())
+	}
+	return strings.Join(ids, ",")
+}
@@ -106,6 +106,17 @@ func (q *Queries) ListStores(ctx context.Context, arg ListStoresParams) ([]Store
 	return items, nil
 }
 
+const setStoreScope = `-- name: SetStoreScope :exec
+SELECT set_config('app.store_ids', $1::text, false)
+`
+
+// Row-level security only shows rows of these stores (comma separated
+// UUIDs, '*' for every store) until the connection is scoped again.
+func (q *Queries) SetStoreScope(ctx context.Context, storeIds string) error {
+	_, err := q.db.Exec(ctx, setStoreScope, storeIds)
+	return err
+}
+
 const updateStore = `-- name: UpdateStore :one
 UPDATE stores
 SET name = $2, address = $3, phone = $4, updated_at = NOW()
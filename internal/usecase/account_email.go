@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+	"pos-api/internal/util"
+	"pos-api/pkg/mailer"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Purposes of the one-time tokens that are emailed as links
+const (
+	purposePasswordReset     = "PASSWORD_RESET"
+	purposeEmailVerification = "EMAIL_VERIFICATION"
+)
+
+// maxAccountEmails is how many links of one purpose a user gets per hour, so
+// the endpoints cannot be used to flood an inbox.
+const maxAccountEmails = 3
+
+var (
+	errInvalidEmailToken = errors.New("invalid or expired link, request a new one")
+	errEmailInUse        = errors.New("email is already in use")
+	errEmailVerified     = errors.New("email address is already verified")
+	errTooManyEmails     = errors.New("too many emails sent, try again later")
+)
+
+// ForgotPassword answers nil for unknown and deactivated accounts too, and
+// when the hourly limit is reached, so it does not tell which emails exist.
+func (uc *authUsecase) ForgotPassword(ctx context.Context, req *domain.ForgotPasswordRequest) error {
+	authUser, err := uc.store.GetAuthUserByEmail(ctx, req.Email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	profileDB, err := uc.store.GetProfile(ctx, authUser.ID)
+	if err != nil || !profileDB.IsActive {
+		return nil
+	}
+
+	token, err := uc.issueAccountToken(ctx, authUser.ID, purposePasswordReset, authUser.Email, uc.config.PasswordResetDuration)
+	if errors.Is(err, errTooManyEmails) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := uc.mailer.Send(ctx, mailer.Message{
+		To:      authUser.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Someone asked to reset the password of your account. Open this link within %s to choose a new one:\n\n%s\n\nIf it was not you, ignore this email; your password stays the same.\n",
+			linkLifetime(uc.config.PasswordResetDuration), uc.accountLink("/reset-password", token)),
+	}); err != nil {
+		return err
+	}
+	return uc.recordLogin(ctx, "PASSWORD_RESET_REQUESTED", authUser.ID, &domain.LoginRequest{
+		Email:      authUser.Email,
+		ClientInfo: req.ClientInfo,
+	}, "")
+}
+
+func (uc *authUsecase) ResetPassword(ctx context.Context, req *domain.ResetPasswordRequest) error {
+	hashedPassword, err := util.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	var token repository.AuthOneTimeToken
+	err = uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		token, err = q.UseOneTimeToken(ctx, repository.UseOneTimeTokenParams{
+			TokenHash: hashOpaqueToken(req.Token),
+			Purpose:   purposePasswordReset,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errInvalidEmailToken
+		}
+		if err != nil {
+			return err
+		}
+		authUser, err := q.GetAuthUser(ctx, token.UserID)
+		if err != nil {
+			return err
+		}
+		// Links sent to an address the account no longer has are void
+		if authUser.Email != token.Email {
+			return errInvalidEmailToken
+		}
+		profileDB, err := q.GetProfile(ctx, token.UserID)
+		if err != nil {
+			return fmt.Errorf("profile not found")
+		}
+		if !profileDB.IsActive {
+			return errAccountDeactivated
+		}
+
+		if err := q.UpdateAuthUserPassword(ctx, repository.UpdateAuthUserPasswordParams{
+			ID:                token.UserID,
+			EncryptedPassword: hashedPassword,
+		}); err != nil {
+			return err
+		}
+		// The link reached the inbox, which proves the address as well
+		if !authUser.EmailVerifiedAt.Valid {
+			return q.SetAuthUserEmailVerified(ctx, repository.SetAuthUserEmailVerifiedParams{
+				ID:    token.UserID,
+				Email: token.Email,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := uc.LogoutAll(ctx, uuid.UUID(token.UserID.Bytes)); err != nil {
+		return err
+	}
+	if err := uc.loginLimiter.Unlock(ctx, token.Email); err != nil {
+		return err
+	}
+	return uc.recordLogin(ctx, "PASSWORD_RESET", token.UserID, &domain.LoginRequest{
+		Email:      token.Email,
+		ClientInfo: req.ClientInfo,
+	}, "")
+}
+
+func (uc *authUsecase) SendVerification(ctx context.Context, userID uuid.UUID) error {
+	authUser, err := uc.store.GetAuthUser(ctx, pgtype.UUID{Bytes: userID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("profile not found")
+	}
+	if authUser.EmailVerifiedAt.Valid {
+		return errEmailVerified
+	}
+	return uc.sendVerification(ctx, authUser.ID, authUser.Email)
+}
+
+// VerifyEmail marks the address of the link verified. When it differs from
+// the account's address, this completes an email change.
+func (uc *authUsecase) VerifyEmail(ctx context.Context, req *domain.VerifyEmailRequest) error {
+	var token repository.AuthOneTimeToken
+	changed := false
+	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		var err error
+		token, err = q.UseOneTimeToken(ctx, repository.UseOneTimeTokenParams{
+			TokenHash: hashOpaqueToken(req.Token),
+			Purpose:   purposeEmailVerification,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errInvalidEmailToken
+		}
+		if err != nil {
+			return err
+		}
+		authUser, err := q.GetAuthUser(ctx, token.UserID)
+		if err != nil {
+			return err
+		}
+
+		if authUser.Email != token.Email {
+			// Someone may have registered the address since the link was sent
+			_, err := q.GetAuthUserByEmail(ctx, token.Email)
+			if err == nil {
+				return errEmailInUse
+			}
+			if !errors.Is(err, pgx.ErrNoRows) {
+				return err
+			}
+			if err := q.UpdateProfileEmail(ctx, repository.UpdateProfileEmailParams{
+				ID:    token.UserID,
+				Email: pgtype.Text{String: token.Email, Valid: true},
+			}); err != nil {
+				return err
+			}
+			changed = true
+		}
+		return q.SetAuthUserEmailVerified(ctx, repository.SetAuthUserEmailVerifiedParams{
+			ID:    token.UserID,
+			Email: token.Email,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	action := "EMAIL_VERIFIED"
+	if changed {
+		action = "EMAIL_CHANGED"
+	}
+	return uc.recordLogin(ctx, action, token.UserID, &domain.LoginRequest{
+		Email:      token.Email,
+		ClientInfo: req.ClientInfo,
+	}, "")
+}
+
+// ChangeEmail keeps the current address until the link sent to the new one
+// is opened. The current address is told about the change, in case the
+// account was taken over.
+func (uc *authUsecase) ChangeEmail(ctx context.Context, userID uuid.UUID, req *domain.ChangeEmailRequest) error {
+	authUser, err := uc.store.GetAuthUser(ctx, pgtype.UUID{Bytes: userID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("profile not found")
+	}
+	if err := util.CheckPassword(req.Password, authUser.EncryptedPassword); err != nil {
+		return errInvalidCredentials
+	}
+	if strings.EqualFold(req.Email, authUser.Email) {
+		return fmt.Errorf("this is already your email address")
+	}
+	_, err = uc.store.GetAuthUserByEmail(ctx, req.Email)
+	if err == nil {
+		return errEmailInUse
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	if err := uc.sendVerification(ctx, authUser.ID, req.Email); err != nil {
+		return err
+	}
+	if err := uc.mailer.Send(ctx, mailer.Message{
+		To:      authUser.Email,
+		Subject: "Your email address is being changed",
+		Body: fmt.Sprintf("A link to move your account to %s was sent to that address. Your account keeps this address until the link is opened.\n\nIf you did not ask for this, change your password.\n",
+			req.Email),
+	}); err != nil {
+		return err
+	}
+	return uc.recordLogin(ctx, "EMAIL_CHANGE_REQUESTED", authUser.ID, &domain.LoginRequest{
+		Email:      req.Email,
+		ClientInfo: req.ClientInfo,
+	}, "")
+}
+
+func (uc *authUsecase) PurgeOneTimeTokens(ctx context.Context) (int64, error) {
+	return uc.store.PurgeOneTimeTokens(ctx, pgtype.Timestamptz{Time: time.Now(), Valid: true})
+}
+
+// sendVerification emails a link that verifies email for the user.
+func (uc *authUsecase) sendVerification(ctx context.Context, userID pgtype.UUID, email string) error {
+	token, err := uc.issueAccountToken(ctx, userID, purposeEmailVerification, email, uc.config.EmailVerificationDuration)
+	if err != nil {
+		return err
+	}
+	return uc.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Open this link within %s to verify your email address:\n\n%s\n\nIf you did not ask for this, ignore this email.\n",
+			linkLifetime(uc.config.EmailVerificationDuration), uc.accountLink("/verify-email", token)),
+	})
+}
+
+// issueAccountToken creates a one-time token for a link sent to email. Earlier
+// tokens of the purpose stop working.
+func (uc *authUsecase) issueAccountToken(ctx context.Context, userID pgtype.UUID, purpose, email string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	sent, err := uc.store.CountRecentOneTimeTokens(ctx, repository.CountRecentOneTimeTokensParams{
+		UserID:    userID,
+		Purpose:   purpose,
+		CreatedAt: pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true},
+	})
+	if err != nil {
+		return "", err
+	}
+	if sent >= maxAccountEmails {
+		return "", errTooManyEmails
+	}
+
+	token, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	err = uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		if err := q.RevokeOneTimeTokens(ctx, repository.RevokeOneTimeTokensParams{
+			UserID:  userID,
+			Purpose: purpose,
+		}); err != nil {
+			return err
+		}
+		_, err := q.CreateOneTimeToken(ctx, repository.CreateOneTimeTokenParams{
+			UserID:    userID,
+			Purpose:   purpose,
+			Email:     email,
+			TokenHash: hashOpaqueToken(token),
+			ExpiresAt: pgtype.Timestamptz{Time: now.Add(ttl), Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// accountLink points at a page of the web app that posts the token back.
+func (uc *authUsecase) accountLink(path, token string) string {
+	return strings.TrimSuffix(uc.config.AccountLinkBaseURL, "/") + path + "?token=" + token
+}
+
+// linkLifetime renders a token lifetime for an email, e.g. "48 hours".
+func linkLifetime(d time.Duration) string {
+	switch {
+	case d == time.Hour:
+		return "1 hour"
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%d hours", d/time.Hour)
+	default:
+		return fmt.Sprintf("%d minutes", d/time.Minute)
+	}
+}
@@ -2,36 +2,74 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"pos-api/internal/domain"
 	"pos-api/internal/repository"
 	"pos-api/internal/util"
+	"pos-api/pkg/mailer"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type authUsecase struct {
-	store      repository.Repository
-	tokenMaker util.TokenMaker
-	config     AuthConfig
+	store        repository.Repository
+	tokenMaker   util.TokenMaker
+	denylist     util.TokenDenylist
+	loginLimiter util.LoginLimiter
+	challenges   util.LoginChallengeStore
+	totp         *util.TOTP
+	mailer       mailer.Mailer
+	config       AuthConfig
 }
 
 type AuthConfig struct {
-	AccessTokenDuration time.Duration
+	AccessTokenDuration  time.Duration
+	RefreshTokenDuration time.Duration // Extended on every refresh
+	ChallengeDuration    time.Duration // Time to give the second factor after the password
+	// Emailed links
+	PasswordResetDuration     time.Duration
+	EmailVerificationDuration time.Duration
+	AccountLinkBaseURL        string // Web app, links go to <base>/reset-password and <base>/verify-email
 }
 
-func NewAuthUsecase(store repository.Repository, tokenMaker util.TokenMaker, config AuthConfig) domain.AuthUsecase {
+func NewAuthUsecase(store repository.Repository, tokenMaker util.TokenMaker, denylist util.TokenDenylist, loginLimiter util.LoginLimiter, challenges util.LoginChallengeStore, totp *util.TOTP, mailer mailer.Mailer, config AuthConfig) domain.AuthUsecase {
 	return &authUsecase{
-		store:      store,
-		tokenMaker: tokenMaker,
-		config:     config,
+		store:        store,
+		tokenMaker:   tokenMaker,
+		denylist:     denylist,
+		loginLimiter: loginLimiter,
+		challenges:   challenges,
+		totp:         totp,
+		mailer:       mailer,
+		config:       config,
 	}
 }
 
-func (uc *authUsecase) Register(ctx context.Context, req *domain.RegisterRequest) (*domain.Profile, error) {
+func (uc *authUsecase) Register(ctx context.Context, actor *domain.JwtCustomClaims, req *domain.RegisterRequest) (*domain.Profile, error) {
+	var storeID pgtype.UUID
+	if req.StoreID != "" {
+		parsedID, err := uuid.Parse(req.StoreID)
+		if err == nil {
+			storeID = pgtype.UUID{Bytes: parsedID, Valid: true}
+		}
+	}
+
+	storeID, err := uc.authorizeRegister(ctx, actor, req.Role, storeID)
+	if err != nil {
+		return nil, err
+	}
+
 	hashedPassword, err := util.HashPassword(req.Password)
 	if err != nil {
 		return nil, err
@@ -40,52 +78,24 @@ func (uc *authUsecase) Register(ctx context.Context, req *domain.RegisterRequest
 	var profile *domain.Profile
 
 	err = uc.store.ExecTx(ctx, func(q *repository.Queries) error {
-		// 1. Create Auth User (Simulated)
-		resAuth, err := q.CreateAuthUser(ctx, repository.CreateAuthUserParams{
-			Email:             req.Email,
-			EncryptedPassword: hashedPassword,
-		})
-		if err != nil {
-			return fmt.Errorf("failed to create auth user: %w", err)
-		}
-
-		// 2. Create Profile
-		// Profile ID matches Auth User ID
-		var storeID pgtype.UUID
-		if req.StoreID != "" {
-			parsedID, err := uuid.Parse(req.StoreID)
-			if err == nil {
-				storeID = pgtype.UUID{Bytes: parsedID, Valid: true}
+		if actor == nil {
+			// Concurrent anonymous requests must not both create the first SUPER_ADMIN
+			if err := q.LockRoleAssignments(ctx, string(domain.RoleSuperAdmin)); err != nil {
+				return err
+			}
+			admins, err := q.CountUsersWithRole(ctx, string(domain.RoleSuperAdmin))
+			if err != nil {
+				return err
+			}
+			if admins > 0 {
+				return errRegisterRequiresToken
 			}
 		}
-
-		resProfile, err := q.CreateProfile(ctx, repository.CreateProfileParams{
-			ID:       resAuth.ID, // Link ID
-			Email:    pgtype.Text{String: req.Email, Valid: true},
-			FullName: pgtype.Text{String: req.FullName, Valid: true},
-			Role:     string(req.Role),
-			StoreID:  storeID,
-		})
+		resProfile, err := createAccount(ctx, q, req.Email, hashedPassword, req.FullName, req.Role, storeID)
 		if err != nil {
-			return fmt.Errorf("failed to create profile: %w", err)
-		}
-
-		// Map to domain
-		var sID *uuid.UUID
-		if resProfile.StoreID.Valid {
-			uid := uuid.UUID(resProfile.StoreID.Bytes)
-			sID = &uid
-		}
-
-		profile = &domain.Profile{
-			ID:        uuid.UUID(resProfile.ID.Bytes),
-			Email:     resProfile.Email.String,
-			FullName:  resProfile.FullName.String,
-			Role:      domain.UserRole(resProfile.Role),
-			StoreID:   sID,
-			CreatedAt: resProfile.CreatedAt.Time,
-			// UpdatedAt: resProfile.UpdatedAt.Time, // Not available in DB yet
+			return err
 		}
+		profile = toDomainProfile(resProfile)
 		return nil
 	})
 
@@ -93,20 +103,102 @@ func (uc *authUsecase) Register(ctx context.Context, req *domain.RegisterRequest
 		return nil, err
 	}
 
+	// The account exists either way; a new link can be requested
+	_ = uc.sendVerification(ctx, pgtype.UUID{Bytes: profile.ID, Valid: true}, profile.Email)
+
 	return profile, nil
 }
 
+var (
+	errRegisterForbidden     = errors.New("not allowed to create users with this role")
+	errRegisterRequiresToken = errors.New("registration requires a SUPER_ADMIN or STORE_OWNER token")
+)
+
+// authorizeRegister returns the store the new account goes to. Owners can
+// only add staff to their own store. Anonymous requests may only create the
+// first SUPER_ADMIN, to set up a fresh installation.
+func (uc *authUsecase) authorizeRegister(ctx context.Context, actor *domain.JwtCustomClaims, role domain.UserRole, storeID pgtype.UUID) (pgtype.UUID, error) {
+	switch {
+	case actor == nil:
+		// Register checks inside its transaction that no SUPER_ADMIN exists yet
+		if role != domain.RoleSuperAdmin {
+			return pgtype.UUID{}, errRegisterRequiresToken
+		}
+		return pgtype.UUID{}, nil
+	case slices.Contains(actor.Roles, string(domain.RoleSuperAdmin)):
+		return storeID, nil
+	case slices.Contains(actor.Roles, string(domain.RoleStoreOwner)):
+		if !role.IsStaffRole() {
+			return pgtype.UUID{}, errRegisterForbidden
+		}
+		owner, err := uc.store.GetProfile(ctx, pgtype.UUID{Bytes: actor.UserID, Valid: true})
+		if err != nil || !owner.StoreID.Valid {
+			return pgtype.UUID{}, fmt.Errorf("owner has no store")
+		}
+		if storeID.Valid && storeID != owner.StoreID {
+			return pgtype.UUID{}, fmt.Errorf("user belongs to another store")
+		}
+		return owner.StoreID, nil
+	default:
+		return pgtype.UUID{}, errRegisterForbidden
+	}
+}
+
+// createAccount creates the auth user, the profile that shares its ID and the
+// global role assignment.
+func createAccount(ctx context.Context, q *repository.Queries, email, passwordHash, fullName string, role domain.UserRole, storeID pgtype.UUID) (repository.Profile, error) {
+	// 1. Create Auth User (Simulated)
+	resAuth, err := q.CreateAuthUser(ctx, repository.CreateAuthUserParams{
+		Email:             email,
+		EncryptedPassword: passwordHash,
+	})
+	if err != nil {
+		return repository.Profile{}, fmt.Errorf("failed to create auth user: %w", err)
+	}
+
+	// 2. Create Profile
+	// Profile ID matches Auth User ID
+	resProfile, err := q.CreateProfile(ctx, repository.CreateProfileParams{
+		ID:       resAuth.ID, // Link ID
+		Email:    pgtype.Text{String: email, Valid: true},
+		FullName: pgtype.Text{String: fullName, Valid: true},
+		Role:     string(role),
+		StoreID:  storeID,
+	})
+	if err != nil {
+		return repository.Profile{}, fmt.Errorf("failed to create profile: %w", err)
+	}
+
+	// 3. Assign Role
+	_, err = q.AssignRoleToUser(ctx, repository.AssignRoleToUserParams{
+		UserID:   resProfile.ID,
+		RoleCode: string(role),
+	})
+	if err != nil {
+		return repository.Profile{}, fmt.Errorf("failed to assign role: %w", err)
+	}
+	return resProfile, nil
+}
+
 func (uc *authUsecase) Login(ctx context.Context, req *domain.LoginRequest) (*domain.LoginResponse, error) {
+	wait, locked, err := uc.loginLimiter.Allow(ctx, req.IPAddress, req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if wait > 0 {
+		return nil, &domain.LoginThrottledError{RetryAfter: wait, Locked: locked}
+	}
+
 	// 1. Get Auth User
 	authUser, err := uc.store.GetAuthUserByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, fmt.Errorf("invalid credentials") // User not found
+		return nil, uc.loginFailed(ctx, req, pgtype.UUID{}, "unknown_email", errInvalidCredentials)
 	}
 
 	// 2. Check Password
 	err = util.CheckPassword(req.Password, authUser.EncryptedPassword)
 	if err != nil {
-		return nil, fmt.Errorf("invalid credentials")
+		return nil, uc.loginFailed(ctx, req, authUser.ID, "wrong_password", errInvalidCredentials)
 	}
 
 	// 3. Get Profile
@@ -114,38 +206,519 @@ func (uc *authUsecase) Login(ctx context.Context, req *domain.LoginRequest) (*do
 	if err != nil {
 		return nil, fmt.Errorf("profile not found")
 	}
+	if !profileDB.IsActive {
+		if err := uc.recordLogin(ctx, "LOGIN_FAILED", profileDB.ID, req, "account_deactivated"); err != nil {
+			return nil, err
+		}
+		return nil, errAccountDeactivated
+	}
+
+	// 4. Second factor; failures are only forgotten once it is given too
+	challenge, err := uc.twoFactorChallenge(ctx, profileDB, req)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return &domain.LoginResponse{TwoFactor: challenge}, nil
+	}
+	if err := uc.loginLimiter.Succeed(ctx, req.Email); err != nil {
+		return nil, err
+	}
+
+	// 5. Create Tokens; every login starts a new device session
+	res, _, err := uc.issueTokens(ctx, uc.store, profileDB, deviceSession{
+		familyID:        uuid.New(),
+		authenticatedAt: time.Now(),
+		deviceName:      req.DeviceName,
+		client:          req.ClientInfo,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.recordLogin(ctx, "LOGIN", profileDB.ID, req, ""); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// LoginTwoFactor completes a login challenge. For users who enrolled during
+// the challenge the code confirms the authenticator, and the response
+// carries their recovery codes.
+func (uc *authUsecase) LoginTwoFactor(ctx context.Context, req *domain.TwoFactorLoginRequest) (*domain.LoginResponse, error) {
+	tokenHash := hashOpaqueToken(req.ChallengeToken)
+	challenge, err := uc.challenges.Get(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil {
+		return nil, errInvalidChallenge
+	}
+	loginReq := &domain.LoginRequest{
+		Email:      challenge.Email,
+		DeviceName: challenge.DeviceName,
+		ClientInfo: req.ClientInfo,
+	}
+
+	wait, locked, err := uc.loginLimiter.Allow(ctx, req.IPAddress, challenge.Email)
+	if err != nil {
+		return nil, err
+	}
+	if wait > 0 {
+		return nil, &domain.LoginThrottledError{RetryAfter: wait, Locked: locked}
+	}
+
+	userID := pgtype.UUID{Bytes: challenge.UserID, Valid: true}
+	profileDB, err := uc.store.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("profile not found")
+	}
+	if !profileDB.IsActive {
+		return nil, errAccountDeactivated
+	}
+	row, err := uc.store.GetUserTotp(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("set up two-factor authentication first")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var used string
+	var recoveryCodes []string
+	if row.ConfirmedAt.Valid {
+		used, err = verifySecondFactor(ctx, uc.store, uc.totp, row, req.Code)
+	} else {
+		recoveryCodes, err = confirmTOTP(ctx, uc.store, uc.totp, userID, req.Code)
+		if err == nil {
+			used = "totp"
+		} else if errors.Is(err, errInvalidTwoFactorCode) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if used == "" {
+		return nil, uc.loginFailed(ctx, loginReq, userID, "wrong_2fa_code", errInvalidTwoFactorCode)
+	}
+
+	if err := uc.challenges.Delete(ctx, tokenHash); err != nil {
+		return nil, err
+	}
+	if err := uc.loginLimiter.Succeed(ctx, challenge.Email); err != nil {
+		return nil, err
+	}
+	res, _, err := uc.issueTokens(ctx, uc.store, profileDB, deviceSession{
+		familyID:        uuid.New(),
+		authenticatedAt: challenge.AuthenticatedAt,
+		deviceName:      challenge.DeviceName,
+		client:          req.ClientInfo,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.RecoveryCodes = recoveryCodes
+	if err := uc.recordLogin(ctx, "LOGIN", userID, loginReq, used); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (uc *authUsecase) EnrollTwoFactor(ctx context.Context, req *domain.TwoFactorEnrollRequest) (*domain.TOTPEnrollment, error) {
+	challenge, err := uc.challenges.Get(ctx, hashOpaqueToken(req.ChallengeToken))
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil {
+		return nil, errInvalidChallenge
+	}
+	profileDB, err := uc.store.GetProfile(ctx, pgtype.UUID{Bytes: challenge.UserID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("profile not found")
+	}
+	return enrollTOTP(ctx, uc.store, uc.totp, profileDB.ID, profileDB.Email.String)
+}
 
-	// 4. Create Token
-	// Role is in profile
-	role := profileDB.Role
+// twoFactorChallenge starts the second login step when the user has 2FA on,
+// or holds a role that requires it. Returns nil when the password is enough.
+func (uc *authUsecase) twoFactorChallenge(ctx context.Context, profileDB repository.Profile, req *domain.LoginRequest) (*domain.TwoFactorChallenge, error) {
+	row, err := uc.store.GetUserTotp(ctx, profileDB.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	enabled := err == nil && row.ConfirmedAt.Valid
+	if !enabled {
+		required, err := uc.store.UserRequiresTwoFactor(ctx, profileDB.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !required {
+			return nil, nil
+		}
+	}
 
-	accessToken, err := uc.tokenMaker.CreateToken(
-		uuid.UUID(authUser.ID.Bytes),
-		authUser.Email,
-		[]string{role}, // Pass as slice
+	token, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	err = uc.challenges.Save(ctx, hashOpaqueToken(token), util.LoginChallenge{
+		UserID:          uuid.UUID(profileDB.ID.Bytes),
+		Email:           req.Email,
+		DeviceName:      req.DeviceName,
+		AuthenticatedAt: now,
+	}, uc.config.ChallengeDuration)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TwoFactorChallenge{
+		ChallengeToken:     token,
+		ExpiresAt:          now.Add(uc.config.ChallengeDuration),
+		EnrollmentRequired: !enabled,
+	}, nil
+}
+
+var (
+	errInvalidCredentials = errors.New("invalid credentials")
+	errInvalidChallenge   = errors.New("invalid or expired login challenge, log in again")
+)
+
+// loginFailed counts a wrong email, password or code against the email,
+// locking the account after too many, and returns invalid or the lockout for
+// the caller. Unknown emails count too, the caller cannot tell them apart.
+func (uc *authUsecase) loginFailed(ctx context.Context, req *domain.LoginRequest, userID pgtype.UUID, reason string, invalid error) error {
+	lockedFor, err := uc.loginLimiter.Fail(ctx, req.Email)
+	if err != nil {
+		return err
+	}
+	if err := uc.recordLogin(ctx, "LOGIN_FAILED", userID, req, reason); err != nil {
+		return err
+	}
+	if lockedFor == 0 {
+		return invalid
+	}
+	if err := uc.recordLogin(ctx, "ACCOUNT_LOCKED", userID, req, reason); err != nil {
+		return err
+	}
+	return &domain.LoginThrottledError{RetryAfter: lockedFor, Locked: true}
+}
+
+// recordLogin writes a password login or account event to the audit log. The
+// email is kept in after, failed logins may not belong to a user. For a LOGIN
+// the reason is the second factor, if any.
+func (uc *authUsecase) recordLogin(ctx context.Context, action string, userID pgtype.UUID, req *domain.LoginRequest, reason string) error {
+	details := map[string]string{"email": req.Email}
+	if reason != "" {
+		details["reason"] = reason
+	}
+	if req.UserAgent != "" {
+		details["user_agent"] = req.UserAgent
+	}
+	after, _ := json.Marshal(details)
+
+	_, err := uc.store.CreateAuditLog(ctx, repository.CreateAuditLogParams{
+		UserID:    userID,
+		Action:    action,
+		Entity:    pgtype.Text{String: "Profile", Valid: userID.Valid},
+		EntityID:  userID,
+		After:     after,
+		IpAddress: pgtype.Text{String: req.IPAddress, Valid: req.IPAddress != ""},
+	})
+	return err
+}
+
+// Refresh rotates the refresh token. A token that was already rotated is a
+// sign of theft, so presenting one ends its whole device session.
+func (uc *authUsecase) Refresh(ctx context.Context, req *domain.RefreshRequest) (*domain.LoginResponse, error) {
+	var res *domain.LoginResponse
+	var reused *repository.RefreshToken
+
+	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		current, err := q.GetRefreshTokenForUpdate(ctx, hashOpaqueToken(req.RefreshToken))
+		if err != nil {
+			return errInvalidRefreshToken
+		}
+		if current.RevokedAt.Valid {
+			if current.ReplacedByID.Valid {
+				reused = &current
+			}
+			return errInvalidRefreshToken
+		}
+		if !current.ExpiresAt.Time.After(time.Now()) {
+			return errInvalidRefreshToken
+		}
+
+		profileDB, err := q.GetProfile(ctx, current.UserID)
+		if err != nil {
+			return errInvalidRefreshToken
+		}
+		if !profileDB.IsActive {
+			return errAccountDeactivated
+		}
+
+		var nextID pgtype.UUID
+		res, nextID, err = uc.issueTokens(ctx, q, profileDB, deviceSession{
+			familyID:        uuid.UUID(current.FamilyID.Bytes),
+			authenticatedAt: current.AuthenticatedAt.Time,
+			deviceName:      current.DeviceName.String,
+			client:          req.ClientInfo,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.RotateRefreshToken(ctx, repository.RotateRefreshTokenParams{
+			ID:           current.ID,
+			ReplacedByID: nextID,
+		})
+	})
+	if reused != nil {
+		rows, revokeErr := uc.store.RevokeRefreshTokenFamily(ctx, repository.RevokeRefreshTokenFamilyParams{
+			FamilyID: reused.FamilyID,
+			UserID:   reused.UserID,
+		})
+		if revokeErr == nil {
+			revokeErr = uc.denyAccessTokens(ctx, toRevokedTokens(rows))
+		}
+		if revokeErr != nil {
+			return nil, revokeErr
+		}
+		return nil, fmt.Errorf("refresh token was already used, log in again")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (uc *authUsecase) Logout(ctx context.Context, claims *domain.JwtCustomClaims, req *domain.LogoutRequest) error {
+	if err := uc.denylist.Deny(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		return err
+	}
+
+	userID := pgtype.UUID{Bytes: claims.UserID, Valid: true}
+	var token repository.RefreshToken
+	var err error
+	if req.RefreshToken != "" {
+		token, err = uc.store.GetRefreshTokenForUpdate(ctx, hashOpaqueToken(req.RefreshToken))
+	} else {
+		token, err = uc.store.GetRefreshTokenByAccessJTI(ctx, repository.GetRefreshTokenByAccessJTIParams{
+			UserID:    userID,
+			AccessJti: pgtype.Text{String: claims.ID, Valid: true},
+		})
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		// No device session to end, the access token is denied already
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := uc.store.RevokeRefreshTokenFamily(ctx, repository.RevokeRefreshTokenFamilyParams{
+		FamilyID: token.FamilyID,
+		UserID:   userID,
+	})
+	if err != nil {
+		return err
+	}
+	return uc.denyAccessTokens(ctx, toRevokedTokens(rows))
+}
+
+func (uc *authUsecase) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	rows, err := uc.store.RevokeUserRefreshTokens(ctx, pgtype.UUID{Bytes: userID, Valid: true})
+	if err != nil {
+		return err
+	}
+	return uc.denyAccessTokens(ctx, rows)
+}
+
+func (uc *authUsecase) ListSessions(ctx context.Context, claims *domain.JwtCustomClaims) ([]domain.AuthSession, error) {
+	rows, err := uc.store.ListActiveRefreshTokens(ctx, pgtype.UUID{Bytes: claims.UserID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]domain.AuthSession, 0, len(rows))
+	for _, t := range rows {
+		sessions = append(sessions, domain.AuthSession{
+			ID:              uuid.UUID(t.FamilyID.Bytes),
+			DeviceName:      t.DeviceName.String,
+			UserAgent:       t.UserAgent.String,
+			IPAddress:       t.IpAddress.String,
+			AuthenticatedAt: t.AuthenticatedAt.Time,
+			LastRefreshedAt: t.CreatedAt.Time,
+			ExpiresAt:       t.ExpiresAt.Time,
+			Current:         t.AccessJti.String == claims.ID,
+		})
+	}
+	return sessions, nil
+}
+
+func (uc *authUsecase) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	rows, err := uc.store.RevokeRefreshTokenFamily(ctx, repository.RevokeRefreshTokenFamilyParams{
+		FamilyID: pgtype.UUID{Bytes: sessionID, Valid: true},
+		UserID:   pgtype.UUID{Bytes: userID, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("session not found")
+	}
+	return uc.denyAccessTokens(ctx, toRevokedTokens(rows))
+}
+
+func (uc *authUsecase) LogoutUser(ctx context.Context, actor *domain.JwtCustomClaims, userID uuid.UUID) error {
+	if !slices.Contains(actor.Roles, string(domain.RoleSuperAdmin)) {
+		if !slices.Contains(actor.Roles, string(domain.RoleStoreOwner)) {
+			return fmt.Errorf("not allowed to log out other users")
+		}
+		owner, err := uc.store.GetProfile(ctx, pgtype.UUID{Bytes: actor.UserID, Valid: true})
+		if err != nil {
+			return fmt.Errorf("profile not found")
+		}
+		target, err := uc.store.GetProfile(ctx, pgtype.UUID{Bytes: userID, Valid: true})
+		if err != nil {
+			return fmt.Errorf("user not found")
+		}
+		if !owner.StoreID.Valid || owner.StoreID != target.StoreID {
+			return fmt.Errorf("user belongs to another store")
+		}
+	}
+	return uc.LogoutAll(ctx, userID)
+}
+
+func (uc *authUsecase) PurgeRefreshTokens(ctx context.Context) (int64, error) {
+	return uc.store.PurgeRefreshTokens(ctx, pgtype.Timestamptz{Time: time.Now(), Valid: true})
+}
+
+var (
+	errInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	errAccountDeactivated  = errors.New("account is deactivated")
+)
+
+// deviceSession is the refresh token family a token pair is issued for.
+type deviceSession struct {
+	familyID        uuid.UUID
+	authenticatedAt time.Time
+	deviceName      string
+	client          domain.ClientInfo
+}
+
+// issueTokens creates an access token and the refresh token that goes with
+// it, and returns the ID of the refresh token row.
+func (uc *authUsecase) issueTokens(ctx context.Context, q repository.Querier, profileDB repository.Profile, session deviceSession) (*domain.LoginResponse, pgtype.UUID, error) {
+	// Roles are in user_roles, the profile store is the user's own
+	roles, err := loadUserRoles(ctx, q, profileDB.ID, profileDB.StoreID)
+	if err != nil {
+		return nil, pgtype.UUID{}, err
+	}
+	accessToken, claims, err := uc.tokenMaker.CreateToken(
+		uuid.UUID(profileDB.ID.Bytes),
+		profileDB.Email.String,
+		roles.roles,
+		roles.storeRoles,
+		optionalUUID(profileDB.StoreID),
 		uc.config.AccessTokenDuration,
 	)
 	if err != nil {
-		return nil, err
+		return nil, pgtype.UUID{}, err
+	}
+
+	refreshToken, err := newOpaqueToken()
+	if err != nil {
+		return nil, pgtype.UUID{}, err
+	}
+	refreshExpiresAt := time.Now().Add(uc.config.RefreshTokenDuration)
+
+	row, err := q.CreateRefreshToken(ctx, repository.CreateRefreshTokenParams{
+		UserID:          profileDB.ID,
+		FamilyID:        pgtype.UUID{Bytes: session.familyID, Valid: true},
+		TokenHash:       hashOpaqueToken(refreshToken),
+		DeviceName:      pgtype.Text{String: session.deviceName, Valid: session.deviceName != ""},
+		UserAgent:       pgtype.Text{String: session.client.UserAgent, Valid: session.client.UserAgent != ""},
+		IpAddress:       pgtype.Text{String: session.client.IPAddress, Valid: session.client.IPAddress != ""},
+		AccessJti:       pgtype.Text{String: claims.ID, Valid: true},
+		AccessExpiresAt: pgtype.Timestamptz{Time: claims.ExpiresAt.Time, Valid: true},
+		AuthenticatedAt: pgtype.Timestamptz{Time: session.authenticatedAt, Valid: true},
+		ExpiresAt:       pgtype.Timestamptz{Time: refreshExpiresAt, Valid: true},
+	})
+	if err != nil {
+		return nil, pgtype.UUID{}, err
+	}
+
+	authUser, err := q.GetAuthUser(ctx, profileDB.ID)
+	if err != nil {
+		return nil, pgtype.UUID{}, err
+	}
+	emailVerified := authUser.EmailVerifiedAt.Valid
+
+	profile := toDomainProfile(profileDB)
+	profile.Roles = roles.toDomain()
+	profile.EmailVerified = &emailVerified
+	return &domain.LoginResponse{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  claims.ExpiresAt.Time,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshExpiresAt,
+		Profile:               profile,
+	}, row.ID, nil
+}
+
+// denyAccessTokens denylists the last access tokens of revoked refresh
+// tokens until they expire.
+func (uc *authUsecase) denyAccessTokens(ctx context.Context, rows []repository.RevokeUserRefreshTokensRow) error {
+	for _, row := range rows {
+		if !row.AccessJti.Valid {
+			continue
+		}
+		if err := uc.denylist.Deny(ctx, row.AccessJti.String, row.AccessExpiresAt.Time); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toRevokedTokens(rows []repository.RevokeRefreshTokenFamilyRow) []repository.RevokeUserRefreshTokensRow {
+	revoked := make([]repository.RevokeUserRefreshTokensRow, 0, len(rows))
+	for _, row := range rows {
+		revoked = append(revoked, repository.RevokeUserRefreshTokensRow(row))
 	}
+	return revoked
+}
 
+func toDomainProfile(p repository.Profile) *domain.Profile {
 	// Map to domain
 	var sID *uuid.UUID
-	if profileDB.StoreID.Valid {
-		uid := uuid.UUID(profileDB.StoreID.Bytes)
+	if p.StoreID.Valid {
+		uid := uuid.UUID(p.StoreID.Bytes)
 		sID = &uid
 	}
 
-	return &domain.LoginResponse{
-		AccessToken: accessToken,
-		Profile: &domain.Profile{
-			ID:        uuid.UUID(profileDB.ID.Bytes),
-			Email:     profileDB.Email.String,
-			FullName:  profileDB.FullName.String,
-			Role:      domain.UserRole(profileDB.Role),
-			StoreID:   sID,
-			CreatedAt: profileDB.CreatedAt.Time,
-			// UpdatedAt: profileDB.UpdatedAt.Time,
-		},
-	}, nil
+	return &domain.Profile{
+		ID:        uuid.UUID(p.ID.Bytes),
+		Email:     p.Email.String,
+		FullName:  p.FullName.String,
+		Role:      domain.UserRole(p.Role),
+		StoreID:   sID,
+		IsActive:  p.IsActive,
+		HasPin:    p.PinHash.Valid,
+		CreatedAt: p.CreatedAt.Time,
+		UpdatedAt: p.UpdatedAt.Time,
+	}
+}
+
+// newOpaqueToken returns a random URL-safe token. Only its hash is stored.
+func newOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type orderDraftUsecase struct {
+	store        repository.Repository
+	orderUsecase domain.OrderUsecase
+	ttl          time.Duration
+}
+
+// NewOrderDraftUsecase keeps drafts for ttl after their last change.
+func NewOrderDraftUsecase(store repository.Repository, orderUsecase domain.OrderUsecase, ttl time.Duration) domain.OrderDraftUsecase {
+	return &orderDraftUsecase{
+		store:        store,
+		orderUsecase: orderUsecase,
+		ttl:          ttl,
+	}
+}
+
+func (uc *orderDraftUsecase) SaveDraft(ctx context.Context, draftID *uuid.UUID, req *domain.SaveOrderDraftRequest) (*domain.OrderDraft, error) {
+	if err := checkStore(ctx, req.StoreID); err != nil {
+		return nil, err
+	}
+
+	req.Order.StoreID = req.StoreID
+	req.Order.IdempotencyKey = "" // Set on conversion
+	payload, err := json.Marshal(req.Order)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := pgtype.Timestamptz{Time: time.Now().Add(uc.ttl), Valid: true}
+
+	if draftID == nil {
+		dbDraft, err := uc.store.CreateOrderDraft(ctx, repository.CreateOrderDraftParams{
+			StoreID:    pgtype.UUID{Bytes: req.StoreID, Valid: true},
+			CashierID:  pgtype.UUID{Bytes: req.CashierID, Valid: true},
+			TerminalID: req.TerminalID,
+			Label:      req.Label,
+			Payload:    payload,
+			ExpiresAt:  expiresAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return toDomainOrderDraft(dbDraft)
+	}
+
+	current, err := uc.GetDraft(ctx, *draftID)
+	if err != nil {
+		return nil, err
+	}
+	if current.StoreID != req.StoreID {
+		return nil, fmt.Errorf("draft belongs to another store")
+	}
+
+	dbDraft, err := uc.store.UpdateOrderDraft(ctx, repository.UpdateOrderDraftParams{
+		TerminalID: req.TerminalID,
+		Label:      req.Label,
+		Payload:    payload,
+		ExpiresAt:  expiresAt,
+		ID:         pgtype.UUID{Bytes: *draftID, Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("draft not found or expired")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toDomainOrderDraft(dbDraft)
+}
+
+func (uc *orderDraftUsecase) GetDraft(ctx context.Context, draftID uuid.UUID) (*domain.OrderDraft, error) {
+	dbDraft, err := uc.store.GetOrderDraft(ctx, pgtype.UUID{Bytes: draftID, Valid: true})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("draft not found or expired")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStoreID(ctx, dbDraft.StoreID); err != nil {
+		return nil, err
+	}
+	return toDomainOrderDraft(dbDraft)
+}
+
+func (uc *orderDraftUsecase) ListDrafts(ctx context.Context, filter domain.ListOrderDraftsFilter) ([]domain.OrderDraft, error) {
+	if err := checkStore(ctx, filter.StoreID); err != nil {
+		return nil, err
+	}
+
+	arg := repository.ListOrderDraftsParams{
+		StoreID: pgtype.UUID{Bytes: filter.StoreID, Valid: true},
+	}
+	if filter.CashierID != nil {
+		arg.CashierID = pgtype.UUID{Bytes: *filter.CashierID, Valid: true}
+	}
+	if filter.TerminalID != nil {
+		arg.TerminalID = pgtype.Text{String: *filter.TerminalID, Valid: true}
+	}
+
+	rows, err := uc.store.ListOrderDrafts(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]domain.OrderDraft, 0, len(rows))
+	for _, row := range rows {
+		draft, err := toDomainOrderDraft(row)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *draft)
+	}
+	return res, nil
+}
+
+func (uc *orderDraftUsecase) DiscardDraft(ctx context.Context, draftID uuid.UUID) error {
+	if _, err := uc.GetDraft(ctx, draftID); err != nil {
+		return err
+	}
+	n, err := uc.store.DeleteOrderDraft(ctx, pgtype.UUID{Bytes: draftID, Valid: true})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("draft not found or expired")
+	}
+	return nil
+}
+
+// ConvertDraft submits the held order. The draft ID doubles as idempotency key,
+// so a retried conversion returns the same order instead of a second one.
+func (uc *orderDraftUsecase) ConvertDraft(ctx context.Context, draftID uuid.UUID, terminalID *uuid.UUID) (*domain.Order, error) {
+	draft, err := uc.GetDraft(ctx, draftID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := draft.Order
+	req.StoreID = draft.StoreID
+	req.IdempotencyKey = "draft:" + draft.ID.String()
+	req.TerminalID = terminalID
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("draft has no items")
+	}
+
+	order, err := uc.orderUsecase.CreateOrder(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.store.DeleteOrderDraft(ctx, pgtype.UUID{Bytes: draftID, Valid: true}); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (uc *orderDraftUsecase) PurgeExpired(ctx context.Context) (int64, error) {
+	return uc.store.DeleteExpiredOrderDrafts(ctx)
+}
+
+func toDomainOrderDraft(d repository.OrderDraft) (*domain.OrderDraft, error) {
+	draft := &domain.OrderDraft{
+		ID:         uuid.UUID(d.ID.Bytes),
+		StoreID:    uuid.UUID(d.StoreID.Bytes),
+		CashierID:  uuid.UUID(d.CashierID.Bytes),
+		TerminalID: d.TerminalID,
+		Label:      d.Label,
+		ExpiresAt:  d.ExpiresAt.Time,
+		CreatedAt:  d.CreatedAt.Time,
+		UpdatedAt:  d.UpdatedAt.Time,
+	}
+	if err := json.Unmarshal(d.Payload, &draft.Order); err != nil {
+		return nil, fmt.Errorf("failed to read draft payload: %w", err)
+	}
+	return draft, nil
+}
@@ -0,0 +1,524 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type kitchenUsecase struct {
+	store    repository.Repository
+	eventSvc domain.EventService
+}
+
+func NewKitchenUsecase(store repository.Repository, eventSvc domain.EventService) domain.KitchenUsecase {
+	return &kitchenUsecase{
+		store:    store,
+		eventSvc: eventSvc,
+	}
+}
+
+func (uc *kitchenUsecase) CreateStation(ctx context.Context, req *domain.CreateStationRequest) (*domain.KitchenStation, error) {
+	if err := checkStore(ctx, req.StoreID); err != nil {
+		return nil, err
+	}
+
+	s, err := uc.store.CreateKitchenStation(ctx, repository.CreateKitchenStationParams{
+		StoreID:   pgtype.UUID{Bytes: req.StoreID, Valid: true},
+		Name:      req.Name,
+		Code:      req.Code,
+		IsDefault: req.IsDefault,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	station := toDomainKitchenStation(s)
+	return &station, nil
+}
+
+func (uc *kitchenUsecase) ListStations(ctx context.Context, storeID uuid.UUID) ([]domain.KitchenStation, error) {
+	if err := checkStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	rows, err := uc.store.ListKitchenStations(ctx, pgtype.UUID{Bytes: storeID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]domain.KitchenStation, 0, len(rows))
+	for _, s := range rows {
+		res = append(res, toDomainKitchenStation(s))
+	}
+	return res, nil
+}
+
+func (uc *kitchenUsecase) AddStationRoute(ctx context.Context, stationID uuid.UUID, req *domain.CreateStationRouteRequest) (*domain.KitchenStationRoute, error) {
+	if req.ProductID == nil && req.CategoryID == nil {
+		return nil, fmt.Errorf("either product_id or category_id is required")
+	}
+
+	if err := uc.checkStation(ctx, pgtype.UUID{Bytes: stationID, Valid: true}); err != nil {
+		return nil, err
+	}
+
+	var productID, categoryID pgtype.UUID
+	if req.ProductID != nil {
+		productID = pgtype.UUID{Bytes: *req.ProductID, Valid: true}
+	}
+	if req.CategoryID != nil {
+		categoryID = pgtype.UUID{Bytes: *req.CategoryID, Valid: true}
+	}
+
+	r, err := uc.store.CreateKitchenStationRoute(ctx, repository.CreateKitchenStationRouteParams{
+		StationID:  pgtype.UUID{Bytes: stationID, Valid: true},
+		ProductID:  productID,
+		CategoryID: categoryID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	route := toDomainKitchenStationRoute(r)
+	return &route, nil
+}
+
+func (uc *kitchenUsecase) ListStationRoutes(ctx context.Context, stationID uuid.UUID) ([]domain.KitchenStationRoute, error) {
+	if err := uc.checkStation(ctx, pgtype.UUID{Bytes: stationID, Valid: true}); err != nil {
+		return nil, err
+	}
+	rows, err := uc.store.ListKitchenStationRoutes(ctx, pgtype.UUID{Bytes: stationID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]domain.KitchenStationRoute, 0, len(rows))
+	for _, r := range rows {
+		res = append(res, toDomainKitchenStationRoute(r))
+	}
+	return res, nil
+}
+
+func (uc *kitchenUsecase) DeleteStationRoute(ctx context.Context, routeID uuid.UUID) error {
+	route, err := uc.store.GetKitchenStationRoute(ctx, pgtype.UUID{Bytes: routeID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("route not found")
+	}
+	if err := uc.checkStation(ctx, route.StationID); err != nil {
+		return err
+	}
+	return uc.store.DeleteKitchenStationRoute(ctx, route.ID)
+}
+
+func (uc *kitchenUsecase) ListStationTickets(ctx context.Context, stationID uuid.UUID) ([]domain.KitchenTicket, error) {
+	if err := uc.checkStation(ctx, pgtype.UUID{Bytes: stationID, Valid: true}); err != nil {
+		return nil, err
+	}
+	rows, err := uc.store.ListActiveKitchenTickets(ctx, pgtype.UUID{Bytes: stationID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]domain.KitchenTicket, 0, len(rows))
+	for _, row := range rows {
+		ticket := toDomainKitchenTicket(repository.KitchenTicket{
+			ID:        row.ID,
+			OrderID:   row.OrderID,
+			StationID: row.StationID,
+			Status:    row.Status,
+			BumpedAt:  row.BumpedAt,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+			StartedAt: row.StartedAt,
+		})
+		ticket.StoreID = uuid.UUID(row.StoreID.Bytes)
+		ticket.OrderNumber = row.OrderNumber
+		ticket.OrderType = domain.OrderType(row.OrderType)
+
+		items, err := uc.store.ListTicketItems(ctx, row.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			ticket.Items = append(ticket.Items, toDomainOrderItem(item))
+		}
+		res = append(res, ticket)
+	}
+	return res, nil
+}
+
+// UpdateTicketStatus starts, bumps or recalls a whole station ticket.
+// All items on the ticket follow the ticket status.
+func (uc *kitchenUsecase) UpdateTicketStatus(ctx context.Context, ticketID uuid.UUID, status domain.KitchenStatus, userID uuid.UUID) (*domain.KitchenTicket, error) {
+	var ticket domain.KitchenTicket
+	var orderUpdate *domain.Order
+
+	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		dbTicket, err := q.GetKitchenTicket(ctx, pgtype.UUID{Bytes: ticketID, Valid: true})
+		if err != nil {
+			return fmt.Errorf("ticket not found")
+		}
+
+		dbOrder, err := lockTicketOrder(ctx, q, dbTicket.OrderID)
+		if err != nil {
+			return err
+		}
+		// Read again under the lock, another station may have changed it
+		dbTicket, err = q.GetKitchenTicket(ctx, dbTicket.ID)
+		if err != nil {
+			return fmt.Errorf("ticket not found")
+		}
+
+		if err := validateKitchenChange(ctx, q, dbOrder, domain.KitchenStatus(dbTicket.Status), status); err != nil {
+			return err
+		}
+
+		dbTicket, err = q.UpdateKitchenTicketStatus(ctx, repository.UpdateKitchenTicketStatusParams{
+			ID:     dbTicket.ID,
+			Status: string(status),
+		})
+		if err != nil {
+			return err
+		}
+
+		items, err := q.ListTicketItems(ctx, dbTicket.ID)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := recordItemStatusChange(ctx, q, item, status, userID); err != nil {
+				return err
+			}
+		}
+
+		err = q.UpdateTicketItemsStatus(ctx, repository.UpdateTicketItemsStatusParams{
+			TicketID: dbTicket.ID,
+			Status:   string(status),
+		})
+		if err != nil {
+			return err
+		}
+
+		orderUpdate, err = syncOrderWithTickets(ctx, q, dbOrder, status, userID)
+		if err != nil {
+			return err
+		}
+
+		ticket, err = loadKitchenTicket(ctx, q, dbTicket, dbOrder)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.publishTicketUpdate(ctx, ticket, orderUpdate)
+	return &ticket, nil
+}
+
+// UpdateItemStatus changes one item on a ticket. The ticket is bumped
+// automatically once all of its items are READY.
+func (uc *kitchenUsecase) UpdateItemStatus(ctx context.Context, itemID uuid.UUID, status domain.KitchenStatus, userID uuid.UUID) (*domain.KitchenTicket, error) {
+	var ticket domain.KitchenTicket
+	var orderUpdate *domain.Order
+
+	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		dbItem, err := q.GetOrderItem(ctx, pgtype.UUID{Bytes: itemID, Valid: true})
+		if err != nil {
+			return fmt.Errorf("order item not found")
+		}
+
+		dbOrder, err := lockTicketOrder(ctx, q, dbItem.OrderID)
+		if err != nil {
+			return err
+		}
+		// Read again under the lock, another station may have changed it
+		dbItem, err = q.GetOrderItem(ctx, dbItem.ID)
+		if err != nil {
+			return fmt.Errorf("order item not found")
+		}
+		if !dbItem.TicketID.Valid {
+			return fmt.Errorf("order item is not routed to a kitchen station")
+		}
+
+		dbTicket, err := q.GetKitchenTicket(ctx, dbItem.TicketID)
+		if err != nil {
+			return fmt.Errorf("ticket not found")
+		}
+
+		if err := validateKitchenChange(ctx, q, dbOrder, domain.KitchenStatus(dbItem.Status), status); err != nil {
+			return err
+		}
+
+		_, err = q.UpdateOrderItemStatus(ctx, repository.UpdateOrderItemStatusParams{
+			ID:     dbItem.ID,
+			Status: string(status),
+		})
+		if err != nil {
+			return err
+		}
+		if err := recordItemStatusChange(ctx, q, dbItem, status, userID); err != nil {
+			return err
+		}
+
+		// Derive the ticket status from its items
+		openItems, err := q.CountOpenTicketItems(ctx, dbTicket.ID)
+		if err != nil {
+			return err
+		}
+
+		current := domain.KitchenStatus(dbTicket.Status)
+		next := current
+		switch {
+		case openItems == 0:
+			next = domain.KitchenStatusReady
+		case current != domain.KitchenStatusCooking:
+			next = domain.KitchenStatusCooking
+		}
+
+		if next != current {
+			dbTicket, err = q.UpdateKitchenTicketStatus(ctx, repository.UpdateKitchenTicketStatusParams{
+				ID:     dbTicket.ID,
+				Status: string(next),
+			})
+			if err != nil {
+				return err
+			}
+
+			orderUpdate, err = syncOrderWithTickets(ctx, q, dbOrder, next, userID)
+			if err != nil {
+				return err
+			}
+		}
+
+		ticket, err = loadKitchenTicket(ctx, q, dbTicket, dbOrder)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.publishTicketUpdate(ctx, ticket, orderUpdate)
+	return &ticket, nil
+}
+
+// checkStation rejects stations of stores outside the caller's stores.
+func (uc *kitchenUsecase) checkStation(ctx context.Context, stationID pgtype.UUID) error {
+	station, err := uc.store.GetKitchenStation(ctx, stationID)
+	if err != nil {
+		return fmt.Errorf("station not found")
+	}
+	return checkStoreID(ctx, station.StoreID)
+}
+
+func (uc *kitchenUsecase) publishTicketUpdate(ctx context.Context, ticket domain.KitchenTicket, orderUpdate *domain.Order) {
+	_ = uc.eventSvc.PublishEvent(ctx, "KDS_TICKET_UPDATED", ticket)
+	if orderUpdate != nil {
+		_ = uc.eventSvc.PublishEvent(ctx, "ORDER_STATUS_UPDATED", orderUpdate)
+	}
+}
+
+// validateKitchenChange rejects KDS changes on orders that are finished in
+// their store workflow, not accepted yet or not released to the kitchen.
+func validateKitchenChange(ctx context.Context, q *repository.Queries, dbOrder repository.Order, current, next domain.KitchenStatus) error {
+	workflow, err := loadWorkflow(ctx, q, dbOrder.StoreID, dbOrder.OrderType)
+	if err != nil {
+		return err
+	}
+	orderStatus := domain.OrderStatus(dbOrder.Status)
+	if isTerminalOrderStatus(workflow, orderStatus) {
+		return fmt.Errorf("order is already %s", orderStatus)
+	}
+	if orderStatus == domain.OrderStatusNew {
+		return fmt.Errorf("order has not been accepted yet")
+	}
+	if !dbOrder.KitchenReleasedAt.Valid {
+		return fmt.Errorf("pre-order is not released to the kitchen yet")
+	}
+	if !isValidKitchenTransition(current, next) {
+		return fmt.Errorf("invalid kitchen status transition from %s to %s", current, next)
+	}
+	return nil
+}
+
+// lockTicketOrder locks the order of a ticket before its tickets change.
+// Bumps from several stations then run one after another, so the last one
+// sees every other ticket READY and moves the order on.
+func lockTicketOrder(ctx context.Context, q *repository.Queries, orderID pgtype.UUID) (repository.Order, error) {
+	dbOrder, err := q.GetOrderForUpdate(ctx, orderID)
+	if err != nil {
+		return repository.Order{}, fmt.Errorf("order not found")
+	}
+	if err := checkStoreID(ctx, dbOrder.StoreID); err != nil {
+		return repository.Order{}, err
+	}
+	return dbOrder, nil
+}
+
+// syncOrderWithTickets moves the order along with its station tickets:
+// the first ticket started (or a recall) puts it in COOKING, and the last
+// ticket bumped puts it in READY. Returns nil when the order is unchanged.
+func syncOrderWithTickets(ctx context.Context, q *repository.Queries, dbOrder repository.Order, ticketStatus domain.KitchenStatus, userID uuid.UUID) (*domain.Order, error) {
+	current := domain.OrderStatus(dbOrder.Status)
+	var next domain.OrderStatus
+
+	switch ticketStatus {
+	case domain.KitchenStatusCooking:
+		if current == domain.OrderStatusAccepted || current == domain.OrderStatusReady {
+			next = domain.OrderStatusCooking
+		}
+	case domain.KitchenStatusReady:
+		openTickets, err := q.CountOpenKitchenTickets(ctx, dbOrder.ID)
+		if err != nil {
+			return nil, err
+		}
+		if openTickets == 0 && (current == domain.OrderStatusAccepted || current == domain.OrderStatusCooking) {
+			next = domain.OrderStatusReady
+		}
+	}
+
+	if next == "" {
+		return nil, nil
+	}
+
+	// Stores with a custom workflow may skip the KDS driven stages
+	workflow, err := loadWorkflow(ctx, q, dbOrder.StoreID, dbOrder.OrderType)
+	if err != nil {
+		return nil, err
+	}
+	transition := workflow.Find(current, next)
+	if transition == nil {
+		return nil, nil
+	}
+
+	updated, err := q.UpdateOrderStatus(ctx, repository.UpdateOrderStatusParams{
+		Status:          string(next),
+		ID:              dbOrder.ID,
+		ExpectedVersion: dbOrder.Version,
+		ExpectedStatus:  dbOrder.Status,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, orderConflict(ctx, q, dbOrder.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	reason := "kds: first station started cooking"
+	switch {
+	case next == domain.OrderStatusReady:
+		reason = "kds: all stations bumped"
+	case current == domain.OrderStatusReady:
+		reason = "kds: ticket recalled"
+	}
+	err = recordStatusChange(ctx, q, dbOrder.ID, current, next, statusChange{
+		UserID: userID,
+		Source: domain.StatusSourceSystem,
+		Reason: reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if transition.Has(domain.SideEffectDeductStock) {
+		if err := applyStockDeduction(ctx, q, updated.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	order := toDomainOrder(updated)
+	return &order, nil
+}
+
+func loadKitchenTicket(ctx context.Context, q *repository.Queries, dbTicket repository.KitchenTicket, dbOrder repository.Order) (domain.KitchenTicket, error) {
+	ticket := toDomainKitchenTicket(dbTicket)
+	ticket.StoreID = uuid.UUID(dbOrder.StoreID.Bytes)
+	ticket.OrderNumber = dbOrder.OrderNumber
+	ticket.OrderType = domain.OrderType(dbOrder.OrderType)
+
+	items, err := q.ListTicketItems(ctx, dbTicket.ID)
+	if err != nil {
+		return ticket, err
+	}
+	for _, item := range items {
+		ticket.Items = append(ticket.Items, toDomainOrderItem(item))
+	}
+	return ticket, nil
+}
+
+func toDomainKitchenStation(s repository.KitchenStation) domain.KitchenStation {
+	return domain.KitchenStation{
+		ID:        uuid.UUID(s.ID.Bytes),
+		StoreID:   uuid.UUID(s.StoreID.Bytes),
+		Name:      s.Name,
+		Code:      s.Code,
+		IsDefault: s.IsDefault,
+		CreatedAt: s.CreatedAt.Time,
+	}
+}
+
+func toDomainKitchenStationRoute(r repository.KitchenStationRoute) domain.KitchenStationRoute {
+	route := domain.KitchenStationRoute{
+		ID:        uuid.UUID(r.ID.Bytes),
+		StationID: uuid.UUID(r.StationID.Bytes),
+		CreatedAt: r.CreatedAt.Time,
+	}
+	if r.ProductID.Valid {
+		id := uuid.UUID(r.ProductID.Bytes)
+		route.ProductID = &id
+	}
+	if r.CategoryID.Valid {
+		id := uuid.UUID(r.CategoryID.Bytes)
+		route.CategoryID = &id
+	}
+	return route
+}
+
+func toDomainKitchenTicket(t repository.KitchenTicket) domain.KitchenTicket {
+	ticket := domain.KitchenTicket{
+		ID:        uuid.UUID(t.ID.Bytes),
+		OrderID:   uuid.UUID(t.OrderID.Bytes),
+		StationID: uuid.UUID(t.StationID.Bytes),
+		Status:    domain.KitchenStatus(t.Status),
+		CreatedAt: t.CreatedAt.Time,
+		UpdatedAt: t.UpdatedAt.Time,
+	}
+	if t.StartedAt.Valid {
+		startedAt := t.StartedAt.Time
+		ticket.StartedAt = &startedAt
+	}
+	if t.BumpedAt.Valid {
+		bumpedAt := t.BumpedAt.Time
+		ticket.BumpedAt = &bumpedAt
+	}
+	return ticket
+}
+
+func toDomainOrderItem(i repository.OrderItem) domain.OrderItem {
+	price, _ := i.ProductPrice.Float64Value()
+	total, _ := i.TotalPrice.Float64Value()
+
+	item := domain.OrderItem{
+		ID:           uuid.UUID(i.ID.Bytes),
+		OrderID:      uuid.UUID(i.OrderID.Bytes),
+		ProductID:    uuid.UUID(i.ProductID.Bytes),
+		ProductName:  i.ProductName,
+		ProductPrice: price.Float64,
+		Quantity:     i.Quantity,
+		TotalPrice:   total.Float64,
+		Note:         i.Note.String,
+		Status:       domain.KitchenStatus(i.Status),
+	}
+	if i.TicketID.Valid {
+		id := uuid.UUID(i.TicketID.Bytes)
+		item.TicketID = &id
+	}
+	return item
+}
@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// statusChange describes who moved an order and why.
+type statusChange struct {
+	UserID     uuid.UUID
+	UserRole   string
+	Source     domain.StatusChangeSource
+	Reason     string
+	TerminalID *uuid.UUID
+}
+
+// recordStatusChange writes the structured history row and the audit log for
+// an order status change. It must run in the same transaction as UpdateOrderStatus.
+func recordStatusChange(ctx context.Context, q *repository.Queries, orderID pgtype.UUID, from, to domain.OrderStatus, change statusChange) error {
+	actorID := pgtype.UUID{Bytes: change.UserID, Valid: change.UserID != uuid.Nil}
+
+	_, err := q.CreateOrderStatusHistory(ctx, repository.CreateOrderStatusHistoryParams{
+		OrderID:    orderID,
+		FromStatus: pgtype.Text{String: string(from), Valid: from != ""},
+		ToStatus:   string(to),
+		ActorID:    actorID,
+		ActorRole:  pgtype.Text{String: change.UserRole, Valid: change.UserRole != ""},
+		Source:     string(change.Source),
+		Reason:     pgtype.Text{String: change.Reason, Valid: change.Reason != ""},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record status history: %w", err)
+	}
+
+	before, _ := json.Marshal(map[string]string{"status": string(from)})
+	after, _ := json.Marshal(map[string]string{"status": string(to), "source": string(change.Source)})
+
+	_, err = q.CreateAuditLog(ctx, repository.CreateAuditLogParams{
+		UserID:     actorID,
+		Action:     "UPDATE_ORDER_STATUS",
+		Entity:     pgtype.Text{String: "Order", Valid: true},
+		EntityID:   orderID,
+		Before:     before,
+		After:      after,
+		TerminalID: uuidParam(change.TerminalID),
+	})
+	return err
+}
+
+// recordItemStatusChange logs a KDS status change of a single order item.
+func recordItemStatusChange(ctx context.Context, q *repository.Queries, item repository.OrderItem, to domain.KitchenStatus, userID uuid.UUID) error {
+	if domain.KitchenStatus(item.Status) == to {
+		return nil
+	}
+
+	_, err := q.CreateOrderItemEvent(ctx, repository.CreateOrderItemEventParams{
+		OrderID:     item.OrderID,
+		OrderItemID: item.ID,
+		EventType:   "STATUS_CHANGED",
+		FromStatus:  pgtype.Text{String: item.Status, Valid: item.Status != ""},
+		ToStatus:    pgtype.Text{String: string(to), Valid: true},
+		ActorID:     pgtype.UUID{Bytes: userID, Valid: userID != uuid.Nil},
+	})
+	return err
+}
+
+// GetTimeline merges status history, item changes and payments of an order
+// into a single chronological list.
+func (uc *orderUsecase) GetTimeline(ctx context.Context, orderID uuid.UUID) (*domain.OrderTimeline, error) {
+	id := pgtype.UUID{Bytes: orderID, Valid: true}
+
+	dbOrder, err := uc.store.GetOrder(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("order not found")
+	}
+	if err := checkStoreID(ctx, dbOrder.StoreID); err != nil {
+		return nil, err
+	}
+
+	history, err := uc.store.ListOrderStatusHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	itemEvents, err := uc.store.ListOrderItemEvents(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := uc.store.ListPaymentsByOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []domain.TimelineEntry{{
+		Type:     domain.TimelineOrderCreated,
+		At:       dbOrder.CreatedAt.Time,
+		ToStatus: string(domain.OrderStatusNew),
+		ActorID:  optionalUUID(dbOrder.CashierID),
+	}}
+
+	for _, h := range history {
+		entries = append(entries, domain.TimelineEntry{
+			Type:       domain.TimelineStatusChanged,
+			At:         h.CreatedAt.Time,
+			FromStatus: h.FromStatus.String,
+			ToStatus:   h.ToStatus,
+			ActorID:    optionalUUID(h.ActorID),
+			ActorRole:  h.ActorRole.String,
+			Source:     domain.StatusChangeSource(h.Source),
+			Reason:     h.Reason.String,
+		})
+	}
+
+	for _, e := range itemEvents {
+		entries = append(entries, domain.TimelineEntry{
+			Type:        domain.TimelineItemChanged,
+			At:          e.CreatedAt.Time,
+			FromStatus:  e.FromStatus.String,
+			ToStatus:    e.ToStatus.String,
+			ActorID:     optionalUUID(e.ActorID),
+			OrderItemID: optionalUUID(e.OrderItemID),
+			ProductName: e.ProductName,
+		})
+	}
+
+	for _, p := range payments {
+		amount, _ := p.Amount.Float64Value()
+		entry := domain.TimelineEntry{
+			Type:          domain.TimelinePaymentCreated,
+			At:            p.CreatedAt.Time,
+			ToStatus:      p.Status,
+			PaymentID:     optionalUUID(p.ID),
+			PaymentMethod: p.PaymentMethod,
+			Amount:        amount.Float64,
+		}
+		entries = append(entries, entry)
+
+		if p.PaidAt.Valid {
+			entry.Type = domain.TimelinePaymentPaid
+			entry.At = p.PaidAt.Time
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].At.Before(entries[j].At)
+	})
+
+	return &domain.OrderTimeline{
+		OrderID:     uuid.UUID(dbOrder.ID.Bytes),
+		OrderNumber: dbOrder.OrderNumber,
+		Status:      domain.OrderStatus(dbOrder.Status),
+		Entries:     entries,
+	}, nil
+}
+
+func optionalUUID(id pgtype.UUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	u := uuid.UUID(id.Bytes)
+	return &u
+}
+
+// uuidParam is the inverse of optionalUUID.
+func uuidParam(id *uuid.UUID) pgtype.UUID {
+	if id == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: *id, Valid: true}
+}
+
+func optionalTime(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
@@ -1,20 +1,63 @@
 package usecase
 
-import "pos-api/internal/domain"
+import (
+	"pos-api/internal/domain"
 
-func isValidTransition(current, next domain.OrderStatus) bool {
+	"github.com/google/uuid"
+)
+
+// defaultOrderWorkflow is the graph used when a store has not configured one:
+// NEW -> ACCEPTED -> COOKING -> READY -> DONE, and VOIDED from any open
+// state. Voiding needs the order.void permission, see UpdateStatus.
+func defaultOrderWorkflow(storeID uuid.UUID, orderType string) *domain.Workflow {
+	flow := []domain.OrderStatus{
+		domain.OrderStatusNew,
+		domain.OrderStatusAccepted,
+		domain.OrderStatusCooking,
+		domain.OrderStatusReady,
+		domain.OrderStatusDone,
+	}
+
+	w := &domain.Workflow{
+		StoreID:   storeID,
+		OrderType: orderType,
+		IsDefault: true,
+	}
+	for i := 0; i < len(flow)-1; i++ {
+		w.Transitions = append(w.Transitions,
+			domain.WorkflowTransition{From: flow[i], To: flow[i+1]},
+			domain.WorkflowTransition{From: flow[i], To: domain.OrderStatusVoided},
+		)
+	}
+	return w
+}
+
+// isValidKitchenTransition covers both ticket and item level KDS statuses.
+// READY -> COOKING is a "recall" from the expo screen.
+func isValidKitchenTransition(current, next domain.KitchenStatus) bool {
 	switch current {
-	case domain.OrderStatusNew:
-		return next == domain.OrderStatusAccepted || next == domain.OrderStatusVoided
-	case domain.OrderStatusAccepted:
-		return next == domain.OrderStatusCooking || next == domain.OrderStatusVoided
-	case domain.OrderStatusCooking:
-		return next == domain.OrderStatusReady || next == domain.OrderStatusVoided
-	case domain.OrderStatusReady:
-		return next == domain.OrderStatusDone || next == domain.OrderStatusVoided
-	case domain.OrderStatusDone, domain.OrderStatusVoided:
-		return false // Terminal states
+	case domain.KitchenStatusNew:
+		return next == domain.KitchenStatusCooking || next == domain.KitchenStatusReady
+	case domain.KitchenStatusCooking:
+		return next == domain.KitchenStatusReady
+	case domain.KitchenStatusReady:
+		return next == domain.KitchenStatusCooking
 	default:
 		return false
 	}
 }
+
+// isTerminalOrderStatus reports whether an order in status can no longer move
+// in workflow w. DONE and VOIDED always are; a custom status is terminal when
+// the graph has no transition out of it.
+func isTerminalOrderStatus(w *domain.Workflow, status domain.OrderStatus) bool {
+	if status == domain.OrderStatusDone || status == domain.OrderStatusVoided {
+		return true
+	}
+	for _, t := range w.Transitions {
+		if t.From == status {
+			return false
+		}
+	}
+	return true
+}
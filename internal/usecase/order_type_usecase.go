@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type orderTypeUsecase struct {
+	store repository.Repository
+}
+
+func NewOrderTypeUsecase(store repository.Repository) domain.OrderTypeUsecase {
+	return &orderTypeUsecase{
+		store: store,
+	}
+}
+
+func (uc *orderTypeUsecase) SetCharges(ctx context.Context, req *domain.SetOrderTypeChargesRequest) ([]domain.OrderTypeCharge, error) {
+	if err := checkStore(ctx, req.StoreID); err != nil {
+		return nil, err
+	}
+
+	for _, c := range req.Charges {
+		if !c.OrderType.IsValid() {
+			return nil, fmt.Errorf("unknown order type %s", c.OrderType)
+		}
+	}
+
+	storeID := pgtype.UUID{Bytes: req.StoreID, Valid: true}
+	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		for _, c := range req.Charges {
+			if c.TaxRate == 0 && c.ServiceChargeRate == 0 {
+				err := q.DeleteOrderTypeCharge(ctx, repository.DeleteOrderTypeChargeParams{
+					StoreID:   storeID,
+					OrderType: string(c.OrderType),
+				})
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			_, err := q.UpsertOrderTypeCharge(ctx, repository.UpsertOrderTypeChargeParams{
+				StoreID:           storeID,
+				OrderType:         string(c.OrderType),
+				TaxRate:           moneyNumeric(c.TaxRate),
+				ServiceChargeRate: moneyNumeric(c.ServiceChargeRate),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.GetCharges(ctx, req.StoreID)
+}
+
+func (uc *orderTypeUsecase) GetCharges(ctx context.Context, storeID uuid.UUID) ([]domain.OrderTypeCharge, error) {
+	if err := checkStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	rows, err := uc.store.ListOrderTypeCharges(ctx, pgtype.UUID{Bytes: storeID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]domain.OrderTypeCharge, 0, len(rows))
+	for _, row := range rows {
+		tax, _ := row.TaxRate.Float64Value()
+		service, _ := row.ServiceChargeRate.Float64Value()
+		res = append(res, domain.OrderTypeCharge{
+			StoreID:           uuid.UUID(row.StoreID.Bytes),
+			OrderType:         domain.OrderType(row.OrderType),
+			TaxRate:           tax.Float64,
+			ServiceChargeRate: service.Float64,
+			UpdatedAt:         row.UpdatedAt.Time,
+		})
+	}
+	return res, nil
+}
+
+func (uc *orderTypeUsecase) GetSalesReport(ctx context.Context, storeID uuid.UUID, from, to time.Time) (*domain.SalesByOrderTypeReport, error) {
+	if err := checkStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	if !to.After(from) {
+		return nil, fmt.Errorf("invalid date range")
+	}
+
+	rows, err := uc.store.GetSalesByOrderType(ctx, repository.GetSalesByOrderTypeParams{
+		StoreID:  pgtype.UUID{Bytes: storeID, Valid: true},
+		FromDate: pgtype.Timestamptz{Time: from, Valid: true},
+		ToDate:   pgtype.Timestamptz{Time: to, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.SalesByOrderTypeReport{
+		StoreID: storeID,
+		From:    from,
+		To:      to,
+		Types:   make([]domain.SalesByOrderType, 0, len(rows)),
+	}
+	for _, row := range rows {
+		report.Types = append(report.Types, domain.SalesByOrderType{
+			OrderType:     domain.OrderType(row.OrderType),
+			OrdersCount:   row.OrdersCount,
+			GrossSales:    row.GrossSales,
+			ServiceCharge: row.ServiceCharge,
+			Tax:           row.Tax,
+			NetSales:      row.NetSales,
+		})
+	}
+	return report, nil
+}
+
+// resolveOrderType applies the default order type and checks that the
+// type specific data is present.
+func resolveOrderType(req *domain.CreateOrderRequest) (domain.OrderType, error) {
+	orderType := req.OrderType
+	if orderType == "" {
+		orderType = domain.OrderTypeTakeaway
+		if req.TableSessionID != nil || req.TableID != nil {
+			orderType = domain.OrderTypeDineIn
+		}
+	}
+
+	switch orderType {
+	case domain.OrderTypeDineIn:
+		if req.TableSessionID == nil && req.TableID == nil {
+			return "", fmt.Errorf("dine-in orders need a table_id or table_session_id")
+		}
+	case domain.OrderTypePickup:
+		if req.CustomerName == "" || req.CustomerPhone == "" {
+			return "", fmt.Errorf("pickup orders need customer_name and customer_phone")
+		}
+	case domain.OrderTypeDelivery:
+		if req.DeliveryAddress == "" {
+			return "", fmt.Errorf("delivery orders need a delivery_address")
+		}
+	case domain.OrderTypeTakeaway:
+	default:
+		return "", fmt.Errorf("unknown order type %s", orderType)
+	}
+
+	// Only dine-in orders sit at a table
+	if orderType != domain.OrderTypeDineIn && (req.TableSessionID != nil || req.TableID != nil) {
+		return "", fmt.Errorf("%s orders cannot have a table_id or table_session_id", orderType)
+	}
+	return orderType, nil
+}
+
+// calculateCharges returns service charge and tax for an item total.
+// Order types without a configured rule are not charged.
+func calculateCharges(ctx context.Context, q *repository.Queries, storeID uuid.UUID, orderType domain.OrderType, total float64) (float64, float64, error) {
+	rule, err := q.GetOrderTypeCharge(ctx, repository.GetOrderTypeChargeParams{
+		StoreID:   pgtype.UUID{Bytes: storeID, Valid: true},
+		OrderType: string(orderType),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	serviceRate, _ := rule.ServiceChargeRate.Float64Value()
+	taxRate, _ := rule.TaxRate.Float64Value()
+
+	serviceCharge := math.Round(total*serviceRate.Float64) / 100
+	tax := math.Round((total+serviceCharge)*taxRate.Float64) / 100
+	return serviceCharge, tax, nil
+}
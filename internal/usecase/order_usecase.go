@@ -3,30 +3,40 @@ package usecase
 import (
 	"context" // Keeping context as it's used throughout the file. The instruction to remove it seems to be based on a misunderstanding or an incomplete example.
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"strings"
 	"time"
 
 	"pos-api/internal/domain"
 	"pos-api/internal/repository"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type orderUsecase struct {
-	store    repository.Repository
-	eventSvc domain.EventService
+	store       repository.Repository
+	eventSvc    domain.EventService
+	permissions domain.PermissionUsecase
 }
 
-func NewOrderUsecase(store repository.Repository, eventSvc domain.EventService) domain.OrderUsecase {
+func NewOrderUsecase(store repository.Repository, eventSvc domain.EventService, permissions domain.PermissionUsecase) domain.OrderUsecase {
 	return &orderUsecase{
-		store:    store,
-		eventSvc: eventSvc,
+		store:       store,
+		eventSvc:    eventSvc,
+		permissions: permissions,
 	}
 }
 
 func (uc *orderUsecase) CreateOrder(ctx context.Context, req *domain.CreateOrderRequest) (*domain.Order, error) {
+	if err := checkStore(ctx, req.StoreID); err != nil {
+		return nil, err
+	}
+
 	// 1. Idempotency Check
 	if req.IdempotencyKey != "" {
 		existing, err := uc.store.GetIdempotencyKey(ctx, req.IdempotencyKey)
@@ -34,6 +44,9 @@ func (uc *orderUsecase) CreateOrder(ctx context.Context, req *domain.CreateOrder
 			// Found existing key, return cached order
 			var cachedOrder domain.Order
 			if jsonErr := json.Unmarshal(existing.ResponseBody, &cachedOrder); jsonErr == nil {
+				if err := checkStore(ctx, cachedOrder.StoreID); err != nil {
+					return nil, err
+				}
 				return &cachedOrder, nil
 			}
 			// If unmarshal fails, we proceed to recreate (or log error) - treating as new for safety or erroring?
@@ -42,16 +55,22 @@ func (uc *orderUsecase) CreateOrder(ctx context.Context, req *domain.CreateOrder
 		}
 	}
 
+	orderType, err := resolveOrderType(req)
+	if err != nil {
+		return nil, err
+	}
+
 	var order domain.Order
 
-	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+	err = uc.store.ExecTx(ctx, func(q *repository.Queries) error {
 		// 1. Validate Products & Calculate Total
 		var totalAmount float64
 		var orderItems []domain.OrderItem
+		var itemStations []pgtype.UUID // Parallel to orderItems; invalid when the store has no KDS routing
 
 		for _, itemReq := range req.Items {
 			product, err := q.GetProduct(ctx, pgtype.UUID{Bytes: itemReq.ProductID, Valid: true})
-			if err != nil {
+			if err != nil || (product.StoreID.Valid && uuid.UUID(product.StoreID.Bytes) != req.StoreID) {
 				return fmt.Errorf("product not found: %s", itemReq.ProductID)
 			}
 
@@ -70,38 +89,123 @@ func (uc *orderUsecase) CreateOrder(ctx context.Context, req *domain.CreateOrder
 				Quantity:     itemReq.Quantity,
 				TotalPrice:   itemTotal,
 				Note:         itemReq.Note,
+				Status:       domain.KitchenStatusNew,
 			})
+
+			station, err := q.ResolveKitchenStation(ctx, repository.ResolveKitchenStationParams{
+				StoreID:    pgtype.UUID{Bytes: req.StoreID, Valid: true},
+				ProductID:  product.ID,
+				CategoryID: product.CategoryID,
+			})
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("failed to route item to kitchen station: %w", err)
+			}
+			itemStations = append(itemStations, station.ID)
 		}
 
-		// 2. Create Order Header
-		totalAmountNumeric := pgtype.Numeric{Int: big.NewInt(int64(totalAmount * 100)), Exp: -2, Valid: true}
+		// 2. Tax & service charge for the order type
+		serviceCharge, tax, err := calculateCharges(ctx, q, req.StoreID, orderType, totalAmount)
+		if err != nil {
+			return err
+		}
+		finalAmount := totalAmount + serviceCharge + tax
+
+		// 3. Pre-orders stay off the KDS until the release scheduler picks them up
+		var scheduledFor pgtype.Timestamptz
+		releasedAt := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		if req.ScheduledFor != nil {
+			if err := validateSchedule(ctx, q, req.StoreID, *req.ScheduledFor); err != nil {
+				return err
+			}
+			scheduledFor = pgtype.Timestamptz{Time: *req.ScheduledFor, Valid: true}
+			releasedAt = pgtype.Timestamptz{}
+		}
+
+		// 4. Create Order Header
 		orderNumber := fmt.Sprintf("ORD-%d", time.Now().Unix())
 
-		var sessionID pgtype.UUID
+		var sessionID, tableID pgtype.UUID
+		if req.TableID != nil {
+			tableID = pgtype.UUID{Bytes: *req.TableID, Valid: true}
+		}
 		if req.TableSessionID != nil {
-			sessionID = pgtype.UUID{Bytes: *req.TableSessionID, Valid: true}
+			// Locked so the session cannot be closed while this order is added
+			session, err := q.GetSessionForUpdate(ctx, pgtype.UUID{Bytes: *req.TableSessionID, Valid: true})
+			if err != nil || !session.IsActive.Bool {
+				return fmt.Errorf("table session is not active")
+			}
+			if !session.ExpiresAt.Time.After(time.Now()) {
+				return fmt.Errorf("table session has expired")
+			}
+			sessionID, tableID = session.ID, session.TableID
+		}
+		if tableID.Valid {
+			table, err := q.GetTable(ctx, tableID)
+			if err != nil || uuid.UUID(table.StoreID.Bytes) != req.StoreID {
+				return fmt.Errorf("table not found")
+			}
 		}
 
 		// Use StoreID instead of OutletID
 		dbOrder, err := q.CreateOrder(ctx, repository.CreateOrderParams{
-			StoreID:        pgtype.UUID{Bytes: req.StoreID, Valid: true},
-			TableSessionID: sessionID,
-			OrderNumber:    orderNumber,
-			TotalAmount:    totalAmountNumeric,
-			FinalAmount:    totalAmountNumeric,
-			Note:           pgtype.Text{String: req.Note, Valid: req.Note != ""},
-			// Status and PaymentStatus default in DB
+			StoreID:             pgtype.UUID{Bytes: req.StoreID, Valid: true},
+			TableSessionID:      sessionID,
+			OrderNumber:         orderNumber,
+			TotalAmount:         moneyNumeric(totalAmount),
+			TaxAmount:           moneyNumeric(tax),
+			DiscountAmount:      moneyNumeric(0),
+			FinalAmount:         moneyNumeric(finalAmount),
+			Note:                pgtype.Text{String: req.Note, Valid: req.Note != ""},
+			Status:              string(domain.OrderStatusNew),
+			PaymentStatus:       string(domain.PaymentStatusUnpaid),
+			OrderType:           string(orderType),
+			TableID:             tableID,
+			CustomerName:        pgtype.Text{String: req.CustomerName, Valid: req.CustomerName != ""},
+			CustomerPhone:       pgtype.Text{String: req.CustomerPhone, Valid: req.CustomerPhone != ""},
+			DeliveryAddress:     pgtype.Text{String: req.DeliveryAddress, Valid: req.DeliveryAddress != ""},
+			Courier:             pgtype.Text{String: req.Courier, Valid: req.Courier != ""},
+			ServiceChargeAmount: moneyNumeric(serviceCharge),
+			ScheduledFor:        scheduledFor,
+			KitchenReleasedAt:   releasedAt,
+			TerminalID:          uuidParam(req.TerminalID),
 		})
 		if err != nil {
 			return err
 		}
 
-		// 3. Create Order Items
-		for _, item := range orderItems {
+		// 5. Create Kitchen Tickets (one per station) and Order Items
+		var tickets []domain.KitchenTicket
+		ticketIndex := make(map[uuid.UUID]int)
+		for i := range orderItems {
+			item := &orderItems[i]
+			var ticketID pgtype.UUID
+
+			if itemStations[i].Valid {
+				stationID := uuid.UUID(itemStations[i].Bytes)
+				idx, ok := ticketIndex[stationID]
+				if !ok {
+					dbTicket, err := q.CreateKitchenTicket(ctx, repository.CreateKitchenTicketParams{
+						OrderID:   dbOrder.ID,
+						StationID: itemStations[i],
+					})
+					if err != nil {
+						return fmt.Errorf("failed to create kitchen ticket: %w", err)
+					}
+					ticket := toDomainKitchenTicket(dbTicket)
+					ticket.StoreID = uuid.UUID(dbOrder.StoreID.Bytes)
+					ticket.OrderNumber = dbOrder.OrderNumber
+					ticket.OrderType = orderType
+					tickets = append(tickets, ticket)
+					idx = len(tickets) - 1
+					ticketIndex[stationID] = idx
+				}
+				ticketID = pgtype.UUID{Bytes: tickets[idx].ID, Valid: true}
+			}
+
 			itemPrice := pgtype.Numeric{Int: big.NewInt(int64(item.ProductPrice * 100)), Exp: -2, Valid: true}
 			itemTotal := pgtype.Numeric{Int: big.NewInt(int64(item.TotalPrice * 100)), Exp: -2, Valid: true}
 
-			_, err := q.CreateOrderItem(ctx, repository.CreateOrderItemParams{
+			dbItem, err := q.CreateOrderItem(ctx, repository.CreateOrderItemParams{
 				OrderID:      dbOrder.ID,
 				ProductID:    pgtype.UUID{Bytes: item.ProductID, Valid: true},
 				ProductName:  item.ProductName,
@@ -109,25 +213,28 @@ func (uc *orderUsecase) CreateOrder(ctx context.Context, req *domain.CreateOrder
 				Quantity:     item.Quantity,
 				TotalPrice:   itemTotal,
 				Note:         pgtype.Text{String: item.Note, Valid: item.Note != ""},
+				TicketID:     ticketID,
 			})
 			if err != nil {
 				return err
 			}
+
+			item.ID = uuid.UUID(dbItem.ID.Bytes)
+			item.OrderID = uuid.UUID(dbItem.OrderID.Bytes)
+			if ticketID.Valid {
+				tID := uuid.UUID(ticketID.Bytes)
+				item.TicketID = &tID
+				idx := ticketIndex[uuid.UUID(itemStations[i].Bytes)]
+				tickets[idx].Items = append(tickets[idx].Items, *item)
+			}
 		}
 
 		// Populate return struct
-		tVal, _ := dbOrder.TotalAmount.Float64Value()
-		order = domain.Order{
-			ID:          uuid.UUID(dbOrder.ID.Bytes),
-			StoreID:     uuid.UUID(dbOrder.StoreID.Bytes),
-			OrderNumber: dbOrder.OrderNumber,
-			Status:      domain.OrderStatus(dbOrder.Status),
-			TotalAmount: tVal.Float64,
-			Items:       orderItems,
-			CreatedAt:   dbOrder.CreatedAt.Time,
-		}
-
-		// 4. Save Idempotency Key (Inside Tx for consistency)
+		order = toDomainOrder(dbOrder)
+		order.Items = orderItems
+		order.Tickets = tickets
+
+		// 6. Save Idempotency Key (Inside Tx for consistency)
 		if req.IdempotencyKey != "" {
 			jsonBytes, _ := json.Marshal(order)
 			_, err = q.CreateIdempotencyKey(ctx, repository.CreateIdempotencyKeyParams{
@@ -147,55 +254,127 @@ func (uc *orderUsecase) CreateOrder(ctx context.Context, req *domain.CreateOrder
 		return nil, err
 	}
 
-	// Publish Realtime Event
-	_ = uc.eventSvc.PublishEvent(ctx, "NEW_ORDER", order)
+	// Publish Realtime Event; pre-orders are announced when they are released
+	if order.KitchenReleasedAt != nil {
+		_ = uc.eventSvc.PublishEvent(ctx, "NEW_ORDER", order)
+		for _, ticket := range order.Tickets {
+			_ = uc.eventSvc.PublishEvent(ctx, "KDS_TICKET_CREATED", ticket)
+		}
+	}
 
 	return &order, nil
 }
 
-func (uc *orderUsecase) UpdateStatus(ctx context.Context, orderID uuid.UUID, status domain.OrderStatus, userID uuid.UUID, userRole string) (*domain.Order, error) {
-	// 1. Get current order to validate transition
-	currentOrder, err := uc.store.GetOrder(ctx, pgtype.UUID{Bytes: orderID, Valid: true})
-	if err != nil {
-		return nil, fmt.Errorf("order not found")
-	}
+func (uc *orderUsecase) UpdateStatus(ctx context.Context, orderID uuid.UUID, req *domain.UpdateOrderStatusRequest) (*domain.Order, error) {
+	status := req.Status
 
-	// 2. Validate state transition
-	if !isValidTransition(domain.OrderStatus(currentOrder.Status), status) {
-		return nil, fmt.Errorf("invalid status transition from %s to %s", currentOrder.Status, status)
-	}
+	var order domain.Order
+	var transition *domain.WorkflowTransition
+	var userRole string
 
-	// 3. SPECIAL CHECK: Voiding requires STORE_OWNER
-	if status == domain.OrderStatusVoided {
-		if userRole != string(domain.RoleStoreOwner) && userRole != string(domain.RoleSuperAdmin) {
-			return nil, fmt.Errorf("permission denied: only STORE_OWNER can void orders")
+	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		// 1. Get current order to validate transition
+		currentOrder, err := q.GetOrder(ctx, pgtype.UUID{Bytes: orderID, Valid: true})
+		if err != nil {
+			return fmt.Errorf("order not found")
+		}
+		if err := checkStoreID(ctx, currentOrder.StoreID); err != nil {
+			return err
+		}
+
+		// Client-side precondition (If-Match / expected_version)
+		if req.ExpectedVersion != nil && *req.ExpectedVersion != currentOrder.Version {
+			current := toDomainOrder(currentOrder)
+			return &domain.OrderConflictError{Current: &current}
 		}
-	}
 
-	// 3. Update status
-	dbOrder, err := uc.store.UpdateOrderStatus(ctx, repository.UpdateOrderStatusParams{
-		ID:     pgtype.UUID{Bytes: orderID, Valid: true},
-		Status: string(status),
+		// 2. Validate state transition against the store workflow
+		workflow, err := loadWorkflow(ctx, q, currentOrder.StoreID, currentOrder.OrderType)
+		if err != nil {
+			return err
+		}
+		transition = workflow.Find(domain.OrderStatus(currentOrder.Status), status)
+		if transition == nil {
+			return fmt.Errorf("invalid status transition from %s to %s", currentOrder.Status, status)
+		}
+		// System changes (e.g. self-order auto-accept) follow store settings, not
+		// roles. Everyone else is checked with the roles they hold in the
+		// order's store; any of them may allow the transition.
+		storeID := uuid.UUID(currentOrder.StoreID.Bytes)
+		var roles []string
+		if req.Actor != nil {
+			roles = req.Actor.RolesIn(storeID)
+		}
+		userRole = domain.ActingRole(roles)
+		if req.Source != domain.StatusSourceSystem && !transition.AllowsAnyRole(roles) {
+			return fmt.Errorf("permission denied: %s cannot move orders to %s", strings.Join(roles, ", "), status)
+		}
+		// Voiding also needs the order.void permission in the order's store
+		if req.Source != domain.StatusSourceSystem && status == domain.OrderStatusVoided {
+			allowed, err := uc.permissions.HasPermission(ctx, roles, &storeID, domain.PermOrderVoid)
+			if err != nil {
+				return err
+			}
+			if !allowed {
+				return fmt.Errorf("permission denied: %s is required to void orders", domain.PermOrderVoid)
+			}
+		}
+
+		// READY is driven by the KDS when the order has station tickets
+		if status == domain.OrderStatusReady {
+			openTickets, err := q.CountOpenKitchenTickets(ctx, currentOrder.ID)
+			if err != nil {
+				return err
+			}
+			if openTickets > 0 {
+				return fmt.Errorf("order still has %d kitchen station(s) that have not bumped", openTickets)
+			}
+		}
+
+		// 3. Update status
+		dbOrder, err := q.UpdateOrderStatus(ctx, repository.UpdateOrderStatusParams{
+			Status:          string(status),
+			ID:              currentOrder.ID,
+			ExpectedVersion: currentOrder.Version,
+			ExpectedStatus:  currentOrder.Status,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return orderConflict(ctx, q, currentOrder.ID)
+		}
+		if err != nil {
+			return err
+		}
+
+		// 4. History + Audit Log
+		err = recordStatusChange(ctx, q, dbOrder.ID, domain.OrderStatus(currentOrder.Status), status, statusChange{
+			UserID:     req.UserID,
+			UserRole:   userRole,
+			Source:     req.Source,
+			Reason:     req.Reason,
+			TerminalID: req.TerminalID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if transition.Has(domain.SideEffectDeductStock) {
+			if err := applyStockDeduction(ctx, q, dbOrder.ID); err != nil {
+				return err
+			}
+		}
+
+		order = toDomainOrder(dbOrder)
+		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	// 4. Audit Log
-	_, _ = uc.store.CreateAuditLog(ctx, repository.CreateAuditLogParams{
-		UserID:   pgtype.UUID{Bytes: userID, Valid: true},
-		Action:   "UPDATE_ORDER_STATUS",
-		Entity:   pgtype.Text{String: "Order", Valid: true},
-		EntityID: pgtype.UUID{Bytes: orderID, Valid: true},
-		Before:   []byte(fmt.Sprintf(`{"status": "%s"}`, currentOrder.Status)),
-		After:    []byte(fmt.Sprintf(`{"status": "%s"}`, status)),
-	})
+	if transition.Has(domain.SideEffectNotify) {
+		_ = uc.eventSvc.PublishEvent(ctx, "ORDER_STATUS_UPDATED", order)
+	}
 
-	// Create struct
-	return &domain.Order{
-		ID:     uuid.UUID(dbOrder.ID.Bytes),
-		Status: domain.OrderStatus(dbOrder.Status),
-	}, nil
+	return &order, nil
 }
 
 func (uc *orderUsecase) GetOrder(ctx context.Context, orderID uuid.UUID) (*domain.Order, error) {
@@ -203,11 +382,12 @@ func (uc *orderUsecase) GetOrder(ctx context.Context, orderID uuid.UUID) (*domai
 	if err != nil {
 		return nil, err
 	}
+	if err := checkStoreID(ctx, dbOrder.StoreID); err != nil {
+		return nil, err
+	}
 	// Fetch items too... kept simple for now
-	return &domain.Order{
-		ID:     uuid.UUID(dbOrder.ID.Bytes),
-		Status: domain.OrderStatus(dbOrder.Status),
-	}, nil
+	order := toDomainOrder(dbOrder)
+	return &order, nil
 }
 
 func (uc *orderUsecase) GetOrdersBySession(ctx context.Context, sessionID uuid.UUID) ([]domain.Order, error) {
@@ -218,6 +398,9 @@ func (uc *orderUsecase) GetOrdersBySession(ctx context.Context, sessionID uuid.U
 
 	var res []domain.Order
 	for _, o := range orders {
+		if err := checkStoreID(ctx, o.StoreID); err != nil {
+			return nil, err
+		}
 		res = append(res, domain.Order{
 			ID:          uuid.UUID(o.ID.Bytes),
 			Status:      domain.OrderStatus(o.Status),
@@ -226,3 +409,59 @@ func (uc *orderUsecase) GetOrdersBySession(ctx context.Context, sessionID uuid.U
 	}
 	return res, nil
 }
+
+func (uc *orderUsecase) PurgeIdempotencyKeys(ctx context.Context, ttl time.Duration) (int64, error) {
+	return uc.store.PurgeIdempotencyKeys(ctx, pgtype.Timestamptz{Time: time.Now().Add(-ttl), Valid: true})
+}
+
+func toDomainOrder(o repository.Order) domain.Order {
+	total, _ := o.TotalAmount.Float64Value()
+	tax, _ := o.TaxAmount.Float64Value()
+	discount, _ := o.DiscountAmount.Float64Value()
+	final, _ := o.FinalAmount.Float64Value()
+	service, _ := o.ServiceChargeAmount.Float64Value()
+
+	return domain.Order{
+		ID:                  uuid.UUID(o.ID.Bytes),
+		StoreID:             uuid.UUID(o.StoreID.Bytes),
+		TableSessionID:      optionalUUID(o.TableSessionID),
+		CashierID:           optionalUUID(o.CashierID),
+		TerminalID:          optionalUUID(o.TerminalID),
+		OrderNumber:         o.OrderNumber,
+		OrderType:           domain.OrderType(o.OrderType),
+		Status:              domain.OrderStatus(o.Status),
+		PaymentStatus:       domain.PaymentStatus(o.PaymentStatus),
+		TotalAmount:         total.Float64,
+		ServiceChargeAmount: service.Float64,
+		TaxAmount:           tax.Float64,
+		DiscountAmount:      discount.Float64,
+		FinalAmount:         final.Float64,
+		Note:                o.Note.String,
+		Version:             o.Version,
+		TableID:             optionalUUID(o.TableID),
+		CustomerName:        o.CustomerName.String,
+		CustomerPhone:       o.CustomerPhone.String,
+		DeliveryAddress:     o.DeliveryAddress.String,
+		Courier:             o.Courier.String,
+		ScheduledFor:        optionalTime(o.ScheduledFor),
+		KitchenReleasedAt:   optionalTime(o.KitchenReleasedAt),
+		CreatedAt:           o.CreatedAt.Time,
+		UpdatedAt:           o.UpdatedAt.Time,
+	}
+}
+
+// orderConflict reports a failed compare-and-swap together with the latest
+// state of the order, so clients can refresh and retry.
+func orderConflict(ctx context.Context, q *repository.Queries, id pgtype.UUID) error {
+	latest, err := q.GetOrder(ctx, id)
+	if err != nil {
+		return err
+	}
+	current := toDomainOrder(latest)
+	return &domain.OrderConflictError{Current: &current}
+}
+
+// moneyNumeric rounds an amount to cents for DECIMAL(10, 2) columns.
+func moneyNumeric(v float64) pgtype.Numeric {
+	return pgtype.Numeric{Int: big.NewInt(int64(math.Round(v * 100))), Exp: -2, Valid: true}
+}
@@ -23,9 +23,16 @@ func NewPaymentUsecase(store repository.Repository) domain.PaymentUsecase {
 }
 
 func (uc *paymentUsecase) UploadQRIS(ctx context.Context, req *domain.UploadQRISRequest) (*domain.Payment, error) {
-	// 1. Validate Order exists?
+	// 1. Validate Order exists and belongs to the caller's store
 	// 2. Save file
 	// 3. Update/Create Payment record
+	order, err := uc.store.GetOrder(ctx, pgtype.UUID{Bytes: req.OrderID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("order not found")
+	}
+	if err := checkStoreID(ctx, order.StoreID); err != nil {
+		return nil, err
+	}
 
 	// Create upload dir
 	uploadDir := "uploads/qris"
@@ -0,0 +1,327 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type permissionUsecase struct {
+	store    repository.Repository
+	cacheTTL time.Duration
+
+	mu    sync.RWMutex
+	cache *permissionCache
+}
+
+// permissionCache is a snapshot of the role_permissions table.
+type permissionCache struct {
+	defaults map[string][]string               // role -> permissions
+	stores   map[uuid.UUID]map[string][]string // store -> role -> permissions
+	loadedAt time.Time
+}
+
+// NewPermissionUsecase caches the role to permission mapping for cacheTTL.
+// Changes made through this usecase apply at once on this instance, and on
+// the others once their cache expires.
+func NewPermissionUsecase(store repository.Repository, cacheTTL time.Duration) domain.PermissionUsecase {
+	return &permissionUsecase{
+		store:    store,
+		cacheTTL: cacheTTL,
+	}
+}
+
+func (uc *permissionUsecase) HasPermission(ctx context.Context, roles []string, storeID *uuid.UUID, permission domain.Permission) (bool, error) {
+	if slices.Contains(roles, string(domain.RoleSuperAdmin)) {
+		return true, nil
+	}
+
+	cache, err := uc.snapshot(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, role := range roles {
+		if slices.Contains(cache.permissionsOf(role, storeID), string(permission)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (uc *permissionUsecase) StoresWithPermission(ctx context.Context, claims *domain.JwtCustomClaims, access *domain.StoreAccess, permission domain.Permission) (*domain.StoreAccess, error) {
+	if access == nil {
+		return nil, nil
+	}
+	if access.AllStores {
+		allowed, err := uc.HasPermission(ctx, claims.Roles, nil, permission)
+		if err != nil || allowed {
+			return access, err
+		}
+	}
+	if len(access.StoreIDs) == 0 {
+		allowed, err := uc.HasPermission(ctx, claims.Roles, nil, permission)
+		if err != nil || !allowed {
+			return nil, err
+		}
+		return &domain.StoreAccess{StoreIDs: []uuid.UUID{}}, nil
+	}
+
+	narrowed := &domain.StoreAccess{StoreIDs: []uuid.UUID{}}
+	for _, storeID := range access.StoreIDs {
+		allowed, err := uc.HasPermission(ctx, claims.RolesIn(storeID), &storeID, permission)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			narrowed.StoreIDs = append(narrowed.StoreIDs, storeID)
+		}
+	}
+	if len(narrowed.StoreIDs) == 0 {
+		return nil, nil
+	}
+	return narrowed, nil
+}
+
+func (uc *permissionUsecase) ListPermissions(ctx context.Context) ([]domain.PermissionInfo, error) {
+	rows, err := uc.store.ListPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make([]domain.PermissionInfo, 0, len(rows))
+	for _, row := range rows {
+		permissions = append(permissions, domain.PermissionInfo{
+			Code:        domain.Permission(row.Code),
+			Description: row.Description,
+		})
+	}
+	return permissions, nil
+}
+
+func (uc *permissionUsecase) GetRolePermissions(ctx context.Context, actor *domain.JwtCustomClaims, role domain.UserRole, storeID *uuid.UUID) (*domain.RolePermissions, error) {
+	_, store, err := uc.scopeStore(ctx, actor, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if role == domain.RoleSuperAdmin {
+		all, err := uc.ListPermissions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res := &domain.RolePermissions{Role: role, StoreID: optionalUUID(store), IsDefault: true}
+		for _, p := range all {
+			res.Permissions = append(res.Permissions, p.Code)
+		}
+		return res, nil
+	}
+
+	if store.Valid {
+		row, err := uc.store.GetRolePermissions(ctx, repository.GetRolePermissionsParams{
+			RoleCode: string(role),
+			StoreID:  store,
+		})
+		if err == nil {
+			return toDomainRolePermissions(row, false), nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	row, err := uc.store.GetRolePermissions(ctx, repository.GetRolePermissionsParams{
+		RoleCode: string(role),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &domain.RolePermissions{Role: role, StoreID: optionalUUID(store), IsDefault: true, Permissions: []domain.Permission{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	res := toDomainRolePermissions(row, true)
+	res.StoreID = optionalUUID(store)
+	return res, nil
+}
+
+// SetRolePermissions replaces the permissions of a role. A STORE_OWNER only
+// customises staff roles in their own store, and only with permissions they
+// hold themselves.
+func (uc *permissionUsecase) SetRolePermissions(ctx context.Context, actor *domain.JwtCustomClaims, role domain.UserRole, req *domain.SetRolePermissionsRequest) (*domain.RolePermissions, error) {
+	if role == domain.RoleSuperAdmin {
+		return nil, fmt.Errorf("SUPER_ADMIN always holds every permission")
+	}
+	if _, err := uc.store.GetRole(ctx, string(role)); err != nil {
+		return nil, fmt.Errorf("unknown role %s", role)
+	}
+	scope, store, err := uc.scopeStore(ctx, actor, req.StoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	known, err := uc.ListPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permissions := make([]string, 0, len(req.Permissions))
+	for _, p := range req.Permissions {
+		if !slices.ContainsFunc(known, func(k domain.PermissionInfo) bool { return k.Code == p }) {
+			return nil, fmt.Errorf("unknown permission %s", p)
+		}
+		if scope.Valid {
+			if !role.IsStaffRole() || !p.StoreAssignable() {
+				return nil, fmt.Errorf("not allowed to give %s to %s", p, role)
+			}
+			storeUUID := uuid.UUID(scope.Bytes)
+			held, err := uc.HasPermission(ctx, actor.RolesIn(storeUUID), &storeUUID, p)
+			if err != nil {
+				return nil, err
+			}
+			if !held {
+				return nil, fmt.Errorf("not allowed to give %s to %s", p, role)
+			}
+		}
+		permissions = append(permissions, string(p))
+	}
+	slices.Sort(permissions)
+	permissions = slices.Compact(permissions)
+
+	var row repository.RolePermission
+	err = uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		err := q.DeleteRolePermissions(ctx, repository.DeleteRolePermissionsParams{
+			RoleCode: string(role),
+			StoreID:  store,
+		})
+		if err != nil {
+			return err
+		}
+		row, err = q.CreateRolePermissions(ctx, repository.CreateRolePermissionsParams{
+			RoleCode:    string(role),
+			StoreID:     store,
+			Permissions: permissions,
+			UpdatedBy:   pgtype.UUID{Bytes: actor.UserID, Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.invalidate()
+	return toDomainRolePermissions(row, !store.Valid), nil
+}
+
+func (uc *permissionUsecase) ResetRolePermissions(ctx context.Context, actor *domain.JwtCustomClaims, role domain.UserRole, storeID uuid.UUID) error {
+	scope, store, err := uc.scopeStore(ctx, actor, &storeID)
+	if err != nil {
+		return err
+	}
+	if scope.Valid && !role.IsStaffRole() {
+		return fmt.Errorf("not allowed to change %s", role)
+	}
+
+	err = uc.store.DeleteRolePermissions(ctx, repository.DeleteRolePermissionsParams{
+		RoleCode: string(role),
+		StoreID:  store,
+	})
+	if err != nil {
+		return err
+	}
+	uc.invalidate()
+	return nil
+}
+
+// scopeStore returns the actor scope (see actorScope) and the store to read
+// or change: always the own store for a STORE_OWNER, the given one (or none,
+// for the defaults) for a SUPER_ADMIN.
+func (uc *permissionUsecase) scopeStore(ctx context.Context, actor *domain.JwtCustomClaims, storeID *uuid.UUID) (pgtype.UUID, pgtype.UUID, error) {
+	scope, err := actorScope(ctx, uc.store, actor)
+	if err != nil {
+		return pgtype.UUID{}, pgtype.UUID{}, err
+	}
+	store := uuidParam(storeID)
+	if scope.Valid {
+		if store.Valid && store != scope {
+			return pgtype.UUID{}, pgtype.UUID{}, errOtherStore
+		}
+		return scope, scope, nil
+	}
+	return scope, store, nil
+}
+
+// snapshot returns the cached mapping, reloading it once it is older than
+// the TTL.
+func (uc *permissionUsecase) snapshot(ctx context.Context) (*permissionCache, error) {
+	uc.mu.RLock()
+	cache := uc.cache
+	uc.mu.RUnlock()
+	if cache != nil && time.Since(cache.loadedAt) < uc.cacheTTL {
+		return cache, nil
+	}
+
+	rows, err := uc.store.ListRolePermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cache = &permissionCache{
+		defaults: make(map[string][]string),
+		stores:   make(map[uuid.UUID]map[string][]string),
+		loadedAt: time.Now(),
+	}
+	for _, row := range rows {
+		if !row.StoreID.Valid {
+			cache.defaults[row.RoleCode] = row.Permissions
+			continue
+		}
+		storeID := uuid.UUID(row.StoreID.Bytes)
+		if cache.stores[storeID] == nil {
+			cache.stores[storeID] = make(map[string][]string)
+		}
+		cache.stores[storeID][row.RoleCode] = row.Permissions
+	}
+
+	uc.mu.Lock()
+	uc.cache = cache
+	uc.mu.Unlock()
+	return cache, nil
+}
+
+func (uc *permissionUsecase) invalidate() {
+	uc.mu.Lock()
+	uc.cache = nil
+	uc.mu.Unlock()
+}
+
+// permissionsOf returns the permissions of a role in a store: the store
+// customisation when there is one, otherwise the default.
+func (c *permissionCache) permissionsOf(role string, storeID *uuid.UUID) []string {
+	if storeID != nil {
+		if permissions, ok := c.stores[*storeID][role]; ok {
+			return permissions
+		}
+	}
+	return c.defaults[role]
+}
+
+func toDomainRolePermissions(row repository.RolePermission, isDefault bool) *domain.RolePermissions {
+	res := &domain.RolePermissions{
+		Role:        domain.UserRole(row.RoleCode),
+		StoreID:     optionalUUID(row.StoreID),
+		IsDefault:   isDefault,
+		Permissions: make([]domain.Permission, 0, len(row.Permissions)),
+		UpdatedAt:   optionalTime(row.UpdatedAt),
+	}
+	for _, p := range row.Permissions {
+		res.Permissions = append(res.Permissions, domain.Permission(p))
+	}
+	return res
+}
@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+	"pos-api/internal/util"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type posDeviceUsecase struct {
+	store         repository.Repository
+	tokenMaker    util.TokenMaker
+	denylist      util.TokenDenylist
+	pinAttempts   util.AttemptLimiter
+	tokenDuration time.Duration
+}
+
+// NewPosDeviceUsecase issues PIN login tokens valid for tokenDuration.
+// pinAttempts limits wrong PINs per user across all devices.
+func NewPosDeviceUsecase(store repository.Repository, tokenMaker util.TokenMaker, denylist util.TokenDenylist, pinAttempts util.AttemptLimiter, tokenDuration time.Duration) domain.PosDeviceUsecase {
+	return &posDeviceUsecase{
+		store:         store,
+		tokenMaker:    tokenMaker,
+		denylist:      denylist,
+		pinAttempts:   pinAttempts,
+		tokenDuration: tokenDuration,
+	}
+}
+
+func (uc *posDeviceUsecase) RegisterDevice(ctx context.Context, req *domain.RegisterPosDeviceRequest, actorID uuid.UUID) (*domain.RegisterPosDeviceResponse, error) {
+	if err := checkStore(ctx, req.StoreID); err != nil {
+		return nil, err
+	}
+
+	credential, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	device, err := uc.store.CreatePosDevice(ctx, repository.CreatePosDeviceParams{
+		StoreID:        pgtype.UUID{Bytes: req.StoreID, Valid: true},
+		Name:           req.Name,
+		CredentialHash: hashOpaqueToken(credential),
+		CreatedBy:      pgtype.UUID{Bytes: actorID, Valid: actorID != uuid.Nil},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.RegisterPosDeviceResponse{
+		Device:     toDomainPosDevice(device),
+		Credential: credential,
+	}, nil
+}
+
+func (uc *posDeviceUsecase) ListDevices(ctx context.Context, storeID uuid.UUID) ([]domain.PosDevice, error) {
+	if err := checkStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	rows, err := uc.store.ListPosDevices(ctx, pgtype.UUID{Bytes: storeID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]domain.PosDevice, 0, len(rows))
+	for _, row := range rows {
+		devices = append(devices, *toDomainPosDevice(row))
+	}
+	return devices, nil
+}
+
+// RevokeDevice denylists the device for as long as its tokens live, so
+// AuthMiddleware rejects every token issued on it.
+func (uc *posDeviceUsecase) RevokeDevice(ctx context.Context, id uuid.UUID) (*domain.PosDevice, error) {
+	current, err := uc.store.GetPosDevice(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("device not found or already revoked")
+	}
+	if err := checkStoreID(ctx, current.StoreID); err != nil {
+		return nil, err
+	}
+
+	device, err := uc.store.RevokePosDevice(ctx, current.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("device not found or already revoked")
+		}
+		return nil, err
+	}
+
+	if err := uc.denylist.Deny(ctx, util.DeviceDenylistKey(id), time.Now().Add(uc.tokenDuration)); err != nil {
+		return nil, err
+	}
+	return toDomainPosDevice(device), nil
+}
+
+func (uc *posDeviceUsecase) AuthenticateDevice(ctx context.Context, credential string) (*domain.PosDevice, error) {
+	// The credential tells the store, it is looked up across all of them
+	device, err := uc.store.GetPosDeviceByCredential(domain.WithUnscopedAccess(ctx), hashOpaqueToken(credential))
+	if err != nil {
+		return nil, fmt.Errorf("invalid or revoked device credential")
+	}
+	return toDomainPosDevice(device), nil
+}
+
+func (uc *posDeviceUsecase) ListStaff(ctx context.Context, device *domain.PosDevice) ([]domain.PinStaff, error) {
+	rows, err := uc.store.ListPinStaff(ctx, pgtype.UUID{Bytes: device.StoreID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	staff := make([]domain.PinStaff, 0, len(rows))
+	for _, row := range rows {
+		staff = append(staff, domain.PinStaff{
+			ID:       uuid.UUID(row.ID.Bytes),
+			FullName: row.FullName.String,
+			Role:     domain.UserRole(row.Role),
+		})
+	}
+	return staff, nil
+}
+
+func (uc *posDeviceUsecase) SetPin(ctx context.Context, userID uuid.UUID, req *domain.SetPinRequest) error {
+	authUser, err := uc.store.GetAuthUser(ctx, pgtype.UUID{Bytes: userID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("user not found")
+	}
+	if err := util.CheckPassword(req.Password, authUser.EncryptedPassword); err != nil {
+		return fmt.Errorf("invalid password")
+	}
+	if err := refuseTwoFactorPin(ctx, uc.store, authUser.ID); err != nil {
+		return err
+	}
+
+	pinHash, err := util.HashPassword(req.Pin)
+	if err != nil {
+		return err
+	}
+	return uc.store.SetProfilePin(ctx, repository.SetProfilePinParams{
+		ID:      authUser.ID,
+		PinHash: pgtype.Text{String: pinHash, Valid: true},
+	})
+}
+
+func (uc *posDeviceUsecase) PinLogin(ctx context.Context, device *domain.PosDevice, req *domain.PinLoginRequest) (*domain.PinLoginResponse, error) {
+	return uc.signIn(ctx, device, req, "PIN_LOGIN")
+}
+
+func (uc *posDeviceUsecase) SwitchUser(ctx context.Context, device *domain.PosDevice, current *domain.JwtCustomClaims, req *domain.PinLoginRequest) (*domain.PinLoginResponse, error) {
+	if current.DeviceID == nil || *current.DeviceID != device.ID {
+		return nil, fmt.Errorf("token was not issued on this device")
+	}
+
+	res, err := uc.signIn(ctx, device, req, "PIN_SWITCH_USER")
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.denylist.Deny(ctx, current.ID, current.ExpiresAt.Time); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+var (
+	errInvalidPin   = errors.New("invalid user or PIN")
+	errPinTwoFactor = errors.New("accounts with two-factor authentication cannot use a PIN, log in with password and code")
+)
+
+// refuseTwoFactorPin keeps users with 2FA on, or a role that requires it, off
+// PIN login: the PIN would give a full token without the second factor.
+func refuseTwoFactorPin(ctx context.Context, q repository.Querier, userID pgtype.UUID) error {
+	required, err := usesTwoFactor(ctx, q, userID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return errPinTwoFactor
+	}
+	return nil
+}
+
+// signIn checks the PIN and issues a device token. Wrong PINs count per user,
+// so trying PINs on several devices does not get around the limit.
+func (uc *posDeviceUsecase) signIn(ctx context.Context, device *domain.PosDevice, req *domain.PinLoginRequest, action string) (*domain.PinLoginResponse, error) {
+	attemptKey := pinAttemptKey(req.UserID)
+	blocked, err := uc.pinAttempts.Blocked(ctx, attemptKey)
+	if err != nil {
+		return nil, err
+	}
+	if blocked > 0 {
+		return nil, fmt.Errorf("too many wrong PINs, try again in %s", blocked.Round(time.Second))
+	}
+
+	profileDB, err := uc.store.GetProfile(ctx, pgtype.UUID{Bytes: req.UserID, Valid: true})
+	if err != nil || !profileDB.PinHash.Valid || uuid.UUID(profileDB.StoreID.Bytes) != device.StoreID {
+		return nil, errInvalidPin
+	}
+	if !profileDB.IsActive {
+		return nil, errAccountDeactivated
+	}
+	if err := util.CheckPassword(req.Pin, profileDB.PinHash.String); err != nil {
+		if err := uc.pinAttempts.Fail(ctx, attemptKey); err != nil {
+			return nil, err
+		}
+		return nil, errInvalidPin
+	}
+	if err := uc.pinAttempts.Reset(ctx, attemptKey); err != nil {
+		return nil, err
+	}
+	// Checked at every login, 2FA may have been switched on after the PIN was set
+	if err := refuseTwoFactorPin(ctx, uc.store, profileDB.ID); err != nil {
+		return nil, err
+	}
+
+	// The token only works in the store of the device
+	roles, err := loadUserRoles(ctx, uc.store, profileDB.ID, pgtype.UUID{Bytes: device.StoreID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	accessToken, claims, err := uc.tokenMaker.CreateDeviceToken(
+		req.UserID,
+		profileDB.Email.String,
+		roles.roles,
+		device.ID,
+		device.StoreID,
+		uc.tokenDuration,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceID := pgtype.UUID{Bytes: device.ID, Valid: true}
+	if err := uc.store.TouchPosDevice(ctx, deviceID); err != nil {
+		return nil, err
+	}
+	_, err = uc.store.CreateAuditLog(ctx, repository.CreateAuditLogParams{
+		UserID:     profileDB.ID,
+		Action:     action,
+		Entity:     pgtype.Text{String: "PosDevice", Valid: true},
+		EntityID:   deviceID,
+		IpAddress:  pgtype.Text{String: req.IPAddress, Valid: req.IPAddress != ""},
+		TerminalID: deviceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	profile := toDomainProfile(profileDB)
+	profile.Roles = roles.toDomain()
+	return &domain.PinLoginResponse{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: claims.ExpiresAt.Time,
+		DeviceID:             device.ID,
+		StoreID:              device.StoreID,
+		Profile:              profile,
+	}, nil
+}
+
+func pinAttemptKey(userID uuid.UUID) string {
+	return "pin:" + userID.String()
+}
+
+func toDomainPosDevice(d repository.PosDevice) *domain.PosDevice {
+	return &domain.PosDevice{
+		ID:         uuid.UUID(d.ID.Bytes),
+		StoreID:    uuid.UUID(d.StoreID.Bytes),
+		Name:       d.Name,
+		IsActive:   d.IsActive,
+		LastSeenAt: optionalTime(d.LastSeenAt),
+		CreatedAt:  d.CreatedAt.Time,
+		RevokedAt:  optionalTime(d.RevokedAt),
+	}
+}
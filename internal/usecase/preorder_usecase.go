@@ -0,0 +1,369 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Used for stores that never saved their pre-order settings
+const (
+	defaultPreorderLeadMinutes = 15
+	defaultPreorderSlotMinutes = 15
+	defaultPreorderTimezone    = "Asia/Jakarta"
+)
+
+type preorderUsecase struct {
+	store    repository.Repository
+	eventSvc domain.EventService
+}
+
+func NewPreorderUsecase(store repository.Repository, eventSvc domain.EventService) domain.PreorderUsecase {
+	return &preorderUsecase{
+		store:    store,
+		eventSvc: eventSvc,
+	}
+}
+
+func (uc *preorderUsecase) SetSettings(ctx context.Context, req *domain.SetPreorderSettingsRequest) (*domain.PreorderSettings, error) {
+	if err := checkStore(ctx, req.StoreID); err != nil {
+		return nil, err
+	}
+
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return nil, fmt.Errorf("unknown timezone %s", req.Timezone)
+	}
+
+	type window struct{ opens, closes pgtype.Time }
+	hours := make(map[time.Weekday]window)
+	for _, h := range req.OpeningHours {
+		if _, dup := hours[h.Weekday]; dup {
+			return nil, fmt.Errorf("duplicate opening hours for %s", h.Weekday)
+		}
+		opens, err := parseClock(h.OpensAt)
+		if err != nil {
+			return nil, err
+		}
+		closes, err := parseClock(h.ClosesAt)
+		if err != nil {
+			return nil, err
+		}
+		if opens.Microseconds >= closes.Microseconds {
+			return nil, fmt.Errorf("%s opens at %s but closes at %s", h.Weekday, h.OpensAt, h.ClosesAt)
+		}
+		hours[h.Weekday] = window{opens, closes}
+	}
+
+	storeID := pgtype.UUID{Bytes: req.StoreID, Valid: true}
+	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		_, err := q.UpsertPreorderSettings(ctx, repository.UpsertPreorderSettingsParams{
+			StoreID:         storeID,
+			LeadTimeMinutes: req.LeadTimeMinutes,
+			SlotMinutes:     req.SlotMinutes,
+			SlotCapacity:    req.SlotCapacity,
+			Timezone:        req.Timezone,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := q.DeleteOpeningHours(ctx, storeID); err != nil {
+			return err
+		}
+		for day, w := range hours {
+			_, err := q.CreateOpeningHours(ctx, repository.CreateOpeningHoursParams{
+				StoreID:  storeID,
+				Weekday:  int16(day),
+				OpensAt:  w.opens,
+				ClosesAt: w.closes,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.GetSettings(ctx, req.StoreID)
+}
+
+func (uc *preorderUsecase) GetSettings(ctx context.Context, storeID uuid.UUID) (*domain.PreorderSettings, error) {
+	if err := checkStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+	return loadPreorderSettings(ctx, uc.store, pgtype.UUID{Bytes: storeID, Valid: true})
+}
+
+func (uc *preorderUsecase) ListSlots(ctx context.Context, storeID uuid.UUID, date string) ([]domain.PreorderSlot, error) {
+	settings, err := uc.GetSettings(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	day, err := time.ParseInLocation("2006-01-02", date, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date, expected YYYY-MM-DD")
+	}
+
+	slots := []domain.PreorderSlot{}
+	opens, closes, ok := openingWindow(settings, day)
+	if !ok {
+		return slots, nil
+	}
+
+	booked, err := uc.store.ListPreorderTimes(ctx, repository.ListPreorderTimesParams{
+		StoreID:  pgtype.UUID{Bytes: storeID, Valid: true},
+		FromTime: pgtype.Timestamptz{Time: opens, Valid: true},
+		ToTime:   pgtype.Timestamptz{Time: closes, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	step := time.Duration(settings.SlotMinutes) * time.Minute
+	earliest := time.Now().Add(time.Duration(settings.LeadTimeMinutes) * time.Minute)
+	for start := opens; start.Before(closes); start = start.Add(step) {
+		end := start.Add(step)
+		if end.After(closes) {
+			end = closes
+		}
+
+		slot := domain.PreorderSlot{
+			Start:    start,
+			End:      end,
+			Capacity: settings.SlotCapacity,
+		}
+		for _, t := range booked {
+			if !t.Time.Before(start) && t.Time.Before(end) {
+				slot.Booked++
+			}
+		}
+		// A slot can be booked while its end is still reachable after the lead time
+		slot.Available = end.After(earliest) && (slot.Capacity == 0 || slot.Booked < int64(slot.Capacity))
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+func (uc *preorderUsecase) ListUpcoming(ctx context.Context, storeID uuid.UUID) ([]domain.Order, error) {
+	if err := checkStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	rows, err := uc.store.ListUpcomingPreorders(ctx, pgtype.UUID{Bytes: storeID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]domain.Order, 0, len(rows))
+	for _, o := range rows {
+		res = append(res, toDomainOrder(o))
+	}
+	return res, nil
+}
+
+// ReleaseDue makes due pre-orders visible to the kitchen and announces them
+// through the hub exactly like a fresh order.
+func (uc *preorderUsecase) ReleaseDue(ctx context.Context) ([]domain.Order, error) {
+	due, err := uc.store.ListDuePreorders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var released []domain.Order
+	for _, o := range due {
+		var order domain.Order
+		skipped := false
+
+		err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+			n, err := q.ReleasePreorder(ctx, o.ID)
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				// Released by another instance in the meantime
+				skipped = true
+				return nil
+			}
+
+			dbOrder, err := q.GetOrder(ctx, o.ID)
+			if err != nil {
+				return err
+			}
+			order = toDomainOrder(dbOrder)
+
+			items, err := q.ListOrderItems(ctx, dbOrder.ID)
+			if err != nil {
+				return err
+			}
+			for _, item := range items {
+				order.Items = append(order.Items, toDomainOrderItem(item))
+			}
+
+			tickets, err := q.ListOrderKitchenTickets(ctx, dbOrder.ID)
+			if err != nil {
+				return err
+			}
+			for _, t := range tickets {
+				ticket, err := loadKitchenTicket(ctx, q, t, dbOrder)
+				if err != nil {
+					return err
+				}
+				order.Tickets = append(order.Tickets, ticket)
+			}
+			return nil
+		})
+		if err != nil {
+			return released, fmt.Errorf("failed to release pre-order %s: %w", o.OrderNumber, err)
+		}
+		if skipped {
+			continue
+		}
+
+		_ = uc.eventSvc.PublishEvent(ctx, "NEW_ORDER", order)
+		for _, ticket := range order.Tickets {
+			_ = uc.eventSvc.PublishEvent(ctx, "KDS_TICKET_CREATED", ticket)
+		}
+		released = append(released, order)
+	}
+	return released, nil
+}
+
+// validateSchedule checks a pickup time against the lead time, the opening
+// hours and the capacity of its slot. The slot stays locked until the
+// surrounding transaction ends, so concurrent orders cannot overbook it.
+func validateSchedule(ctx context.Context, q *repository.Queries, storeID uuid.UUID, scheduledFor time.Time) error {
+	settings, err := loadPreorderSettings(ctx, q, pgtype.UUID{Bytes: storeID, Valid: true})
+	if err != nil {
+		return err
+	}
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return err
+	}
+
+	earliest := time.Now().Add(time.Duration(settings.LeadTimeMinutes) * time.Minute)
+	if scheduledFor.Before(earliest) {
+		return fmt.Errorf("scheduled_for must be at least %d minutes from now", settings.LeadTimeMinutes)
+	}
+
+	local := scheduledFor.In(loc)
+	opens, closes, ok := openingWindow(settings, local)
+	if !ok || local.Before(opens) || !local.Before(closes) {
+		return fmt.Errorf("store is closed at %s", local.Format("Mon 2006-01-02 15:04"))
+	}
+
+	if settings.SlotCapacity == 0 {
+		return nil
+	}
+
+	step := time.Duration(settings.SlotMinutes) * time.Minute
+	slotStart := opens.Add(local.Sub(opens) / step * step)
+	slotEnd := slotStart.Add(step)
+
+	if err := q.LockPreorderSlot(ctx, fmt.Sprintf("preorder:%s:%d", storeID, slotStart.Unix())); err != nil {
+		return err
+	}
+	booked, err := q.CountPreordersInSlot(ctx, repository.CountPreordersInSlotParams{
+		StoreID:   pgtype.UUID{Bytes: storeID, Valid: true},
+		SlotStart: pgtype.Timestamptz{Time: slotStart, Valid: true},
+		SlotEnd:   pgtype.Timestamptz{Time: slotEnd, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	if booked >= int64(settings.SlotCapacity) {
+		return fmt.Errorf("pickup slot %s is full", slotStart.Format("15:04"))
+	}
+	return nil
+}
+
+func loadPreorderSettings(ctx context.Context, q repository.Querier, storeID pgtype.UUID) (*domain.PreorderSettings, error) {
+	settings := &domain.PreorderSettings{
+		StoreID:         uuid.UUID(storeID.Bytes),
+		LeadTimeMinutes: defaultPreorderLeadMinutes,
+		SlotMinutes:     defaultPreorderSlotMinutes,
+		Timezone:        defaultPreorderTimezone,
+		OpeningHours:    []domain.OpeningHours{},
+	}
+
+	row, err := q.GetPreorderSettings(ctx, storeID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if err == nil {
+		settings.LeadTimeMinutes = row.LeadTimeMinutes
+		settings.SlotMinutes = row.SlotMinutes
+		settings.SlotCapacity = row.SlotCapacity
+		settings.Timezone = row.Timezone
+	}
+
+	hours, err := q.ListOpeningHours(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hours {
+		settings.OpeningHours = append(settings.OpeningHours, domain.OpeningHours{
+			Weekday:  time.Weekday(h.Weekday),
+			OpensAt:  formatClock(h.OpensAt),
+			ClosesAt: formatClock(h.ClosesAt),
+		})
+	}
+	return settings, nil
+}
+
+// openingWindow returns when the store opens and closes on the day of t,
+// in the location of t. A store without opening hours is open all day.
+func openingWindow(settings *domain.PreorderSettings, t time.Time) (opens, closes time.Time, ok bool) {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if len(settings.OpeningHours) == 0 {
+		return midnight, midnight.AddDate(0, 0, 1), true
+	}
+
+	for _, h := range settings.OpeningHours {
+		if h.Weekday != t.Weekday() {
+			continue
+		}
+		o, err := parseClock(h.OpensAt)
+		if err != nil {
+			return opens, closes, false
+		}
+		c, err := parseClock(h.ClosesAt)
+		if err != nil {
+			return opens, closes, false
+		}
+		opens = midnight.Add(time.Duration(o.Microseconds) * time.Microsecond)
+		closes = midnight.Add(time.Duration(c.Microseconds) * time.Microsecond)
+		return opens, closes, true
+	}
+	return opens, closes, false
+}
+
+// parseClock converts "HH:MM" to a TIME column value.
+func parseClock(s string) (pgtype.Time, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return pgtype.Time{}, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	d := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	return pgtype.Time{Microseconds: d.Microseconds(), Valid: true}, nil
+}
+
+func formatClock(t pgtype.Time) string {
+	d := time.Duration(t.Microseconds) * time.Microsecond
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
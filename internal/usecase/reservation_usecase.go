@@ -0,0 +1,312 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type reservationUsecase struct {
+	store           repository.Repository
+	sessionUsecase  domain.SessionUsecase
+	defaultDuration time.Duration
+}
+
+// NewReservationUsecase books tables for defaultDuration unless the request
+// asks for another duration.
+func NewReservationUsecase(store repository.Repository, sessionUsecase domain.SessionUsecase, defaultDuration time.Duration) domain.ReservationUsecase {
+	return &reservationUsecase{
+		store:           store,
+		sessionUsecase:  sessionUsecase,
+		defaultDuration: defaultDuration,
+	}
+}
+
+func (uc *reservationUsecase) CreateReservation(ctx context.Context, req *domain.ReservationRequest, actorID uuid.UUID) (*domain.Reservation, error) {
+	if err := checkStore(ctx, req.StoreID); err != nil {
+		return nil, err
+	}
+
+	if !req.ReservedAt.After(time.Now()) {
+		return nil, fmt.Errorf("reserved_at must be in the future")
+	}
+	duration := uc.duration(req)
+
+	var id pgtype.UUID
+	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		storeID := pgtype.UUID{Bytes: req.StoreID, Valid: true}
+		tableID, err := assignReservationTable(ctx, q, storeID, pgtype.UUID{}, req, duration)
+		if err != nil {
+			return err
+		}
+
+		r, err := q.CreateReservation(ctx, repository.CreateReservationParams{
+			StoreID:         storeID,
+			TableID:         tableID,
+			CustomerName:    req.CustomerName,
+			CustomerPhone:   req.CustomerPhone,
+			CustomerEmail:   pgtype.Text{String: req.CustomerEmail, Valid: req.CustomerEmail != ""},
+			PartySize:       req.PartySize,
+			ReservedAt:      pgtype.Timestamptz{Time: req.ReservedAt, Valid: true},
+			DurationMinutes: int32(duration / time.Minute),
+			Note:            pgtype.Text{String: req.Note, Valid: req.Note != ""},
+			CreatedBy:       pgtype.UUID{Bytes: actorID, Valid: actorID != uuid.Nil},
+		})
+		if err != nil {
+			return err
+		}
+		id = r.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.GetReservation(ctx, uuid.UUID(id.Bytes))
+}
+
+func (uc *reservationUsecase) ListReservations(ctx context.Context, storeID uuid.UUID, date string) ([]domain.Reservation, error) {
+	if err := checkStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	settings, err := loadPreorderSettings(ctx, uc.store, pgtype.UUID{Bytes: storeID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	day, err := time.ParseInLocation("2006-01-02", date, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date, expected YYYY-MM-DD")
+	}
+
+	rows, err := uc.store.ListReservations(ctx, repository.ListReservationsParams{
+		StoreID:  pgtype.UUID{Bytes: storeID, Valid: true},
+		FromTime: pgtype.Timestamptz{Time: day, Valid: true},
+		ToTime:   pgtype.Timestamptz{Time: day.AddDate(0, 0, 1), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reservations := make([]domain.Reservation, 0, len(rows))
+	for _, row := range rows {
+		reservations = append(reservations, toDomainReservationRow(repository.GetReservationRow(row)))
+	}
+	return reservations, nil
+}
+
+func (uc *reservationUsecase) GetReservation(ctx context.Context, id uuid.UUID) (*domain.Reservation, error) {
+	row, err := uc.store.GetReservation(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("reservation not found")
+	}
+	if err := checkStoreID(ctx, row.StoreID); err != nil {
+		return nil, err
+	}
+	r := toDomainReservationRow(row)
+	return &r, nil
+}
+
+func (uc *reservationUsecase) UpdateReservation(ctx context.Context, id uuid.UUID, req *domain.ReservationRequest) (*domain.Reservation, error) {
+	duration := uc.duration(req)
+
+	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		current, err := q.GetReservationForUpdate(ctx, pgtype.UUID{Bytes: id, Valid: true})
+		if err != nil {
+			return fmt.Errorf("reservation not found")
+		}
+		if err := checkStoreID(ctx, current.StoreID); err != nil {
+			return err
+		}
+		if current.Status != string(domain.ReservationStatusBooked) {
+			return fmt.Errorf("reservation is %s", current.Status)
+		}
+		if !req.ReservedAt.Equal(current.ReservedAt.Time) && !req.ReservedAt.After(time.Now()) {
+			return fmt.Errorf("reserved_at must be in the future")
+		}
+
+		tableID, err := assignReservationTable(ctx, q, current.StoreID, current.ID, req, duration)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.UpdateReservation(ctx, repository.UpdateReservationParams{
+			ID:              current.ID,
+			TableID:         tableID,
+			CustomerName:    req.CustomerName,
+			CustomerPhone:   req.CustomerPhone,
+			CustomerEmail:   pgtype.Text{String: req.CustomerEmail, Valid: req.CustomerEmail != ""},
+			PartySize:       req.PartySize,
+			ReservedAt:      pgtype.Timestamptz{Time: req.ReservedAt, Valid: true},
+			DurationMinutes: int32(duration / time.Minute),
+			Note:            pgtype.Text{String: req.Note, Valid: req.Note != ""},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.GetReservation(ctx, id)
+}
+
+func (uc *reservationUsecase) CancelReservation(ctx context.Context, id uuid.UUID) (*domain.Reservation, error) {
+	return uc.setStatus(ctx, id, domain.ReservationStatusCancelled)
+}
+
+func (uc *reservationUsecase) MarkNoShow(ctx context.Context, id uuid.UUID) (*domain.Reservation, error) {
+	return uc.setStatus(ctx, id, domain.ReservationStatusNoShow)
+}
+
+// SeatReservation may seat the guests at another table than the reserved
+// one, e.g. when it is still occupied. The table must be free.
+func (uc *reservationUsecase) SeatReservation(ctx context.Context, id uuid.UUID, req *domain.SeatRequest) (*domain.Reservation, error) {
+	current, err := uc.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status != domain.ReservationStatusBooked {
+		return nil, fmt.Errorf("reservation is %s", current.Status)
+	}
+
+	tableID := current.TableID
+	if req.TableID != nil {
+		tableID = req.TableID
+	}
+	if tableID == nil {
+		return nil, fmt.Errorf("reservation has no table; pick one")
+	}
+	if err := checkTableFits(ctx, uc.store, current.StoreID, *tableID, current.PartySize); err != nil {
+		return nil, err
+	}
+
+	// The reservation is seated in the same transaction as the session
+	if _, err := uc.sessionUsecase.SeatTable(ctx, *tableID, domain.SeatedParty{ReservationID: &id}); err != nil {
+		return nil, err
+	}
+
+	return uc.GetReservation(ctx, id)
+}
+
+func (uc *reservationUsecase) ExpireNoShows(ctx context.Context, grace time.Duration) (int64, error) {
+	return uc.store.MarkNoShowReservations(ctx, pgtype.Timestamptz{Time: time.Now().Add(-grace), Valid: true})
+}
+
+func (uc *reservationUsecase) setStatus(ctx context.Context, id uuid.UUID, status domain.ReservationStatus) (*domain.Reservation, error) {
+	if _, err := uc.GetReservation(ctx, id); err != nil {
+		return nil, err
+	}
+	if _, err := uc.store.SetReservationStatus(ctx, repository.SetReservationStatusParams{
+		ID:     pgtype.UUID{Bytes: id, Valid: true},
+		Status: string(status),
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("reservation not found or no longer booked")
+		}
+		return nil, err
+	}
+	return uc.GetReservation(ctx, id)
+}
+
+func (uc *reservationUsecase) duration(req *domain.ReservationRequest) time.Duration {
+	if req.DurationMinutes > 0 {
+		return time.Duration(req.DurationMinutes) * time.Minute
+	}
+	return uc.defaultDuration
+}
+
+// assignReservationTable checks the requested table for capacity and
+// overlapping bookings, or picks the smallest free table that fits. excludeID
+// is the reservation being changed.
+func assignReservationTable(ctx context.Context, q *repository.Queries, storeID, excludeID pgtype.UUID, req *domain.ReservationRequest, duration time.Duration) (pgtype.UUID, error) {
+	if err := q.LockReservations(ctx, fmt.Sprintf("reservation:%s", uuid.UUID(storeID.Bytes))); err != nil {
+		return pgtype.UUID{}, err
+	}
+
+	startsAt := pgtype.Timestamptz{Time: req.ReservedAt, Valid: true}
+	endsAt := pgtype.Timestamptz{Time: req.ReservedAt.Add(duration), Valid: true}
+
+	if req.TableID == nil {
+		table, err := q.FindFreeTable(ctx, repository.FindFreeTableParams{
+			StoreID:   storeID,
+			PartySize: pgtype.Int4{Int32: req.PartySize, Valid: true},
+			ExcludeID: excludeID,
+			EndsAt:    endsAt,
+			StartsAt:  startsAt,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgtype.UUID{}, fmt.Errorf("no table for %d guests is free at that time", req.PartySize)
+		}
+		if err != nil {
+			return pgtype.UUID{}, err
+		}
+		return table.ID, nil
+	}
+
+	if err := checkTableFits(ctx, q, uuid.UUID(storeID.Bytes), *req.TableID, req.PartySize); err != nil {
+		return pgtype.UUID{}, err
+	}
+	tableID := pgtype.UUID{Bytes: *req.TableID, Valid: true}
+	conflicts, err := q.CountReservationConflicts(ctx, repository.CountReservationConflictsParams{
+		TableID:   tableID,
+		ExcludeID: excludeID,
+		EndsAt:    endsAt,
+		StartsAt:  startsAt,
+	})
+	if err != nil {
+		return pgtype.UUID{}, err
+	}
+	if conflicts > 0 {
+		return pgtype.UUID{}, fmt.Errorf("table is already reserved at that time")
+	}
+	return tableID, nil
+}
+
+// checkTableFits rejects tables of other stores and tables too small for the
+// party.
+func checkTableFits(ctx context.Context, q repository.Querier, storeID, tableID uuid.UUID, partySize int32) error {
+	table, err := q.GetTable(ctx, pgtype.UUID{Bytes: tableID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("table not found")
+	}
+	if uuid.UUID(table.StoreID.Bytes) != storeID {
+		return fmt.Errorf("table belongs to another store")
+	}
+	if table.Capacity.Int32 < partySize {
+		return fmt.Errorf("table %s seats %d guests, party has %d", table.Name, table.Capacity.Int32, partySize)
+	}
+	return nil
+}
+
+func toDomainReservationRow(row repository.GetReservationRow) domain.Reservation {
+	return domain.Reservation{
+		ID:              uuid.UUID(row.ID.Bytes),
+		StoreID:         uuid.UUID(row.StoreID.Bytes),
+		TableID:         optionalUUID(row.TableID),
+		TableName:       row.TableName.String,
+		CustomerName:    row.CustomerName,
+		CustomerPhone:   row.CustomerPhone,
+		CustomerEmail:   row.CustomerEmail.String,
+		PartySize:       row.PartySize,
+		ReservedAt:      row.ReservedAt.Time,
+		DurationMinutes: row.DurationMinutes,
+		EndsAt:          row.ReservedAt.Time.Add(time.Duration(row.DurationMinutes) * time.Minute),
+		Status:          domain.ReservationStatus(row.Status),
+		Note:            row.Note.String,
+		TableSessionID:  optionalUUID(row.TableSessionID),
+		CreatedAt:       row.CreatedAt.Time,
+		UpdatedAt:       row.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type selfOrderUsecase struct {
+	store        repository.Repository
+	orderUsecase domain.OrderUsecase
+}
+
+func NewSelfOrderUsecase(store repository.Repository, orderUsecase domain.OrderUsecase) domain.SelfOrderUsecase {
+	return &selfOrderUsecase{
+		store:        store,
+		orderUsecase: orderUsecase,
+	}
+}
+
+func (uc *selfOrderUsecase) GetMenu(ctx context.Context, session *domain.TableSession) (*domain.Menu, error) {
+	rows, err := uc.store.ListMenuProducts(ctx, pgtype.UUID{Bytes: session.StoreID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	menu := &domain.Menu{
+		StoreID:    session.StoreID,
+		TableName:  session.TableName,
+		Categories: []domain.MenuCategory{},
+	}
+	// Rows are sorted by category, so a new category starts a new group
+	for _, p := range rows {
+		categoryID := optionalUUID(p.CategoryID)
+		n := len(menu.Categories)
+		if n == 0 || !sameCategory(menu.Categories[n-1].CategoryID, categoryID) {
+			name := p.CategoryName.String
+			if name == "" {
+				name = "Other"
+			}
+			menu.Categories = append(menu.Categories, domain.MenuCategory{
+				CategoryID: categoryID,
+				Name:       name,
+			})
+			n++
+		}
+
+		price, _ := p.Price.Float64Value()
+		menu.Categories[n-1].Products = append(menu.Categories[n-1].Products, domain.Product{
+			ID:          uuid.UUID(p.ID.Bytes),
+			StoreID:     uuid.UUID(p.StoreID.Bytes),
+			CategoryID:  categoryID,
+			Name:        p.Name,
+			Description: p.Description.String,
+			SKU:         p.Sku.String,
+			Price:       price.Float64,
+			Stock:       p.Stock,
+			ImageURL:    p.ImageUrl.String,
+			IsAvailable: p.IsAvailable.Bool,
+			CreatedAt:   p.CreatedAt.Time,
+			UpdatedAt:   p.UpdatedAt.Time,
+		})
+	}
+	return menu, nil
+}
+
+// PlaceOrder creates a DINE_IN order in the session through the normal
+// CreateOrder validation. Auto-accepting stores move it to ACCEPTED right away.
+func (uc *selfOrderUsecase) PlaceOrder(ctx context.Context, session *domain.TableSession, req *domain.CustomerOrderRequest) (*domain.Order, error) {
+	settings, err := uc.GetSettings(ctx, session.StoreID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.Enabled {
+		return nil, fmt.Errorf("self-ordering is disabled for this store")
+	}
+
+	sessionID, tableID := session.ID, session.TableID
+	orderReq := &domain.CreateOrderRequest{
+		StoreID:        session.StoreID,
+		TableSessionID: &sessionID,
+		TableID:        &tableID,
+		OrderType:      domain.OrderTypeDineIn,
+		Note:           req.Note,
+		Items:          req.Items,
+	}
+	if req.IdempotencyKey != "" {
+		// Scoped to the session so customers cannot collide with other keys
+		orderReq.IdempotencyKey = "session:" + session.ID.String() + ":" + req.IdempotencyKey
+	}
+
+	order, err := uc.orderUsecase.CreateOrder(ctx, orderReq)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.AutoAccept || order.Status != domain.OrderStatusNew {
+		return order, nil
+	}
+
+	accepted, err := uc.orderUsecase.UpdateStatus(ctx, order.ID, &domain.UpdateOrderStatusRequest{
+		Status: domain.OrderStatusAccepted,
+		Reason: "self-order auto-accept",
+		Source: domain.StatusSourceSystem,
+	})
+	if err != nil {
+		// The order exists either way; staff can still accept it by hand
+		return order, nil
+	}
+	accepted.Items = order.Items
+	accepted.Tickets = order.Tickets
+	return accepted, nil
+}
+
+func (uc *selfOrderUsecase) GetBill(ctx context.Context, session *domain.TableSession) (*domain.SessionBill, error) {
+	orders, err := uc.store.GetOrdersBySession(ctx, pgtype.UUID{Bytes: session.ID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	bill := &domain.SessionBill{
+		SessionID: session.ID,
+		TableName: session.TableName,
+		Orders:    []domain.Order{},
+	}
+	for _, o := range orders {
+		if o.Status == string(domain.OrderStatusVoided) {
+			continue
+		}
+
+		order := toDomainOrder(o)
+		items, err := uc.store.ListOrderItems(ctx, o.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			order.Items = append(order.Items, toDomainOrderItem(item))
+		}
+
+		bill.Subtotal += order.TotalAmount
+		bill.ServiceCharge += order.ServiceChargeAmount
+		bill.Tax += order.TaxAmount
+		bill.Discount += order.DiscountAmount
+		bill.Total += order.FinalAmount
+		if order.PaymentStatus == domain.PaymentStatusPaid {
+			bill.Paid += order.FinalAmount
+		}
+		bill.Orders = append(bill.Orders, order)
+	}
+	bill.Outstanding = bill.Total - bill.Paid
+	return bill, nil
+}
+
+func (uc *selfOrderUsecase) GetSettings(ctx context.Context, storeID uuid.UUID) (*domain.SelfOrderSettings, error) {
+	if err := checkStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	row, err := uc.store.GetSelfOrderSettings(ctx, pgtype.UUID{Bytes: storeID, Valid: true})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Enabled with staff confirmation until the owner decides otherwise
+		return &domain.SelfOrderSettings{StoreID: storeID, Enabled: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toDomainSelfOrderSettings(row), nil
+}
+
+func (uc *selfOrderUsecase) SetSettings(ctx context.Context, req *domain.SetSelfOrderSettingsRequest) (*domain.SelfOrderSettings, error) {
+	if err := checkStore(ctx, req.StoreID); err != nil {
+		return nil, err
+	}
+
+	row, err := uc.store.UpsertSelfOrderSettings(ctx, repository.UpsertSelfOrderSettingsParams{
+		StoreID:    pgtype.UUID{Bytes: req.StoreID, Valid: true},
+		Enabled:    req.Enabled,
+		AutoAccept: req.AutoAccept,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toDomainSelfOrderSettings(row), nil
+}
+
+func toDomainSelfOrderSettings(s repository.StoreSelfOrderSetting) *domain.SelfOrderSettings {
+	return &domain.SelfOrderSettings{
+		StoreID:    uuid.UUID(s.StoreID.Bytes),
+		Enabled:    s.Enabled,
+		AutoAccept: s.AutoAccept,
+		UpdatedAt:  s.UpdatedAt.Time,
+	}
+}
+
+func sameCategory(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
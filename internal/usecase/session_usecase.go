@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,94 +12,504 @@ import (
 	"pos-api/internal/repository"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type sessionUsecase struct {
-	repo repository.Querier // Use Querier interface (satisfied by *Queries)
+	store    repository.Repository
+	eventSvc domain.EventService
+	ttl      time.Duration
 }
 
-func NewSessionUsecase(repo repository.Querier) domain.SessionUsecase {
-	return &sessionUsecase{repo: repo}
+// NewSessionUsecase issues session tokens valid for ttl. Scanning the QR code
+// of a table whose session is still open renews the token.
+func NewSessionUsecase(store repository.Repository, eventSvc domain.EventService, ttl time.Duration) domain.SessionUsecase {
+	return &sessionUsecase{
+		store:    store,
+		eventSvc: eventSvc,
+		ttl:      ttl,
+	}
 }
 
 func (uc *sessionUsecase) CreateSession(ctx context.Context, tableID uuid.UUID) (*domain.TableSession, error) {
-	// Generate random token
-	bytes := make([]byte, 16)
-	if _, err := rand.Read(bytes); err != nil {
+	return uc.openSession(ctx, tableID, true, nil)
+}
+
+func (uc *sessionUsecase) SeatTable(ctx context.Context, tableID uuid.UUID, party domain.SeatedParty) (*domain.TableSession, error) {
+	return uc.openSession(ctx, tableID, false, &party)
+}
+
+// openSession starts a session at the table. With join, an open session of
+// the table is returned instead of failing. A party is marked seated at the
+// new session in the same transaction.
+func (uc *sessionUsecase) openSession(ctx context.Context, tableID uuid.UUID, join bool, party *domain.SeatedParty) (*domain.TableSession, error) {
+	var sessionID pgtype.UUID
+	created := false
+	expiresAt := pgtype.Timestamptz{Time: time.Now().Add(uc.ttl), Valid: true}
+
+	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		// Lock the reservation first; cancelling it waits for the seating
+		if party != nil && party.ReservationID != nil {
+			reservation, err := q.GetReservationForUpdate(ctx, pgtype.UUID{Bytes: *party.ReservationID, Valid: true})
+			if err != nil || reservation.Status != string(domain.ReservationStatusBooked) {
+				return errReservationNotBooked
+			}
+		}
+
+		// Lock the table so concurrent scans end up in the same session
+		table, err := q.GetTableForUpdate(ctx, pgtype.UUID{Bytes: tableID, Valid: true})
+		if err != nil {
+			return fmt.Errorf("table not found")
+		}
+		if err := checkStoreID(ctx, table.StoreID); err != nil {
+			return err
+		}
+
+		// A table has one open session; join it instead of starting another
+		active, err := q.GetActiveSessionByTable(ctx, table.ID)
+		if err == nil && !join {
+			return fmt.Errorf("table %s is occupied", table.Name)
+		}
+		if err == nil {
+			sessionID = active.ID
+			if active.ExpiresAt.Time.After(time.Now()) {
+				return nil
+			}
+			_, err = q.ExtendSession(ctx, repository.ExtendSessionParams{
+				ID:        active.ID,
+				ExpiresAt: expiresAt,
+			})
+			return err
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		// Generate random token
+		bytes := make([]byte, 16)
+		if _, err := rand.Read(bytes); err != nil {
+			return err
+		}
+		token := hex.EncodeToString(bytes)
+
+		session, err := q.CreateSession(ctx, repository.CreateSessionParams{
+			TableID:   table.ID,
+			Token:     token,
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			return err
+		}
+		sessionID = session.ID
+		created = true
+
+		// Guests sat down, so the table is no longer waiting to be cleaned
+		err = q.SetTableNeedsCleaning(ctx, repository.SetTableNeedsCleaningParams{
+			ID:            table.ID,
+			NeedsCleaning: false,
+		})
+		if err != nil || party == nil {
+			return err
+		}
+		return seatParty(ctx, q, party, table.ID, session.ID)
+	})
+	if err != nil {
 		return nil, err
 	}
-	token := hex.EncodeToString(bytes)
 
-	// Set expiry (e.g., 2 hours)
-	expiresAt := time.Now().Add(2 * time.Hour)
+	if created {
+		uc.publishTableStatus(ctx, tableID)
+	}
+	return uc.GetSession(ctx, uuid.UUID(sessionID.Bytes))
+}
+
+var (
+	errReservationNotBooked = errors.New("reservation is no longer booked")
+	errPartyNotWaiting      = errors.New("party is no longer waiting")
+)
 
-	// Create session in DB
-	session, err := uc.repo.CreateSession(ctx, repository.CreateSessionParams{
-		TableID:   pgtype.UUID{Bytes: tableID, Valid: true},
-		Token:     token,
-		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+// seatParty marks a reservation or waitlist entry seated at a new session.
+func seatParty(ctx context.Context, q *repository.Queries, party *domain.SeatedParty, tableID, sessionID pgtype.UUID) error {
+	var err error
+	switch {
+	case party.ReservationID != nil:
+		_, err = q.SeatReservation(ctx, repository.SeatReservationParams{
+			ID:             pgtype.UUID{Bytes: *party.ReservationID, Valid: true},
+			TableID:        tableID,
+			TableSessionID: sessionID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errReservationNotBooked
+		}
+	case party.WaitlistEntryID != nil:
+		_, err = q.SeatWaitlistEntry(ctx, repository.SeatWaitlistEntryParams{
+			ID:             pgtype.UUID{Bytes: *party.WaitlistEntryID, Valid: true},
+			TableID:        tableID,
+			TableSessionID: sessionID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errPartyNotWaiting
+		}
+	}
+	return err
+}
+
+func (uc *sessionUsecase) GetSession(ctx context.Context, sessionID uuid.UUID) (*domain.TableSession, error) {
+	row, err := uc.store.GetSession(ctx, pgtype.UUID{Bytes: sessionID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("session not found")
+	}
+	if err := checkStoreID(ctx, row.StoreID); err != nil {
+		return nil, err
+	}
+	return toDomainSessionRow(row), nil
+}
+
+// RequestBill notifies the cashier with a BILL_REQUESTED event. Asking again
+// sends another notification.
+func (uc *sessionUsecase) RequestBill(ctx context.Context, sessionID uuid.UUID) (*domain.TableSession, error) {
+	if _, err := uc.GetSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	if _, err := uc.store.RequestSessionBill(ctx, pgtype.UUID{Bytes: sessionID, Valid: true}); err != nil {
+		return nil, fmt.Errorf("session is not active")
+	}
+
+	session, err := uc.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every socket of the store gets the event, the customer token stays out
+	event := *session
+	event.Token = ""
+	_ = uc.eventSvc.PublishEvent(ctx, "BILL_REQUESTED", event)
+	uc.publishTableStatus(ctx, session.TableID)
+	return session, nil
+}
+
+func (uc *sessionUsecase) CallWaiter(ctx context.Context, sessionID uuid.UUID, note string) error {
+	session, err := uc.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if !session.IsActive {
+		return fmt.Errorf("session is not active")
+	}
+
+	return uc.eventSvc.PublishEvent(ctx, "WAITER_CALLED", domain.WaiterCall{
+		SessionID: session.ID,
+		StoreID:   session.StoreID,
+		TableID:   session.TableID,
+		TableName: session.TableName,
+		Note:      note,
+		CalledAt:  time.Now(),
+	})
+}
+
+func (uc *sessionUsecase) CloseSession(ctx context.Context, sessionID, actorID uuid.UUID) (*domain.TableSession, error) {
+	var tableID pgtype.UUID
+	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		current, err := q.GetSessionForUpdate(ctx, pgtype.UUID{Bytes: sessionID, Valid: true})
+		if err != nil {
+			return fmt.Errorf("session not found")
+		}
+		if !current.IsActive.Bool {
+			return fmt.Errorf("session is already closed")
+		}
+		table, err := q.GetTable(ctx, current.TableID)
+		if err != nil {
+			return err
+		}
+		if err := checkStoreID(ctx, table.StoreID); err != nil {
+			return err
+		}
+
+		unsettled, err := q.CountUnsettledSessionOrders(ctx, current.ID)
+		if err != nil {
+			return err
+		}
+		if unsettled > 0 {
+			return fmt.Errorf("session has %d unpaid orders; pay, void or move them first", unsettled)
+		}
+
+		if _, err := q.CloseSession(ctx, repository.CloseSessionParams{
+			ID:       current.ID,
+			Status:   string(domain.SessionStatusClosed),
+			ClosedBy: pgtype.UUID{Bytes: actorID, Valid: actorID != uuid.Nil},
+		}); err != nil {
+			return err
+		}
+		tableID = current.TableID
+
+		return q.SetTableNeedsCleaning(ctx, repository.SetTableNeedsCleaningParams{
+			ID:            current.TableID,
+			NeedsCleaning: true,
+		})
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &domain.TableSession{
-		ID:        uuid.UUID(session.ID.Bytes),
-		TableID:   uuid.UUID(session.TableID.Bytes),
-		Token:     session.Token,
-		ExpiresAt: session.ExpiresAt.Time,
-		IsActive:  session.IsActive.Bool,
-	}, nil
+	uc.publishTableStatus(ctx, uuid.UUID(tableID.Bytes))
+	return uc.GetSession(ctx, sessionID)
 }
 
-func (uc *sessionUsecase) ValidateSession(ctx context.Context, token string) (*domain.TableSession, error) {
-	// Join query GetSessionByToken likely returns Row with StoreID
-	// In sessions.sql we defined: SELECT ts.*, t.store_id, t.name as table_name
-	// But GetSessionByToken in sessions.sql was: SELECT * FROM table_sessions WHERE token = $1
-	// Wait, I updated sessions.sql in step 253?
-	// Step 253: Verify content of sessions.sql
-	// "-- name: GetSessionByToken :one
-	// SELECT * FROM table_sessions
-	// WHERE token = $1 LIMIT 1;"
-	// Ah, I missed updating GetSessionByToken to include JOIN in step 253?
-	// I defined GetTableSessions with JOIN, but ValidateSession calls GetSessionByToken.
-	// Validate logic usually needs StoreID to identify context.
-	// I should update GetSessionByToken to include JOIN as well or do a separate fetch if needed.
-	// For now, I'll rely on what's available. If GetSessionByToken returns table_session only, I don't have StoreID.
-	// I will fetch table info separately or update query.
-	// Checking repository struct via error log might help, but let's just assume I need to fix query/repo.
-
-	// Assuming raw session fetch:
-	row, err := uc.repo.GetSessionByToken(ctx, token)
+// MoveSession seats the guests at another free table of the same store. Their
+// orders and token move along; the old table is left dirty.
+func (uc *sessionUsecase) MoveSession(ctx context.Context, sessionID uuid.UUID, req *domain.MoveSessionRequest) (*domain.TableSession, error) {
+	var fromTableID pgtype.UUID
+	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		current, err := q.GetSessionForUpdate(ctx, pgtype.UUID{Bytes: sessionID, Valid: true})
+		if err != nil {
+			return fmt.Errorf("session not found")
+		}
+		if !current.IsActive.Bool {
+			return fmt.Errorf("session is not active")
+		}
+		if uuid.UUID(current.TableID.Bytes) == req.TableID {
+			return fmt.Errorf("session is already at this table")
+		}
+
+		from, err := q.GetTable(ctx, current.TableID)
+		if err != nil {
+			return err
+		}
+		if err := checkStoreID(ctx, from.StoreID); err != nil {
+			return err
+		}
+		to, err := q.GetTableForUpdate(ctx, pgtype.UUID{Bytes: req.TableID, Valid: true})
+		if err != nil {
+			return fmt.Errorf("table not found")
+		}
+		if to.StoreID != from.StoreID {
+			return fmt.Errorf("table belongs to another store")
+		}
+		if _, err := q.GetActiveSessionByTable(ctx, to.ID); err == nil {
+			return fmt.Errorf("table %s is occupied; merge the sessions instead", to.Name)
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		if _, err := q.MoveSession(ctx, repository.MoveSessionParams{
+			ID:      current.ID,
+			TableID: to.ID,
+		}); err != nil {
+			return err
+		}
+		// Wait for order updates in flight; moved orders get a new version
+		if _, err := q.LockSessionOrders(ctx, current.ID); err != nil {
+			return err
+		}
+		if _, err := q.MoveSessionOrders(ctx, repository.MoveSessionOrdersParams{
+			TargetSessionID: current.ID,
+			TargetTableID:   to.ID,
+			SourceSessionID: current.ID,
+		}); err != nil {
+			return err
+		}
+		fromTableID = from.ID
+
+		if err := q.SetTableNeedsCleaning(ctx, repository.SetTableNeedsCleaningParams{
+			ID:            from.ID,
+			NeedsCleaning: true,
+		}); err != nil {
+			return err
+		}
+		return q.SetTableNeedsCleaning(ctx, repository.SetTableNeedsCleaningParams{
+			ID:            to.ID,
+			NeedsCleaning: false,
+		})
+	})
 	if err != nil {
-		return nil, fmt.Errorf("invalid or expired session")
+		return nil, err
+	}
+
+	uc.publishTableStatus(ctx, uuid.UUID(fromTableID.Bytes), req.TableID)
+	return uc.GetSession(ctx, sessionID)
+}
+
+// MergeSessions joins two occupied tables: the orders of the source session
+// move to the target session and table, and the source session is closed as
+// MERGED. Guests of the source table continue with the target token.
+func (uc *sessionUsecase) MergeSessions(ctx context.Context, req *domain.MergeSessionsRequest, actorID uuid.UUID) (*domain.TableSession, error) {
+	if req.SourceSessionID == req.TargetSessionID {
+		return nil, fmt.Errorf("cannot merge a session into itself")
+	}
+
+	var sourceTableID pgtype.UUID
+	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		// Lock in a fixed order so two opposite merges cannot deadlock
+		first, second := req.SourceSessionID, req.TargetSessionID
+		if first.String() > second.String() {
+			first, second = second, first
+		}
+		locked := make(map[uuid.UUID]repository.TableSession, 2)
+		for _, id := range []uuid.UUID{first, second} {
+			s, err := q.GetSessionForUpdate(ctx, pgtype.UUID{Bytes: id, Valid: true})
+			if err != nil {
+				return fmt.Errorf("session not found: %s", id)
+			}
+			if !s.IsActive.Bool {
+				return fmt.Errorf("session is not active: %s", id)
+			}
+			locked[id] = s
+		}
+		source, target := locked[req.SourceSessionID], locked[req.TargetSessionID]
+
+		sourceTable, err := q.GetTable(ctx, source.TableID)
+		if err != nil {
+			return err
+		}
+		targetTable, err := q.GetTable(ctx, target.TableID)
+		if err != nil {
+			return err
+		}
+		if sourceTable.StoreID != targetTable.StoreID {
+			return fmt.Errorf("sessions belong to different stores")
+		}
+		if err := checkStoreID(ctx, sourceTable.StoreID); err != nil {
+			return err
+		}
+
+		// Wait for order updates in flight; moved orders get a new version
+		if _, err := q.LockSessionOrders(ctx, source.ID); err != nil {
+			return err
+		}
+		if _, err := q.MoveSessionOrders(ctx, repository.MoveSessionOrdersParams{
+			TargetSessionID: target.ID,
+			TargetTableID:   target.TableID,
+			SourceSessionID: source.ID,
+		}); err != nil {
+			return err
+		}
+		if _, err := q.CloseSession(ctx, repository.CloseSessionParams{
+			ID:           source.ID,
+			Status:       string(domain.SessionStatusMerged),
+			ClosedBy:     pgtype.UUID{Bytes: actorID, Valid: actorID != uuid.Nil},
+			MergedIntoID: target.ID,
+		}); err != nil {
+			return err
+		}
+		sourceTableID = source.TableID
+
+		return q.SetTableNeedsCleaning(ctx, repository.SetTableNeedsCleaningParams{
+			ID:            source.TableID,
+			NeedsCleaning: true,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := uc.GetSession(ctx, req.TargetSessionID)
+	if err != nil {
+		return nil, err
 	}
+	uc.publishTableStatus(ctx, uuid.UUID(sourceTableID.Bytes), target.TableID)
+	return target, nil
+}
+
+// ExpireSessions closes expired sessions without unpaid orders. The others
+// stay open so the cashier still sees the table as occupied.
+func (uc *sessionUsecase) ExpireSessions(ctx context.Context) (int64, error) {
+	var tableIDs []uuid.UUID
+	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		sessions, err := q.LockExpiredSessions(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range sessions {
+			// Counted after the lock, so an order added just before is seen
+			unsettled, err := q.CountUnsettledSessionOrders(ctx, s.ID)
+			if err != nil {
+				return err
+			}
+			if unsettled > 0 {
+				continue
+			}
 
-	// We need StoreID.
-	// Let's assume we need to join manually or correct the query.
-	// Correct Approach: Update sessions.sql GetSessionByToken to JOIN tables.
-	// But Sqlc generates "GetSessionByTokenRow" struct if cols change.
-	// I want to avoid re-running sqlc right now if I can (it takes manual steps).
-	// I will check if I can just returning session without StoreID validation?
-	// Domain struct requires StoreID.
-	// I'll fetch Table to get StoreID.
+			if _, err := q.CloseSession(ctx, repository.CloseSessionParams{
+				ID:     s.ID,
+				Status: string(domain.SessionStatusClosed),
+			}); err != nil {
+				return err
+			}
+			if err := q.SetTableNeedsCleaning(ctx, repository.SetTableNeedsCleaningParams{
+				ID:            s.TableID,
+				NeedsCleaning: true,
+			}); err != nil {
+				return err
+			}
+			tableIDs = append(tableIDs, uuid.UUID(s.TableID.Bytes))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	uc.publishTableStatus(ctx, tableIDs...)
+	return int64(len(tableIDs)), nil
+}
 
-	// But `uc.repo` (Querier) might not have `GetTable`. `GetTable` is likely in `stores.sql` or `tables.sql`?
-	// It's in `stores.sql`? No.
-	// I don't have `GetTable` query generated?
-	// I should update sessions.sql to join correctly. It's cleaner.
-	// So I will update sessions.sql AND run sqlc generate. (Fast path).
+// publishTableStatus pushes the new board state of the tables to the
+// connected screens. Failures only delay the board until its next refresh.
+func (uc *sessionUsecase) publishTableStatus(ctx context.Context, tableIDs ...uuid.UUID) {
+	for _, id := range tableIDs {
+		status, err := loadTableStatus(ctx, uc.store, id)
+		if err != nil {
+			continue
+		}
+		_ = uc.eventSvc.PublishEvent(ctx, "TABLE_STATUS_CHANGED", status)
+	}
+}
 
-	// NOTE: For now, avoiding sqlc run to save turn. I'll mock StoreID or fetch from session if I can.
-	// Wait, CreateSession returns session, it doesn't return StoreID.
-	// I'll stick to simple session validation.
+// ValidateSession resolves a customer token. Expired and closed sessions are
+// rejected.
+func (uc *sessionUsecase) ValidateSession(ctx context.Context, token string) (*domain.TableSession, error) {
+	// The token tells the store, it is looked up across all of them
+	row, err := uc.store.GetActiveSessionByToken(domain.WithUnscopedAccess(ctx), token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid or expired session")
+	}
+	return toDomainSessionRow(repository.GetSessionRow(row)), nil
+}
 
+func toDomainSession(s repository.TableSession) *domain.TableSession {
 	return &domain.TableSession{
-		ID:        uuid.UUID(row.ID.Bytes),
-		TableID:   uuid.UUID(row.TableID.Bytes),
-		Token:     row.Token,
-		ExpiresAt: row.ExpiresAt.Time,
-		IsActive:  row.IsActive.Bool,
-	}, nil
+		ID:              uuid.UUID(s.ID.Bytes),
+		TableID:         uuid.UUID(s.TableID.Bytes),
+		Token:           s.Token,
+		ExpiresAt:       s.ExpiresAt.Time,
+		IsActive:        s.IsActive.Bool,
+		Status:          domain.SessionStatus(s.Status),
+		BillRequestedAt: optionalTime(s.BillRequestedAt),
+		ClosedAt:        optionalTime(s.ClosedAt),
+		MergedIntoID:    optionalUUID(s.MergedIntoID),
+		CreatedAt:       s.CreatedAt.Time,
+	}
+}
+
+// toDomainSessionRow adds the table and store of the session.
+func toDomainSessionRow(row repository.GetSessionRow) *domain.TableSession {
+	session := toDomainSession(repository.TableSession{
+		ID:              row.ID,
+		TableID:         row.TableID,
+		Token:           row.Token,
+		ExpiresAt:       row.ExpiresAt,
+		IsActive:        row.IsActive,
+		CreatedAt:       row.CreatedAt,
+		Status:          row.Status,
+		BillRequestedAt: row.BillRequestedAt,
+		ClosedAt:        row.ClosedAt,
+		ClosedBy:        row.ClosedBy,
+		MergedIntoID:    row.MergedIntoID,
+	})
+	session.StoreID = uuid.UUID(row.StoreID.Bytes)
+	session.TableName = row.TableName
+	session.StoreName = row.StoreName
+	return session
 }
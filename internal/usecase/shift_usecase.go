@@ -22,6 +22,10 @@ func NewShiftUsecase(store repository.Repository) domain.ShiftUsecase {
 }
 
 func (uc *shiftUsecase) OpenShift(ctx context.Context, req *domain.OpenShiftRequest) (*domain.Shift, error) {
+	if err := checkStore(ctx, req.StoreID); err != nil {
+		return nil, err
+	}
+
 	// Check if user already has open shift
 	existing, err := uc.store.GetCurrentShift(ctx, pgtype.UUID{Bytes: req.UserID, Valid: true})
 	if err == nil && existing.ID.Valid {
@@ -34,6 +38,7 @@ func (uc *shiftUsecase) OpenShift(ctx context.Context, req *domain.OpenShiftRequ
 		UserID:      pgtype.UUID{Bytes: req.UserID, Valid: true},
 		StoreID:     pgtype.UUID{Bytes: req.StoreID, Valid: true},
 		OpeningCash: startCash,
+		TerminalID:  uuidParam(req.TerminalID),
 	})
 	if err != nil {
 		return nil, err
@@ -46,6 +51,7 @@ func (uc *shiftUsecase) OpenShift(ctx context.Context, req *domain.OpenShiftRequ
 		StoreID:     uuid.UUID(s.StoreID.Bytes),
 		OpenedAt:    s.OpenedAt.Time,
 		OpeningCash: scVal.Float64,
+		TerminalID:  optionalUUID(s.TerminalID),
 	}, nil
 }
 
@@ -53,10 +59,22 @@ func (uc *shiftUsecase) CloseShift(ctx context.Context, req *domain.CloseShiftRe
 	// Calculate expected cash from sales during this shift (complex query needed)
 	expectedCash := 0.0 // Placeholder
 
-	s, err := uc.store.CloseShift(ctx, repository.CloseShiftParams{
-		ID:           pgtype.UUID{Bytes: req.ShiftID, Valid: true},
-		ClosingCash:  pgtype.Numeric{Int: big.NewInt(int64(req.ClosingCash * 100)), Exp: -2, Valid: true},
-		ExpectedCash: pgtype.Numeric{Int: big.NewInt(int64(expectedCash * 100)), Exp: -2, Valid: true},
+	var s repository.Shift
+	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		current, err := q.GetShiftForUpdate(ctx, pgtype.UUID{Bytes: req.ShiftID, Valid: true})
+		if err != nil {
+			return fmt.Errorf("shift not found")
+		}
+		if err := checkStoreID(ctx, current.StoreID); err != nil {
+			return err
+		}
+
+		s, err = q.CloseShift(ctx, repository.CloseShiftParams{
+			ID:           current.ID,
+			ClosingCash:  pgtype.Numeric{Int: big.NewInt(int64(req.ClosingCash * 100)), Exp: -2, Valid: true},
+			ExpectedCash: pgtype.Numeric{Int: big.NewInt(int64(expectedCash * 100)), Exp: -2, Valid: true},
+		})
+		return err
 	})
 	if err != nil {
 		return nil, err
@@ -80,6 +98,7 @@ func (uc *shiftUsecase) CloseShift(ctx context.Context, req *domain.CloseShiftRe
 		ClosingCash:  &ec.Float64,
 		ExpectedCash: &exp.Float64,
 		ClosedAt:     closedAt,
+		TerminalID:   optionalUUID(s.TerminalID),
 	}, nil
 }
 
@@ -88,6 +107,9 @@ func (uc *shiftUsecase) GetCurrentShift(ctx context.Context, userID uuid.UUID) (
 	if err != nil {
 		return nil, err
 	}
+	if err := checkStoreID(ctx, s.StoreID); err != nil {
+		return nil, err
+	}
 	sc, _ := s.OpeningCash.Float64Value()
 	return &domain.Shift{
 		ID:          uuid.UUID(s.ID.Bytes),
@@ -95,5 +117,6 @@ func (uc *shiftUsecase) GetCurrentShift(ctx context.Context, userID uuid.UUID) (
 		StoreID:     uuid.UUID(s.StoreID.Bytes),
 		OpenedAt:    s.OpenedAt.Time,
 		OpeningCash: sc.Float64,
+		TerminalID:  optionalUUID(s.TerminalID),
 	}, nil
 }
@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type slaUsecase struct {
+	store    repository.Repository
+	eventSvc domain.EventService
+}
+
+func NewSLAUsecase(store repository.Repository, eventSvc domain.EventService) domain.SLAUsecase {
+	return &slaUsecase{
+		store:    store,
+		eventSvc: eventSvc,
+	}
+}
+
+// isSLAStage reports whether orders can wait in status at the store: only
+// statuses that are not terminal in the store-wide graph or in the graph of
+// some order type can be tracked.
+func isSLAStage(ctx context.Context, q repository.Querier, storeID pgtype.UUID, status domain.OrderStatus) (bool, error) {
+	orderTypes := []string{""}
+	for _, t := range domain.OrderTypes {
+		orderTypes = append(orderTypes, string(t))
+	}
+	for _, orderType := range orderTypes {
+		workflow, err := loadWorkflow(ctx, q, storeID, orderType)
+		if err != nil {
+			return false, err
+		}
+		if !isTerminalOrderStatus(workflow, status) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (uc *slaUsecase) SetTargets(ctx context.Context, req *domain.SetSLATargetsRequest) ([]domain.SLATarget, error) {
+	if err := checkStore(ctx, req.StoreID); err != nil {
+		return nil, err
+	}
+
+	storeID := pgtype.UUID{Bytes: req.StoreID, Valid: true}
+	for _, t := range req.Targets {
+		if t.TargetSeconds == 0 {
+			continue // Removing a target is always allowed
+		}
+		ok, err := isSLAStage(ctx, uc.store, storeID, t.Status)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("SLA targets are not supported for status %s", t.Status)
+		}
+	}
+
+	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		for _, t := range req.Targets {
+			if t.TargetSeconds == 0 {
+				err := q.DeleteSLATarget(ctx, repository.DeleteSLATargetParams{
+					StoreID: storeID,
+					Status:  string(t.Status),
+				})
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			_, err := q.UpsertSLATarget(ctx, repository.UpsertSLATargetParams{
+				StoreID:       storeID,
+				Status:        string(t.Status),
+				TargetSeconds: t.TargetSeconds,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.GetTargets(ctx, req.StoreID)
+}
+
+func (uc *slaUsecase) GetTargets(ctx context.Context, storeID uuid.UUID) ([]domain.SLATarget, error) {
+	if err := checkStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	rows, err := uc.store.ListSLATargets(ctx, pgtype.UUID{Bytes: storeID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]domain.SLATarget, 0, len(rows))
+	for _, t := range rows {
+		res = append(res, domain.SLATarget{
+			StoreID:       uuid.UUID(t.StoreID.Bytes),
+			Status:        domain.OrderStatus(t.Status),
+			TargetSeconds: t.TargetSeconds,
+			UpdatedAt:     t.UpdatedAt.Time,
+		})
+	}
+	return res, nil
+}
+
+func (uc *slaUsecase) CheckOverdue(ctx context.Context) ([]domain.OverdueOrder, error) {
+	rows, err := uc.store.ListOverdueOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	workflows := make(map[string]*domain.Workflow)
+	var res []domain.OverdueOrder
+	for _, row := range rows {
+		// Orders resting in a terminal custom status (e.g. BILLED) are finished
+		key := uuid.UUID(row.StoreID.Bytes).String() + "/" + row.OrderType
+		workflow, ok := workflows[key]
+		if !ok {
+			workflow, err = loadWorkflow(ctx, uc.store, row.StoreID, row.OrderType)
+			if err != nil {
+				return res, err
+			}
+			workflows[key] = workflow
+		}
+		if isTerminalOrderStatus(workflow, domain.OrderStatus(row.Status)) {
+			continue
+		}
+
+		overdue := domain.OverdueOrder{
+			OrderID:         uuid.UUID(row.ID.Bytes),
+			StoreID:         uuid.UUID(row.StoreID.Bytes),
+			OrderNumber:     row.OrderNumber,
+			Status:          domain.OrderStatus(row.Status),
+			StatusChangedAt: row.StatusChangedAt.Time,
+			TargetSeconds:   row.TargetSeconds,
+		}
+		deadline := row.StatusChangedAt.Time.Add(time.Duration(row.TargetSeconds) * time.Second)
+		overdue.OverdueSeconds = int64(now.Sub(deadline).Seconds())
+
+		// Mark first so a failing publish doesn't spam the kitchen every tick
+		marked, err := uc.store.MarkOrderOverdueNotified(ctx, repository.MarkOrderOverdueNotifiedParams{
+			ID:     row.ID,
+			Status: row.Status,
+		})
+		if err != nil {
+			return res, err
+		}
+		if marked == 0 {
+			continue // Moved on to another stage in the meantime
+		}
+		_ = uc.eventSvc.PublishEvent(ctx, "ORDER_OVERDUE", overdue)
+
+		res = append(res, overdue)
+	}
+	return res, nil
+}
+
+func (uc *slaUsecase) GetPrepTimeReport(ctx context.Context, storeID uuid.UUID, from, to time.Time) (*domain.PrepTimeReport, error) {
+	if err := checkStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	if !to.After(from) {
+		return nil, fmt.Errorf("invalid date range")
+	}
+
+	sID := pgtype.UUID{Bytes: storeID, Valid: true}
+	fromTs := pgtype.Timestamptz{Time: from, Valid: true}
+	toTs := pgtype.Timestamptz{Time: to, Valid: true}
+
+	stations, err := uc.store.GetStationPrepTimes(ctx, repository.GetStationPrepTimesParams{
+		StoreID:  sID,
+		FromDate: fromTs,
+		ToDate:   toTs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := uc.store.GetProductPrepTimes(ctx, repository.GetProductPrepTimesParams{
+		StoreID:  sID,
+		FromDate: fromTs,
+		ToDate:   toTs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.PrepTimeReport{
+		StoreID:  storeID,
+		From:     from,
+		To:       to,
+		Stations: make([]domain.StationPrepTime, 0, len(stations)),
+		Products: make([]domain.ProductPrepTime, 0, len(products)),
+	}
+	for _, s := range stations {
+		report.Stations = append(report.Stations, domain.StationPrepTime{
+			StationID:      uuid.UUID(s.StationID.Bytes),
+			StationName:    s.StationName,
+			TicketsCount:   s.TicketsCount,
+			AvgPrepSeconds: s.AvgPrepSeconds,
+		})
+	}
+	for _, p := range products {
+		report.Products = append(report.Products, domain.ProductPrepTime{
+			ProductID:      uuid.UUID(p.ProductID.Bytes),
+			ProductName:    p.ProductName,
+			ItemsCount:     p.ItemsCount,
+			AvgPrepSeconds: p.AvgPrepSeconds,
+		})
+	}
+	return report, nil
+}
@@ -0,0 +1,523 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+	"pos-api/internal/util"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type staffUsecase struct {
+	store        repository.Repository
+	denylist     util.TokenDenylist
+	pinAttempts  util.AttemptLimiter
+	loginLimiter util.LoginLimiter
+	tokenTTL     time.Duration
+}
+
+// NewStaffUsecase revokes tokens of changed users for tokenTTL, the longest
+// lifetime of an access token.
+func NewStaffUsecase(store repository.Repository, denylist util.TokenDenylist, pinAttempts util.AttemptLimiter, loginLimiter util.LoginLimiter, tokenTTL time.Duration) domain.StaffUsecase {
+	return &staffUsecase{
+		store:        store,
+		denylist:     denylist,
+		pinAttempts:  pinAttempts,
+		loginLimiter: loginLimiter,
+		tokenTTL:     tokenTTL,
+	}
+}
+
+func (uc *staffUsecase) ListStaff(ctx context.Context, actor *domain.JwtCustomClaims, storeID *uuid.UUID) ([]domain.Profile, error) {
+	scope, err := actorScope(ctx, uc.store, actor)
+	if err != nil {
+		return nil, err
+	}
+	if scope.Valid {
+		ownStore := uuid.UUID(scope.Bytes)
+		storeID = &ownStore
+	}
+	if storeID == nil {
+		return nil, fmt.Errorf("store_id is required")
+	}
+
+	rows, err := uc.store.ListStoreProfiles(ctx, pgtype.UUID{Bytes: *storeID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]domain.Profile, 0, len(rows))
+	for _, row := range rows {
+		profiles = append(profiles, *toDomainProfile(row))
+	}
+	return profiles, nil
+}
+
+func (uc *staffUsecase) InviteStaff(ctx context.Context, actor *domain.JwtCustomClaims, req *domain.InviteStaffRequest) (*domain.StaffCredentials, error) {
+	scope, err := actorScope(ctx, uc.store, actor)
+	if err != nil {
+		return nil, err
+	}
+	storeID := uuidParam(req.StoreID)
+	if scope.Valid {
+		if !req.Role.IsStaffRole() {
+			return nil, errRegisterForbidden
+		}
+		if storeID.Valid && storeID != scope {
+			return nil, errOtherStore
+		}
+		storeID = scope
+	}
+	if !storeID.Valid {
+		return nil, fmt.Errorf("store_id is required")
+	}
+
+	password, passwordHash, err := newTemporaryPassword()
+	if err != nil {
+		return nil, err
+	}
+	var pinHash pgtype.Text
+	if req.Pin != "" {
+		hash, err := util.HashPassword(req.Pin)
+		if err != nil {
+			return nil, err
+		}
+		pinHash = pgtype.Text{String: hash, Valid: true}
+	}
+
+	var profile repository.Profile
+	err = uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		profile, err = createAccount(ctx, q, req.Email, passwordHash, req.FullName, req.Role, storeID)
+		if err != nil {
+			return err
+		}
+		if !pinHash.Valid {
+			return nil
+		}
+		if err := q.SetProfilePin(ctx, repository.SetProfilePinParams{ID: profile.ID, PinHash: pinHash}); err != nil {
+			return err
+		}
+		profile.PinHash = pinHash
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.StaffCredentials{
+		Profile:           toDomainProfile(profile),
+		TemporaryPassword: password,
+	}, nil
+}
+
+func (uc *staffUsecase) AssignStore(ctx context.Context, actor *domain.JwtCustomClaims, userID uuid.UUID, req *domain.AssignStoreRequest) (*domain.Profile, error) {
+	scope, err := actorScope(ctx, uc.store, actor)
+	if err != nil {
+		return nil, err
+	}
+	target, err := uc.getTarget(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	storeID := pgtype.UUID{Bytes: req.StoreID, Valid: true}
+	if scope.Valid {
+		if storeID != scope {
+			return nil, errOtherStore
+		}
+		if target.StoreID.Valid && target.StoreID != scope {
+			return nil, errOtherStore
+		}
+		if err := uc.checkOnlyStaffRoles(ctx, target.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	profile, err := uc.store.UpdateProfileStore(ctx, repository.UpdateProfileStoreParams{
+		ID:      target.ID,
+		StoreID: storeID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.revokeTokens(ctx, userID); err != nil {
+		return nil, err
+	}
+	return toDomainProfile(profile), nil
+}
+
+func (uc *staffUsecase) ChangeRole(ctx context.Context, actor *domain.JwtCustomClaims, userID uuid.UUID, req *domain.ChangeRoleRequest) (*domain.Profile, error) {
+	scope, err := actorScope(ctx, uc.store, actor)
+	if err != nil {
+		return nil, err
+	}
+	if scope.Valid && !req.Role.IsStaffRole() {
+		return nil, errRegisterForbidden
+	}
+	target, err := uc.getManagedTarget(ctx, actor, scope, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var profile repository.Profile
+	err = uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		profile, err = q.UpdateProfileRole(ctx, repository.UpdateProfileRoleParams{
+			ID:   target.ID,
+			Role: string(req.Role),
+		})
+		if err != nil {
+			return err
+		}
+		if err := q.DeleteUserGlobalRoles(ctx, target.ID); err != nil {
+			return err
+		}
+		_, err = q.AssignRoleToUser(ctx, repository.AssignRoleToUserParams{
+			UserID:     target.ID,
+			RoleCode:   string(req.Role),
+			AssignedBy: pgtype.UUID{Bytes: actor.UserID, Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.revokeTokens(ctx, userID); err != nil {
+		return nil, err
+	}
+	return toDomainProfile(profile), nil
+}
+
+func (uc *staffUsecase) ListRoles(ctx context.Context, actor *domain.JwtCustomClaims, userID uuid.UUID) ([]domain.RoleAssignment, error) {
+	scope, err := actorScope(ctx, uc.store, actor)
+	if err != nil {
+		return nil, err
+	}
+	target, err := uc.getManagedTarget(ctx, actor, scope, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := loadUserRoles(ctx, uc.store, target.ID, target.StoreID)
+	if err != nil {
+		return nil, err
+	}
+	return roles.toDomain(), nil
+}
+
+// AssignRole gives the user another role. A STORE_OWNER only gives staff
+// roles, and only in their own store.
+func (uc *staffUsecase) AssignRole(ctx context.Context, actor *domain.JwtCustomClaims, userID uuid.UUID, req *domain.AssignRoleRequest) (*domain.RoleAssignment, error) {
+	scope, err := actorScope(ctx, uc.store, actor)
+	if err != nil {
+		return nil, err
+	}
+	storeID := uuidParam(req.StoreID)
+	if scope.Valid {
+		if !req.Role.IsStaffRole() {
+			return nil, errRegisterForbidden
+		}
+		if storeID.Valid && storeID != scope {
+			return nil, errOtherStore
+		}
+		storeID = scope
+	}
+	target, err := uc.getManagedTarget(ctx, actor, scope, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := uc.store.AssignRoleToUser(ctx, repository.AssignRoleToUserParams{
+		UserID:     target.ID,
+		RoleCode:   string(req.Role),
+		StoreID:    storeID,
+		AssignedBy: pgtype.UUID{Bytes: actor.UserID, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("role already assigned or unknown: %w", err)
+	}
+	if err := uc.revokeTokens(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return &domain.RoleAssignment{
+		ID:         uuid.UUID(row.ID.Bytes),
+		Role:       domain.UserRole(row.RoleCode),
+		StoreID:    optionalUUID(row.StoreID),
+		AssignedAt: row.AssignedAt.Time,
+	}, nil
+}
+
+func (uc *staffUsecase) RevokeRole(ctx context.Context, actor *domain.JwtCustomClaims, userID, assignmentID uuid.UUID) error {
+	scope, err := actorScope(ctx, uc.store, actor)
+	if err != nil {
+		return err
+	}
+	target, err := uc.getManagedTarget(ctx, actor, scope, userID)
+	if err != nil {
+		return err
+	}
+
+	err = uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		row, err := q.RevokeUserRole(ctx, repository.RevokeUserRoleParams{
+			ID:     pgtype.UUID{Bytes: assignmentID, Valid: true},
+			UserID: target.ID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("role assignment not found")
+			}
+			return err
+		}
+		// Roles limited to another store are not the owner's to take
+		if scope.Valid && row.StoreID.Valid && row.StoreID != scope {
+			return errOtherStore
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return uc.revokeTokens(ctx, userID)
+}
+
+// Deactivate signs the user out everywhere; deactivated users cannot log in
+// with their password or PIN.
+func (uc *staffUsecase) Deactivate(ctx context.Context, actor *domain.JwtCustomClaims, userID uuid.UUID) (*domain.Profile, error) {
+	return uc.setActive(ctx, actor, userID, false)
+}
+
+func (uc *staffUsecase) Reactivate(ctx context.Context, actor *domain.JwtCustomClaims, userID uuid.UUID) (*domain.Profile, error) {
+	return uc.setActive(ctx, actor, userID, true)
+}
+
+func (uc *staffUsecase) ResetPassword(ctx context.Context, actor *domain.JwtCustomClaims, userID uuid.UUID) (*domain.StaffCredentials, error) {
+	scope, err := actorScope(ctx, uc.store, actor)
+	if err != nil {
+		return nil, err
+	}
+	target, err := uc.getManagedTarget(ctx, actor, scope, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	password, passwordHash, err := newTemporaryPassword()
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.store.UpdateAuthUserPassword(ctx, repository.UpdateAuthUserPasswordParams{
+		ID:                target.ID,
+		EncryptedPassword: passwordHash,
+	}); err != nil {
+		return nil, err
+	}
+	if err := uc.revokeTokens(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := uc.loginLimiter.Unlock(ctx, target.Email.String); err != nil {
+		return nil, err
+	}
+
+	return &domain.StaffCredentials{
+		Profile:           toDomainProfile(target),
+		TemporaryPassword: password,
+	}, nil
+}
+
+func (uc *staffUsecase) ResetPin(ctx context.Context, actor *domain.JwtCustomClaims, userID uuid.UUID, req *domain.ResetPinRequest) error {
+	scope, err := actorScope(ctx, uc.store, actor)
+	if err != nil {
+		return err
+	}
+	target, err := uc.getManagedTarget(ctx, actor, scope, userID)
+	if err != nil {
+		return err
+	}
+
+	pinHash, err := util.HashPassword(req.Pin)
+	if err != nil {
+		return err
+	}
+	if err := uc.store.SetProfilePin(ctx, repository.SetProfilePinParams{
+		ID:      target.ID,
+		PinHash: pgtype.Text{String: pinHash, Valid: true},
+	}); err != nil {
+		return err
+	}
+	return uc.pinAttempts.Reset(ctx, pinAttemptKey(userID))
+}
+
+// Unlock lifts the lockouts after too many wrong passwords or PINs.
+func (uc *staffUsecase) Unlock(ctx context.Context, actor *domain.JwtCustomClaims, userID uuid.UUID) error {
+	scope, err := actorScope(ctx, uc.store, actor)
+	if err != nil {
+		return err
+	}
+	target, err := uc.getManagedTarget(ctx, actor, scope, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.loginLimiter.Unlock(ctx, target.Email.String); err != nil {
+		return err
+	}
+	if err := uc.pinAttempts.Reset(ctx, pinAttemptKey(userID)); err != nil {
+		return err
+	}
+	_, err = uc.store.CreateAuditLog(ctx, repository.CreateAuditLogParams{
+		UserID:   pgtype.UUID{Bytes: actor.UserID, Valid: true},
+		Action:   "ACCOUNT_UNLOCKED",
+		Entity:   pgtype.Text{String: "Profile", Valid: true},
+		EntityID: target.ID,
+	})
+	return err
+}
+
+// ResetTwoFactor removes the authenticator of a user who lost it together
+// with the recovery codes. A role that requires 2FA makes them enrol again at
+// the next login.
+func (uc *staffUsecase) ResetTwoFactor(ctx context.Context, actor *domain.JwtCustomClaims, userID uuid.UUID) error {
+	scope, err := actorScope(ctx, uc.store, actor)
+	if err != nil {
+		return err
+	}
+	target, err := uc.getManagedTarget(ctx, actor, scope, userID)
+	if err != nil {
+		return err
+	}
+
+	return uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		if err := q.DeleteUserTotp(ctx, target.ID); err != nil {
+			return err
+		}
+		if err := q.DeleteRecoveryCodes(ctx, target.ID); err != nil {
+			return err
+		}
+		_, err := q.CreateAuditLog(ctx, repository.CreateAuditLogParams{
+			UserID:   pgtype.UUID{Bytes: actor.UserID, Valid: true},
+			Action:   "TWO_FACTOR_RESET",
+			Entity:   pgtype.Text{String: "Profile", Valid: true},
+			EntityID: target.ID,
+		})
+		return err
+	})
+}
+
+func (uc *staffUsecase) setActive(ctx context.Context, actor *domain.JwtCustomClaims, userID uuid.UUID, active bool) (*domain.Profile, error) {
+	scope, err := actorScope(ctx, uc.store, actor)
+	if err != nil {
+		return nil, err
+	}
+	target, err := uc.getManagedTarget(ctx, actor, scope, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := uc.store.SetProfileActive(ctx, repository.SetProfileActiveParams{
+		ID:       target.ID,
+		IsActive: active,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		if err := uc.revokeTokens(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	return toDomainProfile(profile), nil
+}
+
+var (
+	errOtherStore     = errors.New("user belongs to another store")
+	errStaffForbidden = errors.New("not allowed to manage this user")
+)
+
+// actorScope returns the store a STORE_OWNER manages, or an invalid UUID for
+// a SUPER_ADMIN, who manages every store.
+func actorScope(ctx context.Context, q repository.Querier, actor *domain.JwtCustomClaims) (pgtype.UUID, error) {
+	if slices.Contains(actor.Roles, string(domain.RoleSuperAdmin)) {
+		return pgtype.UUID{}, nil
+	}
+	if !slices.Contains(actor.Roles, string(domain.RoleStoreOwner)) {
+		return pgtype.UUID{}, errStaffForbidden
+	}
+	owner, err := q.GetProfile(ctx, pgtype.UUID{Bytes: actor.UserID, Valid: true})
+	if err != nil || !owner.StoreID.Valid {
+		return pgtype.UUID{}, fmt.Errorf("owner has no store")
+	}
+	return owner.StoreID, nil
+}
+
+// getTarget loads the user to change. Nobody manages their own account here.
+func (uc *staffUsecase) getTarget(ctx context.Context, actor *domain.JwtCustomClaims, userID uuid.UUID) (repository.Profile, error) {
+	if userID == actor.UserID {
+		return repository.Profile{}, fmt.Errorf("cannot change your own account")
+	}
+	target, err := uc.store.GetProfile(ctx, pgtype.UUID{Bytes: userID, Valid: true})
+	if err != nil {
+		return repository.Profile{}, fmt.Errorf("user not found")
+	}
+	return target, nil
+}
+
+// getManagedTarget also checks that a STORE_OWNER only touches staff of
+// their own store who hold nothing but staff roles.
+func (uc *staffUsecase) getManagedTarget(ctx context.Context, actor *domain.JwtCustomClaims, scope pgtype.UUID, userID uuid.UUID) (repository.Profile, error) {
+	target, err := uc.getTarget(ctx, actor, userID)
+	if err != nil {
+		return repository.Profile{}, err
+	}
+	if scope.Valid {
+		if target.StoreID != scope {
+			return repository.Profile{}, errOtherStore
+		}
+		if err := uc.checkOnlyStaffRoles(ctx, target.ID); err != nil {
+			return repository.Profile{}, err
+		}
+	}
+	return target, nil
+}
+
+func (uc *staffUsecase) checkOnlyStaffRoles(ctx context.Context, userID pgtype.UUID) error {
+	rows, err := uc.store.GetUserRoles(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !hasOnlyStaffRoles(rows) {
+		return errStaffForbidden
+	}
+	return nil
+}
+
+// revokeTokens ends every session of the user: refresh tokens are revoked and
+// access tokens, including PIN logins, are denied until they expire.
+func (uc *staffUsecase) revokeTokens(ctx context.Context, userID uuid.UUID) error {
+	if _, err := uc.store.RevokeUserRefreshTokens(ctx, pgtype.UUID{Bytes: userID, Valid: true}); err != nil {
+		return err
+	}
+	return uc.denylist.DenyUser(ctx, userID, uc.tokenTTL)
+}
+
+// newTemporaryPassword returns a random password and its hash.
+func newTemporaryPassword() (string, string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	password := base64.RawURLEncoding.EncodeToString(b)
+	hash, err := util.HashPassword(password)
+	if err != nil {
+		return "", "", err
+	}
+	return password, hash, nil
+}
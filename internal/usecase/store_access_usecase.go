@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var errStoreAccess = errors.New("no access to this store")
+
+type storeAccessUsecase struct {
+	store repository.Repository
+}
+
+func NewStoreAccessUsecase(store repository.Repository) domain.StoreAccessUsecase {
+	return &storeAccessUsecase{
+		store: store,
+	}
+}
+
+func (uc *storeAccessUsecase) ResolveStoreAccess(ctx context.Context, claims *domain.JwtCustomClaims) (*domain.StoreAccess, error) {
+	userID := pgtype.UUID{Bytes: claims.UserID, Valid: true}
+	profile, err := uc.store.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("profile not found")
+	}
+	if !profile.IsActive {
+		return nil, errAccountDeactivated
+	}
+	roles, err := uc.store.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	access := &domain.StoreAccess{StoreIDs: []uuid.UUID{}}
+	if profile.StoreID.Valid {
+		access.StoreIDs = append(access.StoreIDs, uuid.UUID(profile.StoreID.Bytes))
+	}
+	for _, row := range roles {
+		if !row.StoreID.Valid {
+			if row.RoleCode == string(domain.RoleSuperAdmin) {
+				access.AllStores = true
+			}
+			continue
+		}
+		storeID := uuid.UUID(row.StoreID.Bytes)
+		if !slices.Contains(access.StoreIDs, storeID) {
+			access.StoreIDs = append(access.StoreIDs, storeID)
+		}
+	}
+
+	if claims.DeviceID != nil && claims.StoreID != nil {
+		device := &domain.StoreAccess{StoreIDs: []uuid.UUID{}}
+		if access.Allows(*claims.StoreID) {
+			device.StoreIDs = append(device.StoreIDs, *claims.StoreID)
+		}
+		return device, nil
+	}
+	return access, nil
+}
+
+// checkStore rejects a store outside the caller's stores. Requests without a
+// store context are rejected too; jobs and public endpoints set one, see
+// domain.WithUnscopedAccess and domain.WithSingleStoreAccess.
+func checkStore(ctx context.Context, storeID uuid.UUID) error {
+	access := domain.StoreAccessFromContext(ctx)
+	if access == nil || !access.Allows(storeID) {
+		return errStoreAccess
+	}
+	return nil
+}
+
+// checkStoreID is checkStore for a store column. Rows without a store are only
+// reachable by SUPER_ADMIN.
+func checkStoreID(ctx context.Context, storeID pgtype.UUID) error {
+	if !storeID.Valid {
+		access := domain.StoreAccessFromContext(ctx)
+		if access == nil || !access.AllStores {
+			return errStoreAccess
+		}
+		return nil
+	}
+	return checkStore(ctx, uuid.UUID(storeID.Bytes))
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var errEmailNotVerified = errors.New("verify your email address before opening a store")
+
+type storeUsecase struct {
+	store repository.Repository
+}
+
+func NewStoreUsecase(store repository.Repository) domain.StoreUsecase {
+	return &storeUsecase{
+		store: store,
+	}
+}
+
+// CreateStore makes the new store the owner's own store when they have none
+// yet, otherwise gives them STORE_OWNER in it. The roles show up in their
+// token after the next refresh.
+func (uc *storeUsecase) CreateStore(ctx context.Context, actor *domain.JwtCustomClaims, req *domain.CreateStoreRequest) (*domain.Store, error) {
+	params := repository.CreateStoreParams{
+		Name:    req.Name,
+		Address: pgtype.Text{String: req.Address, Valid: req.Address != ""},
+		Phone:   pgtype.Text{String: req.Phone, Valid: req.Phone != ""},
+	}
+	if slices.Contains(actor.Roles, string(domain.RoleSuperAdmin)) {
+		row, err := uc.store.CreateStore(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return toDomainStore(row), nil
+	}
+	if !slices.Contains(actor.Roles, string(domain.RoleStoreOwner)) {
+		return nil, fmt.Errorf("only store owners can open stores")
+	}
+
+	ownerID := pgtype.UUID{Bytes: actor.UserID, Valid: true}
+	authUser, err := uc.store.GetAuthUser(ctx, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("profile not found")
+	}
+	if !authUser.EmailVerifiedAt.Valid {
+		return nil, errEmailNotVerified
+	}
+	owner, err := uc.store.GetProfile(ctx, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("profile not found")
+	}
+
+	var row repository.Store
+	err = uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		row, err = q.CreateStore(ctx, params)
+		if err != nil {
+			return err
+		}
+		if !owner.StoreID.Valid {
+			_, err = q.UpdateProfileStore(ctx, repository.UpdateProfileStoreParams{
+				ID:      ownerID,
+				StoreID: row.ID,
+			})
+			return err
+		}
+		_, err = q.AssignRoleToUser(ctx, repository.AssignRoleToUserParams{
+			UserID:     ownerID,
+			RoleCode:   string(domain.RoleStoreOwner),
+			StoreID:    row.ID,
+			AssignedBy: ownerID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toDomainStore(row), nil
+}
+
+func toDomainStore(s repository.Store) *domain.Store {
+	return &domain.Store{
+		ID:        uuid.UUID(s.ID.Bytes),
+		Name:      s.Name,
+		Address:   s.Address.String,
+		Phone:     s.Phone.String,
+		CreatedAt: s.CreatedAt.Time,
+		UpdatedAt: s.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,456 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+	"pos-api/pkg/pdf"
+	"pos-api/pkg/qrcode"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Bounds for the rendered QR images, so a query string can't ask for a huge one.
+const (
+	defaultQRScale = 10
+	maxQRScale     = 40
+	defaultQRSize  = 512
+	maxQRSize      = 4096
+)
+
+type tableUsecase struct {
+	store          repository.Repository
+	sessionUsecase domain.SessionUsecase
+	eventSvc       domain.EventService
+	qrBaseURL      string
+}
+
+// NewTableUsecase encodes qrBaseURL/<store_id>/<qr_code> in table QR codes.
+func NewTableUsecase(store repository.Repository, sessionUsecase domain.SessionUsecase, eventSvc domain.EventService, qrBaseURL string) domain.TableUsecase {
+	return &tableUsecase{
+		store:          store,
+		sessionUsecase: sessionUsecase,
+		eventSvc:       eventSvc,
+		qrBaseURL:      strings.TrimRight(qrBaseURL, "/"),
+	}
+}
+
+func (uc *tableUsecase) CreateArea(ctx context.Context, req *domain.FloorAreaRequest) (*domain.FloorArea, error) {
+	if err := checkStore(ctx, req.StoreID); err != nil {
+		return nil, err
+	}
+
+	area, err := uc.store.CreateFloorArea(ctx, repository.CreateFloorAreaParams{
+		StoreID:   pgtype.UUID{Bytes: req.StoreID, Valid: true},
+		Name:      req.Name,
+		SortOrder: req.SortOrder,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := toDomainFloorArea(area)
+	return &res, nil
+}
+
+func (uc *tableUsecase) ListAreas(ctx context.Context, storeID uuid.UUID) ([]domain.FloorArea, error) {
+	if err := checkStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	rows, err := uc.store.ListFloorAreas(ctx, pgtype.UUID{Bytes: storeID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]domain.FloorArea, 0, len(rows))
+	for _, a := range rows {
+		res = append(res, toDomainFloorArea(a))
+	}
+	return res, nil
+}
+
+func (uc *tableUsecase) UpdateArea(ctx context.Context, areaID uuid.UUID, req *domain.FloorAreaRequest) (*domain.FloorArea, error) {
+	if err := checkStore(ctx, req.StoreID); err != nil {
+		return nil, err
+	}
+
+	current, err := uc.store.GetFloorArea(ctx, pgtype.UUID{Bytes: areaID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("floor area not found")
+	}
+	if uuid.UUID(current.StoreID.Bytes) != req.StoreID {
+		return nil, fmt.Errorf("floor area belongs to another store")
+	}
+
+	area, err := uc.store.UpdateFloorArea(ctx, repository.UpdateFloorAreaParams{
+		ID:        current.ID,
+		Name:      req.Name,
+		SortOrder: req.SortOrder,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := toDomainFloorArea(area)
+	return &res, nil
+}
+
+// DeleteArea keeps the tables of the area; they become unassigned.
+func (uc *tableUsecase) DeleteArea(ctx context.Context, areaID uuid.UUID) error {
+	area, err := uc.store.GetFloorArea(ctx, pgtype.UUID{Bytes: areaID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("floor area not found")
+	}
+	if err := checkStoreID(ctx, area.StoreID); err != nil {
+		return err
+	}
+	return uc.store.DeleteFloorArea(ctx, area.ID)
+}
+
+func (uc *tableUsecase) CreateTable(ctx context.Context, req *domain.CreateTableRequest) (*domain.Table, error) {
+	if err := checkStore(ctx, req.StoreID); err != nil {
+		return nil, err
+	}
+
+	areaID, err := uc.checkArea(ctx, req.StoreID, req.AreaID)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := newQRCode()
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := uc.store.CreateTable(ctx, repository.CreateTableParams{
+		StoreID:  pgtype.UUID{Bytes: req.StoreID, Valid: true},
+		AreaID:   areaID,
+		Name:     req.Name,
+		Capacity: pgtype.Int4{Int32: req.Capacity, Valid: true},
+		QrCode:   pgtype.Text{String: code, Valid: true},
+		PosX:     req.PosX,
+		PosY:     req.PosY,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := uc.toDomainTable(table)
+	return &res, nil
+}
+
+func (uc *tableUsecase) GetTable(ctx context.Context, tableID uuid.UUID) (*domain.Table, error) {
+	table, err := uc.store.GetTable(ctx, pgtype.UUID{Bytes: tableID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("table not found")
+	}
+	if err := checkStoreID(ctx, table.StoreID); err != nil {
+		return nil, err
+	}
+	res := uc.toDomainTable(table)
+	return &res, nil
+}
+
+func (uc *tableUsecase) ListTables(ctx context.Context, storeID uuid.UUID, areaID *uuid.UUID) ([]domain.Table, error) {
+	if err := checkStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	rows, err := uc.store.ListTables(ctx, pgtype.UUID{Bytes: storeID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]domain.Table, 0, len(rows))
+	for _, t := range rows {
+		table := uc.toDomainTable(t)
+		if areaID != nil && (table.AreaID == nil || *table.AreaID != *areaID) {
+			continue
+		}
+		res = append(res, table)
+	}
+	return res, nil
+}
+
+func (uc *tableUsecase) UpdateTable(ctx context.Context, tableID uuid.UUID, req *domain.UpdateTableRequest) (*domain.Table, error) {
+	current, err := uc.GetTable(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	areaID, err := uc.checkArea(ctx, current.StoreID, req.AreaID)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := uc.store.UpdateTable(ctx, repository.UpdateTableParams{
+		ID:       pgtype.UUID{Bytes: tableID, Valid: true},
+		AreaID:   areaID,
+		Name:     req.Name,
+		Capacity: pgtype.Int4{Int32: req.Capacity, Valid: true},
+		PosX:     req.PosX,
+		PosY:     req.PosY,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := uc.toDomainTable(table)
+	return &res, nil
+}
+
+func (uc *tableUsecase) DeleteTable(ctx context.Context, tableID uuid.UUID) error {
+	if _, err := uc.GetTable(ctx, tableID); err != nil {
+		return err
+	}
+	id := pgtype.UUID{Bytes: tableID, Valid: true}
+	_, err := uc.store.GetActiveSessionByTable(ctx, id)
+	if err == nil {
+		return fmt.Errorf("table has an active session")
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	return uc.store.DeleteTable(ctx, id)
+}
+
+func (uc *tableUsecase) QRCodePNG(ctx context.Context, tableID uuid.UUID, scale int) ([]byte, error) {
+	code, err := uc.encodeTable(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	if scale <= 0 || scale > maxQRScale {
+		scale = defaultQRScale
+	}
+	return code.PNG(scale)
+}
+
+func (uc *tableUsecase) QRCodeSVG(ctx context.Context, tableID uuid.UUID, size int) (string, error) {
+	code, err := uc.encodeTable(ctx, tableID)
+	if err != nil {
+		return "", err
+	}
+	if size <= 0 || size > maxQRSize {
+		size = defaultQRSize
+	}
+	return code.SVG(size), nil
+}
+
+// QRSheetPDF prints six QR codes per A4 page (2 x 3), each with the table
+// and area name below and a dashed cutting guide around it.
+func (uc *tableUsecase) QRSheetPDF(ctx context.Context, storeID uuid.UUID, areaID *uuid.UUID) ([]byte, error) {
+	tables, err := uc.ListTables(ctx, storeID, areaID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("no tables to print")
+	}
+
+	areas, err := uc.ListAreas(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	areaNames := make(map[uuid.UUID]string, len(areas))
+	for _, a := range areas {
+		areaNames[a.ID] = a.Name
+	}
+
+	const (
+		cols, rows = 2, 3
+		margin     = 36.0
+		qrSize     = 170.0
+	)
+	cellW := (pdf.A4Width - 2*margin) / cols
+	cellH := (pdf.A4Height - 2*margin) / rows
+
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+	var page *pdf.Page
+	for i, t := range tables {
+		if i%(cols*rows) == 0 {
+			page = doc.AddPage()
+		}
+		code, err := qrcode.Encode(t.QRURL, qrcode.M)
+		if err != nil {
+			return nil, err
+		}
+
+		slot := i % (cols * rows)
+		x := margin + float64(slot%cols)*cellW
+		y := margin + float64(slot/cols)*cellH
+		page.StrokeRect(x, y, cellW, cellH)
+
+		module := qrSize / float64(code.Size+2*qrcode.QuietZone)
+		qrX := x + (cellW-qrSize)/2 + module*qrcode.QuietZone
+		qrY := y + 24 + module*qrcode.QuietZone
+		for my := 0; my < code.Size; my++ {
+			for mx := 0; mx < code.Size; mx++ {
+				if code.Modules[my][mx] {
+					page.FillRect(qrX+float64(mx)*module, qrY+float64(my)*module, module, module)
+				}
+			}
+		}
+
+		labelY := y + 24 + qrSize + 28
+		page.Text(x+(cellW-pdf.TextWidth(t.Name, 18))/2, labelY, 18, t.Name)
+		if t.AreaID != nil {
+			area := areaNames[*t.AreaID]
+			page.Text(x+(cellW-pdf.TextWidth(area, 11))/2, labelY+18, 11, area)
+		}
+	}
+	return doc.Bytes(), nil
+}
+
+func (uc *tableUsecase) StartSession(ctx context.Context, qrCode string) (*domain.TableSession, error) {
+	table, err := uc.store.GetTableByQRCode(domain.WithUnscopedAccess(ctx), pgtype.Text{String: qrCode, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("unknown table QR code")
+	}
+	if !table.StoreID.Valid {
+		return nil, fmt.Errorf("unknown table QR code")
+	}
+	// The QR code is the customer's only credential, it grants the table's store
+	ctx = domain.WithSingleStoreAccess(ctx, uuid.UUID(table.StoreID.Bytes))
+	return uc.sessionUsecase.CreateSession(ctx, uuid.UUID(table.ID.Bytes))
+}
+
+func (uc *tableUsecase) GetBoard(ctx context.Context, storeID uuid.UUID) ([]domain.TableStatus, error) {
+	if err := checkStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	rows, err := uc.store.ListTableStatuses(ctx, pgtype.UUID{Bytes: storeID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]domain.TableStatus, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, toDomainTableStatus(row))
+	}
+	return res, nil
+}
+
+func (uc *tableUsecase) MarkClean(ctx context.Context, tableID uuid.UUID) (*domain.TableStatus, error) {
+	status, err := loadTableStatus(ctx, uc.store, tableID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStore(ctx, status.StoreID); err != nil {
+		return nil, err
+	}
+	if status.State != domain.TableStateDirty {
+		return status, nil
+	}
+
+	if err := uc.store.SetTableNeedsCleaning(ctx, repository.SetTableNeedsCleaningParams{
+		ID:            pgtype.UUID{Bytes: tableID, Valid: true},
+		NeedsCleaning: false,
+	}); err != nil {
+		return nil, err
+	}
+
+	status.State = domain.TableStateFree
+	_ = uc.eventSvc.PublishEvent(ctx, "TABLE_STATUS_CHANGED", status)
+	return status, nil
+}
+
+func (uc *tableUsecase) encodeTable(ctx context.Context, tableID uuid.UUID) (*qrcode.Code, error) {
+	table, err := uc.GetTable(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	return qrcode.Encode(table.QRURL, qrcode.M)
+}
+
+// checkArea makes sure an area belongs to the store of the table.
+func (uc *tableUsecase) checkArea(ctx context.Context, storeID uuid.UUID, areaID *uuid.UUID) (pgtype.UUID, error) {
+	if areaID == nil {
+		return pgtype.UUID{}, nil
+	}
+	area, err := uc.store.GetFloorArea(ctx, pgtype.UUID{Bytes: *areaID, Valid: true})
+	if err != nil {
+		return pgtype.UUID{}, fmt.Errorf("floor area not found")
+	}
+	if uuid.UUID(area.StoreID.Bytes) != storeID {
+		return pgtype.UUID{}, fmt.Errorf("floor area belongs to another store")
+	}
+	return area.ID, nil
+}
+
+func (uc *tableUsecase) toDomainTable(t repository.Table) domain.Table {
+	storeID := uuid.UUID(t.StoreID.Bytes)
+	return domain.Table{
+		ID:        uuid.UUID(t.ID.Bytes),
+		StoreID:   storeID,
+		AreaID:    optionalUUID(t.AreaID),
+		Name:      t.Name,
+		Capacity:  t.Capacity.Int32,
+		PosX:      t.PosX,
+		PosY:      t.PosY,
+		QRCode:    t.QrCode.String,
+		QRURL:     fmt.Sprintf("%s/%s/%s", uc.qrBaseURL, storeID, t.QrCode.String),
+		CreatedAt: t.CreatedAt.Time,
+		UpdatedAt: t.UpdatedAt.Time,
+	}
+}
+
+// loadTableStatus returns the board tile of one table.
+func loadTableStatus(ctx context.Context, q repository.Querier, tableID uuid.UUID) (*domain.TableStatus, error) {
+	row, err := q.GetTableStatus(ctx, pgtype.UUID{Bytes: tableID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("table not found")
+	}
+	status := toDomainTableStatus(repository.ListTableStatusesRow(row))
+	return &status, nil
+}
+
+func toDomainTableStatus(row repository.ListTableStatusesRow) domain.TableStatus {
+	status := domain.TableStatus{
+		TableID:          uuid.UUID(row.ID.Bytes),
+		StoreID:          uuid.UUID(row.StoreID.Bytes),
+		AreaID:           optionalUUID(row.AreaID),
+		Name:             row.Name,
+		Capacity:         row.Capacity.Int32,
+		PosX:             row.PosX,
+		PosY:             row.PosY,
+		SessionID:        optionalUUID(row.SessionID),
+		SessionStartedAt: optionalTime(row.SessionStartedAt),
+		BillRequestedAt:  optionalTime(row.BillRequestedAt),
+		OpenOrders:       row.OpenOrders,
+	}
+
+	switch {
+	case row.SessionStatus.String == string(domain.SessionStatusBillRequested):
+		status.State = domain.TableStateBillRequested
+	case row.SessionID.Valid:
+		status.State = domain.TableStateOccupied
+	case row.NeedsCleaning:
+		status.State = domain.TableStateDirty
+	default:
+		status.State = domain.TableStateFree
+	}
+	return status
+}
+
+func toDomainFloorArea(a repository.FloorArea) domain.FloorArea {
+	return domain.FloorArea{
+		ID:        uuid.UUID(a.ID.Bytes),
+		StoreID:   uuid.UUID(a.StoreID.Bytes),
+		Name:      a.Name,
+		SortOrder: a.SortOrder,
+		CreatedAt: a.CreatedAt.Time,
+	}
+}
+
+func newQRCode() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,332 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+	"pos-api/internal/util"
+	"pos-api/pkg/qrcode"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type twoFactorUsecase struct {
+	store        repository.Repository
+	totp         *util.TOTP
+	loginLimiter util.LoginLimiter
+}
+
+// NewTwoFactorUsecase counts wrong codes as failed logins of the user, so
+// guessing codes ends in the same lockout as guessing passwords.
+func NewTwoFactorUsecase(store repository.Repository, totp *util.TOTP, loginLimiter util.LoginLimiter) domain.TwoFactorUsecase {
+	return &twoFactorUsecase{
+		store:        store,
+		totp:         totp,
+		loginLimiter: loginLimiter,
+	}
+}
+
+func (uc *twoFactorUsecase) Status(ctx context.Context, userID uuid.UUID) (*domain.TwoFactorStatus, error) {
+	id := pgtype.UUID{Bytes: userID, Valid: true}
+	status := &domain.TwoFactorStatus{}
+
+	row, err := uc.store.GetUserTotp(ctx, id)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	status.Enabled = err == nil && row.ConfirmedAt.Valid
+
+	status.Required, err = uc.store.UserRequiresTwoFactor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if status.Enabled {
+		status.RecoveryCodesLeft, err = uc.store.CountRecoveryCodes(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+func (uc *twoFactorUsecase) Enroll(ctx context.Context, userID uuid.UUID) (*domain.TOTPEnrollment, error) {
+	profile, err := uc.store.GetProfile(ctx, pgtype.UUID{Bytes: userID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("profile not found")
+	}
+	return enrollTOTP(ctx, uc.store, uc.totp, profile.ID, profile.Email.String)
+}
+
+func (uc *twoFactorUsecase) Confirm(ctx context.Context, userID uuid.UUID, req *domain.TwoFactorCodeRequest) (*domain.RecoveryCodes, error) {
+	codes, err := confirmTOTP(ctx, uc.store, uc.totp, pgtype.UUID{Bytes: userID, Valid: true}, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.RecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+func (uc *twoFactorUsecase) Disable(ctx context.Context, userID uuid.UUID, req *domain.TwoFactorCodeRequest) error {
+	id := pgtype.UUID{Bytes: userID, Valid: true}
+	required, err := uc.store.UserRequiresTwoFactor(ctx, id)
+	if err != nil {
+		return err
+	}
+	if required {
+		return fmt.Errorf("two-factor authentication is required for your role")
+	}
+	if err := uc.checkCode(ctx, id, req.Code); err != nil {
+		return err
+	}
+
+	return uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		if err := q.DeleteUserTotp(ctx, id); err != nil {
+			return err
+		}
+		return q.DeleteRecoveryCodes(ctx, id)
+	})
+}
+
+func (uc *twoFactorUsecase) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, req *domain.TwoFactorCodeRequest) (*domain.RecoveryCodes, error) {
+	id := pgtype.UUID{Bytes: userID, Valid: true}
+	if err := uc.checkCode(ctx, id, req.Code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		return replaceRecoveryCodes(ctx, q, id, hashes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.RecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+func (uc *twoFactorUsecase) ListRoleRequirements(ctx context.Context) ([]domain.RoleTwoFactor, error) {
+	rows, err := uc.store.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]domain.RoleTwoFactor, 0, len(rows))
+	for _, row := range rows {
+		roles = append(roles, toDomainRoleTwoFactor(row))
+	}
+	return roles, nil
+}
+
+func (uc *twoFactorUsecase) SetRoleRequirement(ctx context.Context, role domain.UserRole, req *domain.SetRoleTwoFactorRequest) (*domain.RoleTwoFactor, error) {
+	row, err := uc.store.SetRoleTwoFactor(ctx, repository.SetRoleTwoFactorParams{
+		Code:              string(role),
+		RequiresTwoFactor: *req.Required,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("unknown role %s", role)
+	}
+	if err != nil {
+		return nil, err
+	}
+	res := toDomainRoleTwoFactor(row)
+	return &res, nil
+}
+
+// checkCode verifies an authenticator or recovery code of a user with 2FA on.
+func (uc *twoFactorUsecase) checkCode(ctx context.Context, userID pgtype.UUID, code string) error {
+	profile, err := uc.store.GetProfile(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("profile not found")
+	}
+	row, err := uc.store.GetUserTotp(ctx, userID)
+	if err != nil || !row.ConfirmedAt.Valid {
+		return errTwoFactorDisabled
+	}
+
+	wait, locked, err := uc.loginLimiter.Allow(ctx, "", profile.Email.String)
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		return &domain.LoginThrottledError{RetryAfter: wait, Locked: locked}
+	}
+	used, err := verifySecondFactor(ctx, uc.store, uc.totp, row, code)
+	if err != nil {
+		return err
+	}
+	if used == "" {
+		if _, err := uc.loginLimiter.Fail(ctx, profile.Email.String); err != nil {
+			return err
+		}
+		return errInvalidTwoFactorCode
+	}
+	return nil
+}
+
+const recoveryCodeCount = 10
+
+var (
+	errInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	errTwoFactorDisabled    = errors.New("two-factor authentication is not enabled")
+)
+
+// usesTwoFactor reports whether a user has 2FA on or holds a role that
+// requires it.
+func usesTwoFactor(ctx context.Context, q repository.Querier, userID pgtype.UUID) (bool, error) {
+	row, err := q.GetUserTotp(ctx, userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return false, err
+	}
+	if err == nil && row.ConfirmedAt.Valid {
+		return true, nil
+	}
+	return q.UserRequiresTwoFactor(ctx, userID)
+}
+
+// enrollTOTP stores a new secret that is pending until confirmTOTP.
+func enrollTOTP(ctx context.Context, q repository.Querier, totp *util.TOTP, userID pgtype.UUID, account string) (*domain.TOTPEnrollment, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	_, err = q.CreateUserTotp(ctx, repository.CreateUserTotpParams{
+		UserID: userID,
+		Secret: secret,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	otpauthURL := totp.URL(secret, account)
+	code, err := qrcode.Encode(otpauthURL, qrcode.L)
+	if err != nil {
+		return nil, err
+	}
+	png, err := code.PNG(4)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TOTPEnrollment{
+		Secret:     secret,
+		OtpauthURL: otpauthURL,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}, nil
+}
+
+// confirmTOTP switches 2FA on when the code matches the pending secret, and
+// returns the recovery codes.
+func confirmTOTP(ctx context.Context, store repository.Repository, totp *util.TOTP, userID pgtype.UUID, code string) ([]string, error) {
+	row, err := store.GetUserTotp(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("start two-factor enrolment first")
+	}
+	if row.ConfirmedAt.Valid {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+	step, ok := totp.Validate(row.Secret, code, row.LastUsedStep)
+	if !ok {
+		return nil, errInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = store.ExecTx(ctx, func(q *repository.Queries) error {
+		err := q.ConfirmUserTotp(ctx, repository.ConfirmUserTotpParams{
+			UserID:       userID,
+			LastUsedStep: step,
+		})
+		if err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(ctx, q, userID, hashes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// verifySecondFactor checks a code against a confirmed authenticator, then
+// against the unused recovery codes. It returns "totp" or "recovery_code" for
+// the one that matched, "" when neither did. Both can only be used once.
+func verifySecondFactor(ctx context.Context, q repository.Querier, totp *util.TOTP, row repository.UserTotp, code string) (string, error) {
+	if step, ok := totp.Validate(row.Secret, code, row.LastUsedStep); ok {
+		n, err := q.UseTotpStep(ctx, repository.UseTotpStepParams{
+			UserID:       row.UserID,
+			LastUsedStep: step,
+		})
+		if err != nil || n == 0 {
+			return "", err
+		}
+		return "totp", nil
+	}
+
+	n, err := q.UseRecoveryCode(ctx, repository.UseRecoveryCodeParams{
+		UserID:   row.UserID,
+		CodeHash: hashRecoveryCode(code),
+	})
+	if err != nil || n == 0 {
+		return "", err
+	}
+	return "recovery_code", nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, q *repository.Queries, userID pgtype.UUID, hashes []string) error {
+	if err := q.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return err
+	}
+	return q.CreateRecoveryCodes(ctx, repository.CreateRecoveryCodesParams{
+		UserID:     userID,
+		CodeHashes: hashes,
+	})
+}
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newRecoveryCodes returns recovery codes like "k7m2q-x9d4a" and their hashes.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		s := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))[:10]
+		code := s[:5] + "-" + s[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode ignores case, spaces and dashes, which people get wrong
+// when typing the code.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	return hashOpaqueToken(code)
+}
+
+func toDomainRoleTwoFactor(row repository.Role) domain.RoleTwoFactor {
+	return domain.RoleTwoFactor{
+		Role:     domain.UserRole(row.Code),
+		Name:     row.Name,
+		Required: row.RequiresTwoFactor,
+	}
+}
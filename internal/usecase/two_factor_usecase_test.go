@@ -0,0 +1,328 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+	"pos-api/internal/util"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	testSecret   = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" // "12345678901234567890"
+	testEmail    = "owner@example.com"
+	testPassword = "correct horse"
+	testNow      = 1111111111
+)
+
+// fakeTwoFactorStore keeps one user with a confirmed authenticator in memory.
+// Queries the tests do not need are left to the nil Querier and panic.
+type fakeTwoFactorStore struct {
+	repository.Querier
+
+	authUser      repository.AuthUser
+	profile       repository.Profile
+	totp          repository.UserTotp
+	recoveryCodes map[string]bool // hash -> used
+	refreshTokens int
+	audit         []string
+}
+
+func newFakeTwoFactorStore(t *testing.T) *fakeTwoFactorStore {
+	t.Helper()
+	hash, err := util.HashPassword(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	return &fakeTwoFactorStore{
+		authUser: repository.AuthUser{ID: id, Email: testEmail, EncryptedPassword: hash},
+		profile: repository.Profile{
+			ID:       id,
+			Email:    pgtype.Text{String: testEmail, Valid: true},
+			Role:     string(domain.RoleStoreOwner),
+			IsActive: true,
+		},
+		totp: repository.UserTotp{
+			UserID:      id,
+			Secret:      testSecret,
+			ConfirmedAt: pgtype.Timestamptz{Time: time.Unix(testNow, 0), Valid: true},
+		},
+		recoveryCodes: map[string]bool{},
+	}
+}
+
+func (s *fakeTwoFactorStore) ExecTx(ctx context.Context, fn func(*repository.Queries) error) error {
+	return errors.New("transactions are not faked")
+}
+
+func (s *fakeTwoFactorStore) GetAuthUserByEmail(ctx context.Context, email string) (repository.AuthUser, error) {
+	if email != s.authUser.Email {
+		return repository.AuthUser{}, pgx.ErrNoRows
+	}
+	return s.authUser, nil
+}
+
+func (s *fakeTwoFactorStore) GetAuthUser(ctx context.Context, id pgtype.UUID) (repository.AuthUser, error) {
+	return s.authUser, nil
+}
+
+func (s *fakeTwoFactorStore) GetProfile(ctx context.Context, id pgtype.UUID) (repository.Profile, error) {
+	return s.profile, nil
+}
+
+func (s *fakeTwoFactorStore) GetUserRoles(ctx context.Context, userID pgtype.UUID) ([]repository.GetUserRolesRow, error) {
+	return []repository.GetUserRolesRow{{RoleCode: s.profile.Role}}, nil
+}
+
+func (s *fakeTwoFactorStore) GetUserTotp(ctx context.Context, userID pgtype.UUID) (repository.UserTotp, error) {
+	return s.totp, nil
+}
+
+func (s *fakeTwoFactorStore) UserRequiresTwoFactor(ctx context.Context, userID pgtype.UUID) (bool, error) {
+	return false, nil
+}
+
+func (s *fakeTwoFactorStore) UseTotpStep(ctx context.Context, arg repository.UseTotpStepParams) (int64, error) {
+	if s.totp.LastUsedStep >= arg.LastUsedStep {
+		return 0, nil
+	}
+	s.totp.LastUsedStep = arg.LastUsedStep
+	return 1, nil
+}
+
+func (s *fakeTwoFactorStore) UseRecoveryCode(ctx context.Context, arg repository.UseRecoveryCodeParams) (int64, error) {
+	used, ok := s.recoveryCodes[arg.CodeHash]
+	if !ok || used {
+		return 0, nil
+	}
+	s.recoveryCodes[arg.CodeHash] = true
+	return 1, nil
+}
+
+func (s *fakeTwoFactorStore) CreateRefreshToken(ctx context.Context, arg repository.CreateRefreshTokenParams) (repository.RefreshToken, error) {
+	s.refreshTokens++
+	return repository.RefreshToken{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}}, nil
+}
+
+func (s *fakeTwoFactorStore) CreateAuditLog(ctx context.Context, arg repository.CreateAuditLogParams) (repository.AuditLog, error) {
+	s.audit = append(s.audit, arg.Action)
+	return repository.AuditLog{}, nil
+}
+
+type fakeLoginLimiter struct {
+	failures int
+}
+
+func (l *fakeLoginLimiter) Allow(ctx context.Context, ip, email string) (time.Duration, bool, error) {
+	return 0, false, nil
+}
+
+func (l *fakeLoginLimiter) Fail(ctx context.Context, email string) (time.Duration, error) {
+	l.failures++
+	return 0, nil
+}
+
+func (l *fakeLoginLimiter) Succeed(ctx context.Context, email string) error {
+	l.failures = 0
+	return nil
+}
+
+func (l *fakeLoginLimiter) Unlock(ctx context.Context, email string) error {
+	l.failures = 0
+	return nil
+}
+
+type fakeChallengeStore map[string]util.LoginChallenge
+
+func (s fakeChallengeStore) Save(ctx context.Context, tokenHash string, challenge util.LoginChallenge, ttl time.Duration) error {
+	s[tokenHash] = challenge
+	return nil
+}
+
+func (s fakeChallengeStore) Get(ctx context.Context, tokenHash string) (*util.LoginChallenge, error) {
+	challenge, ok := s[tokenHash]
+	if !ok {
+		return nil, nil
+	}
+	return &challenge, nil
+}
+
+func (s fakeChallengeStore) Delete(ctx context.Context, tokenHash string) error {
+	delete(s, tokenHash)
+	return nil
+}
+
+func testTOTP() *util.TOTP {
+	return &util.TOTP{Issuer: "POS", Skew: 1, Now: func() time.Time { return time.Unix(testNow, 0) }}
+}
+
+func currentCode(t *testing.T, totp *util.TOTP) string {
+	t.Helper()
+	code, err := totp.Code(testSecret, totp.Step())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func TestVerifySecondFactorTOTPOnce(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeTwoFactorStore(t)
+	totp := testTOTP()
+	code := currentCode(t, totp)
+
+	used, err := verifySecondFactor(ctx, store, totp, store.totp, code)
+	if err != nil || used != "totp" {
+		t.Fatalf("first use = %q, %v; want totp", used, err)
+	}
+	used, err = verifySecondFactor(ctx, store, totp, store.totp, code)
+	if err != nil || used != "" {
+		t.Fatalf("replay = %q, %v; want refused", used, err)
+	}
+}
+
+func TestVerifySecondFactorRecoveryCodeOnce(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeTwoFactorStore(t)
+	totp := testTOTP()
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != recoveryCodeCount {
+		t.Fatalf("got %d recovery codes, want %d", len(codes), recoveryCodeCount)
+	}
+	for _, hash := range hashes {
+		store.recoveryCodes[hash] = false
+	}
+
+	// Typed in upper case and without the dash, as people do
+	typed := strings.ToUpper(codes[0][:5] + codes[0][6:])
+	used, err := verifySecondFactor(ctx, store, totp, store.totp, " "+typed+" ")
+	if err != nil || used != "recovery_code" {
+		t.Fatalf("first use = %q, %v; want recovery_code", used, err)
+	}
+	used, err = verifySecondFactor(ctx, store, totp, store.totp, codes[0])
+	if err != nil || used != "" {
+		t.Fatalf("second use = %q, %v; want refused", used, err)
+	}
+	used, err = verifySecondFactor(ctx, store, totp, store.totp, codes[1])
+	if err != nil || used != "recovery_code" {
+		t.Fatalf("other code = %q, %v; want recovery_code", used, err)
+	}
+}
+
+func newTestAuthUsecase(t *testing.T, store *fakeTwoFactorStore, limiter *fakeLoginLimiter) *authUsecase {
+	t.Helper()
+	key, err := util.GenerateSigningKey(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenMaker, err := util.NewJWTMaker([]*util.SigningKey{key}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &authUsecase{
+		store:        store,
+		tokenMaker:   tokenMaker,
+		loginLimiter: limiter,
+		challenges:   fakeChallengeStore{},
+		totp:         testTOTP(),
+		config: AuthConfig{
+			AccessTokenDuration:  time.Minute,
+			RefreshTokenDuration: time.Hour,
+			ChallengeDuration:    5 * time.Minute,
+		},
+	}
+}
+
+func TestLoginTwoFactorChallenge(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeTwoFactorStore(t)
+	limiter := &fakeLoginLimiter{}
+	uc := newTestAuthUsecase(t, store, limiter)
+
+	// The password alone only starts the challenge
+	res, err := uc.Login(ctx, &domain.LoginRequest{Email: testEmail, Password: testPassword})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TwoFactor == nil || res.TwoFactor.ChallengeToken == "" {
+		t.Fatal("login with 2FA on returned no challenge")
+	}
+	if res.AccessToken != "" || store.refreshTokens != 0 {
+		t.Fatal("tokens issued before the second factor")
+	}
+	if res.TwoFactor.EnrollmentRequired {
+		t.Error("enrolment asked of a user with a confirmed authenticator")
+	}
+	challenge := res.TwoFactor.ChallengeToken
+
+	// A wrong code counts as a failed login and keeps the challenge
+	_, err = uc.LoginTwoFactor(ctx, &domain.TwoFactorLoginRequest{ChallengeToken: challenge, Code: "000000"})
+	if !errors.Is(err, errInvalidTwoFactorCode) {
+		t.Fatalf("wrong code: err = %v, want %v", err, errInvalidTwoFactorCode)
+	}
+	if limiter.failures != 1 {
+		t.Errorf("failures = %d, want 1", limiter.failures)
+	}
+
+	res, err = uc.LoginTwoFactor(ctx, &domain.TwoFactorLoginRequest{ChallengeToken: challenge, Code: currentCode(t, uc.totp)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AccessToken == "" || res.RefreshToken == "" || store.refreshTokens != 1 {
+		t.Fatal("no tokens after the second factor")
+	}
+	if limiter.failures != 0 {
+		t.Error("failures not forgotten after the login")
+	}
+
+	// The challenge is gone once completed
+	_, err = uc.LoginTwoFactor(ctx, &domain.TwoFactorLoginRequest{ChallengeToken: challenge, Code: currentCode(t, uc.totp)})
+	if !errors.Is(err, errInvalidChallenge) {
+		t.Fatalf("reused challenge: err = %v, want %v", err, errInvalidChallenge)
+	}
+}
+
+func TestLoginTwoFactorRejectsReplayedCode(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeTwoFactorStore(t)
+	uc := newTestAuthUsecase(t, store, &fakeLoginLimiter{})
+	code := currentCode(t, uc.totp)
+
+	for i, wantErr := range []error{nil, errInvalidTwoFactorCode} {
+		res, err := uc.Login(ctx, &domain.LoginRequest{Email: testEmail, Password: testPassword})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = uc.LoginTwoFactor(ctx, &domain.TwoFactorLoginRequest{ChallengeToken: res.TwoFactor.ChallengeToken, Code: code})
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("login %d: err = %v, want %v", i+1, err, wantErr)
+		}
+	}
+}
+
+func TestRefuseTwoFactorPin(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeTwoFactorStore(t)
+	if err := refuseTwoFactorPin(ctx, store, store.authUser.ID); !errors.Is(err, errPinTwoFactor) {
+		t.Fatalf("confirmed 2FA: err = %v, want %v", err, errPinTwoFactor)
+	}
+
+	// A pending enrolment does not count yet
+	store.totp.ConfirmedAt = pgtype.Timestamptz{}
+	if err := refuseTwoFactorPin(ctx, store, store.authUser.ID); err != nil {
+		t.Fatalf("without 2FA: err = %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// userRoles is what user_roles says about a user, ready to put in a token.
+type userRoles struct {
+	assignments []repository.GetUserRolesRow
+	roles       []string
+	storeRoles  map[uuid.UUID][]string
+}
+
+// loadUserRoles reads the role assignments of a user. roles holds the global
+// roles and those limited to homeStore; storeRoles holds, for every other
+// store with roles of its own, those plus SUPER_ADMIN. Other global roles
+// only apply in the user's own store, so owning one store does not make a
+// user the owner of another store they merely work in.
+func loadUserRoles(ctx context.Context, q repository.Querier, userID, homeStore pgtype.UUID) (*userRoles, error) {
+	rows, err := q.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var global, everywhere []string
+	scoped := make(map[uuid.UUID][]string)
+	for _, row := range rows {
+		if !row.StoreID.Valid {
+			global = append(global, row.RoleCode)
+			if row.RoleCode == string(domain.RoleSuperAdmin) {
+				everywhere = append(everywhere, row.RoleCode)
+			}
+			continue
+		}
+		storeID := uuid.UUID(row.StoreID.Bytes)
+		scoped[storeID] = append(scoped[storeID], row.RoleCode)
+	}
+
+	res := &userRoles{assignments: rows, roles: global}
+	if homeStore.Valid {
+		home := uuid.UUID(homeStore.Bytes)
+		res.roles = mergeRoles(global, scoped[home])
+		delete(scoped, home)
+	}
+	for storeID, codes := range scoped {
+		if res.storeRoles == nil {
+			res.storeRoles = make(map[uuid.UUID][]string, len(scoped))
+		}
+		res.storeRoles[storeID] = mergeRoles(everywhere, codes)
+	}
+	return res, nil
+}
+
+func (r *userRoles) toDomain() []domain.RoleAssignment {
+	assignments := make([]domain.RoleAssignment, 0, len(r.assignments))
+	for _, row := range r.assignments {
+		assignments = append(assignments, toDomainRoleAssignment(row))
+	}
+	return assignments
+}
+
+// hasOnlyStaffRoles reports whether every role assignment is a staff role.
+func hasOnlyStaffRoles(rows []repository.GetUserRolesRow) bool {
+	for _, row := range rows {
+		if !domain.UserRole(row.RoleCode).IsStaffRole() {
+			return false
+		}
+	}
+	return true
+}
+
+func mergeRoles(a, b []string) []string {
+	roles := slices.Concat(a, b)
+	slices.Sort(roles)
+	return slices.Compact(roles)
+}
+
+func toDomainRoleAssignment(row repository.GetUserRolesRow) domain.RoleAssignment {
+	return domain.RoleAssignment{
+		ID:         uuid.UUID(row.ID.Bytes),
+		Role:       domain.UserRole(row.RoleCode),
+		Name:       row.Name,
+		StoreID:    optionalUUID(row.StoreID),
+		AssignedAt: row.AssignedAt.Time,
+	}
+}
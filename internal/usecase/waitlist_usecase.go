@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type waitlistUsecase struct {
+	store          repository.Repository
+	sessionUsecase domain.SessionUsecase
+	eventSvc       domain.EventService
+	quotePerParty  time.Duration
+}
+
+// NewWaitlistUsecase quotes quotePerParty of waiting time for every party in
+// the queue, including the new one, unless staff quote a wait themselves.
+func NewWaitlistUsecase(store repository.Repository, sessionUsecase domain.SessionUsecase, eventSvc domain.EventService, quotePerParty time.Duration) domain.WaitlistUsecase {
+	return &waitlistUsecase{
+		store:          store,
+		sessionUsecase: sessionUsecase,
+		eventSvc:       eventSvc,
+		quotePerParty:  quotePerParty,
+	}
+}
+
+func (uc *waitlistUsecase) AddToWaitlist(ctx context.Context, req *domain.WaitlistRequest) (*domain.WaitlistEntry, error) {
+	if err := checkStore(ctx, req.StoreID); err != nil {
+		return nil, err
+	}
+
+	storeID := pgtype.UUID{Bytes: req.StoreID, Valid: true}
+
+	var quote int32
+	if req.QuotedWaitMinutes != nil {
+		quote = *req.QuotedWaitMinutes
+	} else {
+		ahead, err := uc.store.CountWaitingParties(ctx, storeID)
+		if err != nil {
+			return nil, err
+		}
+		quote = int32(time.Duration(ahead+1) * uc.quotePerParty / time.Minute)
+	}
+
+	entry, err := uc.store.CreateWaitlistEntry(ctx, repository.CreateWaitlistEntryParams{
+		StoreID:           storeID,
+		CustomerName:      req.CustomerName,
+		CustomerPhone:     pgtype.Text{String: req.CustomerPhone, Valid: req.CustomerPhone != ""},
+		PartySize:         req.PartySize,
+		QuotedWaitMinutes: quote,
+		Note:              pgtype.Text{String: req.Note, Valid: req.Note != ""},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.getEntry(ctx, uuid.UUID(entry.ID.Bytes))
+}
+
+func (uc *waitlistUsecase) ListWaitlist(ctx context.Context, storeID uuid.UUID) ([]domain.WaitlistEntry, error) {
+	if err := checkStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	rows, err := uc.store.ListWaitlist(ctx, pgtype.UUID{Bytes: storeID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.WaitlistEntry, 0, len(rows))
+	for i, row := range rows {
+		entry := toDomainWaitlistEntryRow(repository.GetWaitlistEntryRow(row))
+		entry.Position = i + 1
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// NotifyReady offers a free table to the party. The TABLE_READY event drives
+// the pager display or the message to the guest.
+func (uc *waitlistUsecase) NotifyReady(ctx context.Context, id uuid.UUID, req *domain.NotifyWaitlistRequest) (*domain.WaitlistEntry, error) {
+	current, err := uc.getEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !current.IsWaiting() {
+		return nil, fmt.Errorf("party is %s", current.Status)
+	}
+	if err := checkTableFits(ctx, uc.store, current.StoreID, req.TableID, current.PartySize); err != nil {
+		return nil, err
+	}
+	if _, err := uc.store.GetActiveSessionByTable(ctx, pgtype.UUID{Bytes: req.TableID, Valid: true}); err == nil {
+		return nil, fmt.Errorf("table is occupied")
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	if _, err := uc.store.NotifyWaitlistEntry(ctx, repository.NotifyWaitlistEntryParams{
+		ID:      pgtype.UUID{Bytes: id, Valid: true},
+		TableID: pgtype.UUID{Bytes: req.TableID, Valid: true},
+	}); err != nil {
+		return nil, fmt.Errorf("party is no longer waiting")
+	}
+
+	entry, err := uc.getEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_ = uc.eventSvc.PublishEvent(ctx, "TABLE_READY", entry)
+	return entry, nil
+}
+
+func (uc *waitlistUsecase) SeatEntry(ctx context.Context, id uuid.UUID, req *domain.SeatRequest) (*domain.WaitlistEntry, error) {
+	current, err := uc.getEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !current.IsWaiting() {
+		return nil, fmt.Errorf("party is %s", current.Status)
+	}
+
+	tableID := current.TableID
+	if req.TableID != nil {
+		tableID = req.TableID
+	}
+	if tableID == nil {
+		return nil, fmt.Errorf("party has no table; pick one")
+	}
+	if err := checkTableFits(ctx, uc.store, current.StoreID, *tableID, current.PartySize); err != nil {
+		return nil, err
+	}
+
+	// The entry is seated in the same transaction as the session
+	if _, err := uc.sessionUsecase.SeatTable(ctx, *tableID, domain.SeatedParty{WaitlistEntryID: &id}); err != nil {
+		return nil, err
+	}
+
+	return uc.getEntry(ctx, id)
+}
+
+func (uc *waitlistUsecase) CancelEntry(ctx context.Context, id uuid.UUID) (*domain.WaitlistEntry, error) {
+	if _, err := uc.getEntry(ctx, id); err != nil {
+		return nil, err
+	}
+	if _, err := uc.store.CancelWaitlistEntry(ctx, pgtype.UUID{Bytes: id, Valid: true}); err != nil {
+		return nil, fmt.Errorf("party not found or no longer waiting")
+	}
+	return uc.getEntry(ctx, id)
+}
+
+func (uc *waitlistUsecase) getEntry(ctx context.Context, id uuid.UUID) (*domain.WaitlistEntry, error) {
+	row, err := uc.store.GetWaitlistEntry(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("waitlist entry not found")
+	}
+	if err := checkStoreID(ctx, row.StoreID); err != nil {
+		return nil, err
+	}
+	entry := toDomainWaitlistEntryRow(row)
+	return &entry, nil
+}
+
+func toDomainWaitlistEntryRow(row repository.GetWaitlistEntryRow) domain.WaitlistEntry {
+	return domain.WaitlistEntry{
+		ID:                uuid.UUID(row.ID.Bytes),
+		StoreID:           uuid.UUID(row.StoreID.Bytes),
+		CustomerName:      row.CustomerName,
+		CustomerPhone:     row.CustomerPhone.String,
+		PartySize:         row.PartySize,
+		QuotedWaitMinutes: row.QuotedWaitMinutes,
+		Status:            domain.WaitlistStatus(row.Status),
+		Note:              row.Note.String,
+		TableID:           optionalUUID(row.TableID),
+		TableName:         row.TableName.String,
+		TableSessionID:    optionalUUID(row.TableSessionID),
+		NotifiedAt:        optionalTime(row.NotifiedAt),
+		SeatedAt:          optionalTime(row.SeatedAt),
+		CreatedAt:         row.CreatedAt.Time,
+	}
+}
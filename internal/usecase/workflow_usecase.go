@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var workflowStatusPattern = regexp.MustCompile(`^[A-Z][A-Z_]{1,49}$`)
+
+type workflowUsecase struct {
+	store repository.Repository
+}
+
+func NewWorkflowUsecase(store repository.Repository) domain.WorkflowUsecase {
+	return &workflowUsecase{
+		store: store,
+	}
+}
+
+func (uc *workflowUsecase) GetWorkflow(ctx context.Context, storeID uuid.UUID, orderType string) (*domain.Workflow, error) {
+	if err := checkStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+	return loadWorkflow(ctx, uc.store, pgtype.UUID{Bytes: storeID, Valid: true}, orderType)
+}
+
+func (uc *workflowUsecase) SetWorkflow(ctx context.Context, req *domain.SetWorkflowRequest) (*domain.Workflow, error) {
+	if err := checkStore(ctx, req.StoreID); err != nil {
+		return nil, err
+	}
+
+	if err := validateWorkflow(req.OrderType, req.Transitions); err != nil {
+		return nil, err
+	}
+
+	storeID := pgtype.UUID{Bytes: req.StoreID, Valid: true}
+	err := uc.store.ExecTx(ctx, func(q *repository.Queries) error {
+		err := q.DeleteWorkflowTransitions(ctx, repository.DeleteWorkflowTransitionsParams{
+			StoreID:   storeID,
+			OrderType: req.OrderType,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, t := range req.Transitions {
+			roles := make([]string, 0, len(t.AllowedRoles))
+			for _, r := range t.AllowedRoles {
+				roles = append(roles, string(r))
+			}
+			effects := make([]string, 0, len(t.SideEffects))
+			for _, e := range t.SideEffects {
+				effects = append(effects, string(e))
+			}
+
+			_, err := q.CreateWorkflowTransition(ctx, repository.CreateWorkflowTransitionParams{
+				StoreID:      storeID,
+				OrderType:    req.OrderType,
+				FromStatus:   string(t.From),
+				ToStatus:     string(t.To),
+				AllowedRoles: roles,
+				SideEffects:  effects,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.GetWorkflow(ctx, req.StoreID, req.OrderType)
+}
+
+func validateWorkflow(orderType string, transitions []domain.WorkflowTransition) error {
+	if orderType != "" && !domain.OrderType(orderType).IsValid() {
+		return fmt.Errorf("unknown order type %s", orderType)
+	}
+	// No transitions reverts to the default graph
+	if len(transitions) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	fromNew := false
+
+	for _, t := range transitions {
+		if !workflowStatusPattern.MatchString(string(t.From)) || !workflowStatusPattern.MatchString(string(t.To)) {
+			return fmt.Errorf("invalid status in transition %s -> %s", t.From, t.To)
+		}
+		if t.From == t.To {
+			return fmt.Errorf("transition %s -> %s does not change the status", t.From, t.To)
+		}
+		if t.From == domain.OrderStatusDone || t.From == domain.OrderStatusVoided {
+			return fmt.Errorf("%s is a terminal status and cannot have outgoing transitions", t.From)
+		}
+
+		key := string(t.From) + "->" + string(t.To)
+		if seen[key] {
+			return fmt.Errorf("duplicate transition %s -> %s", t.From, t.To)
+		}
+		seen[key] = true
+
+		for _, r := range t.AllowedRoles {
+			switch r {
+			case domain.RoleSuperAdmin, domain.RoleStoreOwner, domain.RoleKasir, domain.RoleKitchen, domain.RoleStaff:
+			default:
+				return fmt.Errorf("unknown role %s", r)
+			}
+		}
+		for _, e := range t.SideEffects {
+			if e != domain.SideEffectNotify && e != domain.SideEffectDeductStock {
+				return fmt.Errorf("unknown side effect %s", e)
+			}
+		}
+
+		if t.From == domain.OrderStatusNew {
+			fromNew = true
+		}
+	}
+
+	if !fromNew {
+		return fmt.Errorf("workflow needs at least one transition out of %s", domain.OrderStatusNew)
+	}
+	return nil
+}
+
+// loadWorkflow returns the graph configured for the store and order type,
+// falling back to the store-wide graph and then to the default one.
+func loadWorkflow(ctx context.Context, q repository.Querier, storeID pgtype.UUID, orderType string) (*domain.Workflow, error) {
+	rows, err := q.ListWorkflowTransitions(ctx, repository.ListWorkflowTransitionsParams{
+		StoreID:   storeID,
+		OrderType: orderType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return defaultOrderWorkflow(uuid.UUID(storeID.Bytes), orderType), nil
+	}
+
+	// Rows are sorted type specific first; only use one level
+	level := rows[0].OrderType
+	w := &domain.Workflow{
+		StoreID:   uuid.UUID(storeID.Bytes),
+		OrderType: level,
+	}
+	for _, row := range rows {
+		if row.OrderType != level {
+			break
+		}
+
+		t := domain.WorkflowTransition{
+			From: domain.OrderStatus(row.FromStatus),
+			To:   domain.OrderStatus(row.ToStatus),
+		}
+		for _, r := range row.AllowedRoles {
+			t.AllowedRoles = append(t.AllowedRoles, domain.UserRole(r))
+		}
+		for _, e := range row.SideEffects {
+			t.SideEffects = append(t.SideEffects, domain.WorkflowSideEffect(e))
+		}
+		w.Transitions = append(w.Transitions, t)
+	}
+	return w, nil
+}
+
+// applyStockDeduction books a SALE stock movement for every item of the order.
+// It runs at most once per order, whichever transition triggers it first.
+func applyStockDeduction(ctx context.Context, q *repository.Queries, orderID pgtype.UUID) error {
+	done, err := q.CountStockMovementsByReference(ctx, repository.CountStockMovementsByReferenceParams{
+		ReferenceID: orderID,
+		Type:        "SALE",
+	})
+	if err != nil {
+		return err
+	}
+	if done > 0 {
+		return nil
+	}
+
+	items, err := q.ListOrderItems(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if _, err := q.UpdateProductStock(ctx, repository.UpdateProductStockParams{
+			ID:    item.ProductID,
+			Stock: -item.Quantity,
+		}); err != nil {
+			return fmt.Errorf("failed to deduct stock for %s: %w", item.ProductName, err)
+		}
+
+		_, err := q.CreateStockMovement(ctx, repository.CreateStockMovementParams{
+			ProductID:   item.ProductID,
+			Quantity:    -item.Quantity,
+			Type:        "SALE",
+			ReferenceID: orderID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AttemptLimiter counts failed attempts per key. A key that fails limit times
+// within the window is blocked until the window ends.
+type AttemptLimiter interface {
+	// Blocked returns how long the key stays blocked, 0 when it is not.
+	Blocked(ctx context.Context, key string) (time.Duration, error)
+	Fail(ctx context.Context, key string) error
+	Reset(ctx context.Context, key string) error
+}
+
+const attemptsKeyPrefix = "auth:attempts:"
+
+type RedisAttemptLimiter struct {
+	client *redis.Client
+	limit  int64
+	window time.Duration
+}
+
+func NewRedisAttemptLimiter(client *redis.Client, limit int64, window time.Duration) AttemptLimiter {
+	return &RedisAttemptLimiter{client: client, limit: limit, window: window}
+}
+
+func (l *RedisAttemptLimiter) Blocked(ctx context.Context, key string) (time.Duration, error) {
+	count, err := l.client.Get(ctx, attemptsKeyPrefix+key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if count < l.limit {
+		return 0, nil
+	}
+	ttl, err := l.client.TTL(ctx, attemptsKeyPrefix+key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl <= 0 {
+		ttl = l.window
+	}
+	return ttl, nil
+}
+
+// Fail counts an attempt. The window starts with the first failure.
+func (l *RedisAttemptLimiter) Fail(ctx context.Context, key string) error {
+	pipe := l.client.TxPipeline()
+	pipe.Incr(ctx, attemptsKeyPrefix+key)
+	pipe.ExpireNX(ctx, attemptsKeyPrefix+key, l.window)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (l *RedisAttemptLimiter) Reset(ctx context.Context, key string) error {
+	return l.client.Del(ctx, attemptsKeyPrefix+key).Err()
+}
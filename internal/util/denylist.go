@@ -0,0 +1,94 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"pos-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// TokenDenylist holds revoked access tokens by jti until they expire on their
+// own.
+type TokenDenylist interface {
+	Deny(ctx context.Context, jti string, expiresAt time.Time) error
+	IsDenied(ctx context.Context, jti string) (bool, error)
+	// DenyUser rejects every token of the user issued until now. ttl is the
+	// longest access token lifetime.
+	DenyUser(ctx context.Context, userID uuid.UUID, ttl time.Duration) error
+	IsUserDenied(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (bool, error)
+}
+
+const denylistKeyPrefix = "auth:denylist:"
+
+// DeviceDenylistKey is the entry that revokes every token issued on a POS
+// device.
+func DeviceDenylistKey(deviceID uuid.UUID) string {
+	return "device:" + deviceID.String()
+}
+
+type RedisDenylist struct {
+	client *redis.Client
+}
+
+func NewRedisDenylist(client *redis.Client) TokenDenylist {
+	return &RedisDenylist{client: client}
+}
+
+func (d *RedisDenylist) Deny(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if jti == "" || ttl <= 0 {
+		// Already expired, nothing to deny
+		return nil
+	}
+	return d.client.Set(ctx, denylistKeyPrefix+jti, 1, ttl).Err()
+}
+
+func (d *RedisDenylist) IsDenied(ctx context.Context, jti string) (bool, error) {
+	err := d.client.Get(ctx, denylistKeyPrefix+jti).Err()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (d *RedisDenylist) DenyUser(ctx context.Context, userID uuid.UUID, ttl time.Duration) error {
+	return d.client.Set(ctx, denylistKeyPrefix+"user:"+userID.String(), time.Now().Unix(), ttl).Err()
+}
+
+func (d *RedisDenylist) IsUserDenied(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	deniedUntil, err := d.client.Get(ctx, denylistKeyPrefix+"user:"+userID.String()).Int64()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	// iat has second precision; a token from the same second is denied too
+	return issuedAt.Unix() <= deniedUntil, nil
+}
+
+// IsTokenDenied checks the jti of the token, the tokens of its user and, for
+// POS device tokens, whether the device was revoked.
+func IsTokenDenied(ctx context.Context, denylist TokenDenylist, claims *domain.JwtCustomClaims) (bool, error) {
+	denied, err := denylist.IsDenied(ctx, claims.ID)
+	if err != nil || denied {
+		return denied, err
+	}
+	if claims.IssuedAt != nil {
+		denied, err = denylist.IsUserDenied(ctx, claims.UserID, claims.IssuedAt.Time)
+		if err != nil || denied {
+			return denied, err
+		}
+	}
+	if claims.DeviceID == nil {
+		return false, nil
+	}
+	return denylist.IsDenied(ctx, DeviceDenylistKey(*claims.DeviceID))
+}
@@ -0,0 +1,65 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// LoginChallenge is a password login that waits for its second factor.
+type LoginChallenge struct {
+	UserID          uuid.UUID `json:"user_id"`
+	Email           string    `json:"email"`
+	DeviceName      string    `json:"device_name,omitempty"`
+	AuthenticatedAt time.Time `json:"authenticated_at"` // When the password was checked
+}
+
+// LoginChallengeStore keeps login challenges by the hash of their token until
+// they are completed or expire.
+type LoginChallengeStore interface {
+	Save(ctx context.Context, tokenHash string, challenge LoginChallenge, ttl time.Duration) error
+	// Get returns nil when the challenge does not exist or expired.
+	Get(ctx context.Context, tokenHash string) (*LoginChallenge, error)
+	Delete(ctx context.Context, tokenHash string) error
+}
+
+const challengeKeyPrefix = "auth:challenge:"
+
+type RedisLoginChallengeStore struct {
+	client *redis.Client
+}
+
+func NewRedisLoginChallengeStore(client *redis.Client) LoginChallengeStore {
+	return &RedisLoginChallengeStore{client: client}
+}
+
+func (s *RedisLoginChallengeStore) Save(ctx context.Context, tokenHash string, challenge LoginChallenge, ttl time.Duration) error {
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, challengeKeyPrefix+tokenHash, data, ttl).Err()
+}
+
+func (s *RedisLoginChallengeStore) Get(ctx context.Context, tokenHash string) (*LoginChallenge, error) {
+	data, err := s.client.Get(ctx, challengeKeyPrefix+tokenHash).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var challenge LoginChallenge
+	if err := json.Unmarshal(data, &challenge); err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+func (s *RedisLoginChallengeStore) Delete(ctx context.Context, tokenHash string) error {
+	return s.client.Del(ctx, challengeKeyPrefix+tokenHash).Err()
+}
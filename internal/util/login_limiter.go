@@ -0,0 +1,168 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// LoginLimits configures LoginLimiter. Attempts and failures are counted over
+// sliding windows, so there is no reset at a fixed time an attacker can wait
+// for.
+type LoginLimits struct {
+	IPAttempts      int64 // Password logins per IP within IPWindow
+	IPWindow        time.Duration
+	FailureWindow   time.Duration // Wrong passwords per email count over this window
+	FreeFailures    int64         // Failures before delays start
+	BaseDelay       time.Duration // Doubles with every further failure
+	MaxDelay        time.Duration
+	LockoutFailures int64 // Failures within FailureWindow that lock the account
+	LockoutDuration time.Duration
+}
+
+// LoginLimiter throttles password logins per client IP and per email. Emails
+// are counted whether or not an account exists, so the responses do not tell
+// which emails are registered.
+type LoginLimiter interface {
+	// Allow counts an attempt and returns how long the caller has to wait
+	// before trying again, 0 when the attempt may go ahead. locked is set
+	// while the account is locked out.
+	Allow(ctx context.Context, ip, email string) (wait time.Duration, locked bool, err error)
+	// Fail records a wrong password and returns how long it locked the
+	// account for, 0 when it did not.
+	Fail(ctx context.Context, email string) (time.Duration, error)
+	// Succeed forgets the failures of the email.
+	Succeed(ctx context.Context, email string) error
+	// Unlock lifts a lockout and forgets the failures of the email.
+	Unlock(ctx context.Context, email string) error
+}
+
+const loginKeyPrefix = "auth:login:"
+
+type RedisLoginLimiter struct {
+	client *redis.Client
+	limits LoginLimits
+}
+
+func NewRedisLoginLimiter(client *redis.Client, limits LoginLimits) LoginLimiter {
+	return &RedisLoginLimiter{client: client, limits: limits}
+}
+
+func (l *RedisLoginLimiter) Allow(ctx context.Context, ip, email string) (time.Duration, bool, error) {
+	now := time.Now()
+
+	// Every attempt of the IP counts, including ones blocked for the email
+	if ip != "" {
+		key := loginKeyPrefix + "ip:" + ip
+		count, oldest, _, err := l.window(ctx, key, now, l.limits.IPWindow)
+		if err != nil {
+			return 0, false, err
+		}
+		if count >= l.limits.IPAttempts {
+			return retryAfter(oldest.Add(l.limits.IPWindow), now), false, nil
+		}
+		if err := l.add(ctx, key, now, l.limits.IPWindow); err != nil {
+			return 0, false, err
+		}
+	}
+
+	email = loginEmailKey(email)
+	ttl, err := l.client.PTTL(ctx, loginKeyPrefix+"lock:"+email).Result()
+	if err != nil {
+		return 0, false, err
+	}
+	if ttl > 0 {
+		return retryAfter(now.Add(ttl), now), true, nil
+	}
+
+	count, _, latest, err := l.window(ctx, loginKeyPrefix+"fail:"+email, now, l.limits.FailureWindow)
+	if err != nil {
+		return 0, false, err
+	}
+	if count < l.limits.FreeFailures {
+		return 0, false, nil
+	}
+	// Progressive delay after the latest failure: BaseDelay, 2x, 4x, ...
+	delay := min(l.limits.BaseDelay<<min(count-l.limits.FreeFailures, 16), l.limits.MaxDelay)
+	if next := latest.Add(delay); next.After(now) {
+		return retryAfter(next, now), false, nil
+	}
+	return 0, false, nil
+}
+
+func (l *RedisLoginLimiter) Fail(ctx context.Context, email string) (time.Duration, error) {
+	email = loginEmailKey(email)
+	key := loginKeyPrefix + "fail:" + email
+	now := time.Now()
+	if err := l.add(ctx, key, now, l.limits.FailureWindow); err != nil {
+		return 0, err
+	}
+	count, _, _, err := l.window(ctx, key, now, l.limits.FailureWindow)
+	if err != nil {
+		return 0, err
+	}
+	if count < l.limits.LockoutFailures {
+		return 0, nil
+	}
+
+	// Start over with a clean slate once the lockout ends
+	pipe := l.client.TxPipeline()
+	pipe.Set(ctx, loginKeyPrefix+"lock:"+email, now.Unix(), l.limits.LockoutDuration)
+	pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return l.limits.LockoutDuration, nil
+}
+
+func (l *RedisLoginLimiter) Succeed(ctx context.Context, email string) error {
+	return l.client.Del(ctx, loginKeyPrefix+"fail:"+loginEmailKey(email)).Err()
+}
+
+func (l *RedisLoginLimiter) Unlock(ctx context.Context, email string) error {
+	email = loginEmailKey(email)
+	return l.client.Del(ctx, loginKeyPrefix+"lock:"+email, loginKeyPrefix+"fail:"+email).Err()
+}
+
+// window drops entries older than the window from the sorted set at key and
+// returns how many are left, with the times of the oldest and the newest.
+func (l *RedisLoginLimiter) window(ctx context.Context, key string, now time.Time, window time.Duration) (int64, time.Time, time.Time, error) {
+	pipe := l.client.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", fmt.Sprint(now.Add(-window).UnixMilli()))
+	count := pipe.ZCard(ctx, key)
+	oldest := pipe.ZRangeWithScores(ctx, key, 0, 0)
+	newest := pipe.ZRangeWithScores(ctx, key, -1, -1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, time.Time{}, time.Time{}, err
+	}
+	return count.Val(), scoreTime(oldest.Val(), now), scoreTime(newest.Val(), now), nil
+}
+
+func (l *RedisLoginLimiter) add(ctx context.Context, key string, now time.Time, window time.Duration) error {
+	pipe := l.client.TxPipeline()
+	// Members must be unique, two attempts may share a millisecond
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixMilli()), Member: uuid.NewString()})
+	pipe.PExpire(ctx, key, window)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func scoreTime(entries []redis.Z, fallback time.Time) time.Time {
+	if len(entries) == 0 {
+		return fallback
+	}
+	return time.UnixMilli(int64(entries[0].Score))
+}
+
+// retryAfter rounds up to whole seconds, the precision of Retry-After.
+func retryAfter(until, now time.Time) time.Duration {
+	return max(until.Sub(now).Truncate(time.Second)+time.Second, time.Second)
+}
+
+func loginEmailKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -12,44 +12,106 @@ import (
 )
 
 type TokenMaker interface {
-	CreateToken(userID uuid.UUID, username string, roles []string, duration time.Duration) (string, error)
+	// CreateToken also returns the claims, whose ID (jti) identifies the
+	// token for revocation. storeID is the user's own store, storeRoles holds
+	// the roles in other stores.
+	CreateToken(userID uuid.UUID, username string, roles []string, storeRoles map[uuid.UUID][]string, storeID *uuid.UUID, duration time.Duration) (string, *domain.JwtCustomClaims, error)
+	// CreateDeviceToken scopes the token to a POS device and its store.
+	CreateDeviceToken(userID uuid.UUID, username string, roles []string, deviceID, storeID uuid.UUID, duration time.Duration) (string, *domain.JwtCustomClaims, error)
 	VerifyToken(token string) (*domain.JwtCustomClaims, error)
+	// JWKS returns the public keys of every known key, for verifying tokens
+	// offline.
+	JWKS() JSONWebKeySet
 }
 
+// JWTMaker signs with one active key and verifies with any key of the set,
+// picked by the kid header. To rotate, add the new key and make it active;
+// keep the old one until the tokens it signed have expired.
 type JWTMaker struct {
-	secretKey string
+	active *SigningKey
+	keys   map[string]*SigningKey
+	order  []string // kid order for the JWKS
 }
 
-func NewJWTMaker(secretKey string) (TokenMaker, error) {
-	if len(secretKey) < 32 {
-		return nil, fmt.Errorf("invalid key size: must be at least 32 characters")
+// NewJWTMaker signs with the key activeKeyID, or the last key when it is
+// empty.
+func NewJWTMaker(keys []*SigningKey, activeKeyID string) (TokenMaker, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing keys")
 	}
-	return &JWTMaker{secretKey}, nil
+	if activeKeyID == "" {
+		activeKeyID = keys[len(keys)-1].ID
+	}
+
+	maker := &JWTMaker{keys: make(map[string]*SigningKey, len(keys))}
+	for _, key := range keys {
+		if _, ok := maker.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %s", key.ID)
+		}
+		maker.keys[key.ID] = key
+		maker.order = append(maker.order, key.ID)
+	}
+	maker.active = maker.keys[activeKeyID]
+	if maker.active == nil {
+		return nil, fmt.Errorf("active key %s not found", activeKeyID)
+	}
+	if maker.active.PrivateKey == nil {
+		return nil, fmt.Errorf("active key %s has no private key", activeKeyID)
+	}
+	return maker, nil
 }
 
-func (maker *JWTMaker) CreateToken(userID uuid.UUID, username string, roles []string, duration time.Duration) (string, error) {
+func (maker *JWTMaker) CreateToken(userID uuid.UUID, username string, roles []string, storeRoles map[uuid.UUID][]string, storeID *uuid.UUID, duration time.Duration) (string, *domain.JwtCustomClaims, error) {
+	claims := &domain.JwtCustomClaims{
+		UserID:     userID,
+		Username:   username,
+		Roles:      roles,
+		StoreRoles: storeRoles,
+		StoreID:    storeID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        uuid.New().String(),
+		},
+	}
+	return maker.sign(claims)
+}
+
+func (maker *JWTMaker) CreateDeviceToken(userID uuid.UUID, username string, roles []string, deviceID, storeID uuid.UUID, duration time.Duration) (string, *domain.JwtCustomClaims, error) {
 	claims := &domain.JwtCustomClaims{
 		UserID:   userID,
 		Username: username,
 		Roles:    roles,
+		DeviceID: &deviceID,
+		StoreID:  &storeID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ID:        uuid.New().String(),
 		},
 	}
+	return maker.sign(claims)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(maker.secretKey))
+func (maker *JWTMaker) sign(claims *domain.JwtCustomClaims) (string, *domain.JwtCustomClaims, error) {
+	token := jwt.NewWithClaims(maker.active.Method, claims)
+	token.Header["kid"] = maker.active.ID
+	signed, err := token.SignedString(maker.active.PrivateKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
 }
 
 func (maker *JWTMaker) VerifyToken(tokenString string) (*domain.JwtCustomClaims, error) {
 	keyFunc := func(token *jwt.Token) (interface{}, error) {
-		_, ok := token.Method.(*jwt.SigningMethodHMAC)
-		if !ok {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := maker.keys[kid]
+		// The algorithm must be the one of the key, never what the token claims
+		if !ok || token.Method.Alg() != key.Method.Alg() {
 			return nil, ErrInvalidToken
 		}
-		return []byte(maker.secretKey), nil
+		return key.PublicKey, nil
 	}
 
 	token, err := jwt.ParseWithClaims(tokenString, &domain.JwtCustomClaims{}, keyFunc)
@@ -65,4 +127,12 @@ func (maker *JWTMaker) VerifyToken(tokenString string) (*domain.JwtCustomClaims,
 	return claims, nil
 }
 
+func (maker *JWTMaker) JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(maker.order))}
+	for _, kid := range maker.order {
+		set.Keys = append(set.Keys, maker.keys[kid].JWK())
+	}
+	return set
+}
+
 var ErrInvalidToken = errors.New("token is invalid")
@@ -0,0 +1,118 @@
+package util
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// The SHA-1 seed of RFC 6238 appendix B, "12345678901234567890".
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func fixedTOTP(unix int64) *TOTP {
+	return &TOTP{Issuer: "POS", Skew: 1, Now: func() time.Time { return time.Unix(unix, 0) }}
+}
+
+// The RFC lists 8 digit codes; with 6 digits they keep their last six.
+func TestTOTPCodeRFC6238(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string // RFC value
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, tt := range tests {
+		totp := fixedTOTP(tt.unix)
+		got, err := totp.Code(rfcSecret, totp.Step())
+		if err != nil {
+			t.Fatalf("Code at %d: %v", tt.unix, err)
+		}
+		if want := tt.code[2:]; got != want {
+			t.Errorf("Code at %d = %s, want %s", tt.unix, got, want)
+		}
+	}
+}
+
+func TestTOTPCodeInvalidSecret(t *testing.T) {
+	if _, err := fixedTOTP(59).Code("not base32!", 1); err == nil {
+		t.Fatal("Code accepted an invalid secret")
+	}
+}
+
+func TestTOTPValidateSkew(t *testing.T) {
+	totp := fixedTOTP(1111111111)
+	now := totp.Step()
+
+	for offset := int64(-2); offset <= 2; offset++ {
+		code, err := totp.Code(rfcSecret, now+offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		step, ok := totp.Validate(rfcSecret, code, 0)
+		wantOK := offset >= -totp.Skew && offset <= totp.Skew
+		if ok != wantOK {
+			t.Errorf("offset %d: ok = %v, want %v", offset, ok, wantOK)
+		}
+		if ok && step != now+offset {
+			t.Errorf("offset %d: step = %d, want %d", offset, step, now+offset)
+		}
+	}
+}
+
+func TestTOTPValidateReplay(t *testing.T) {
+	totp := fixedTOTP(1111111111)
+	code, err := totp.Code(rfcSecret, totp.Step())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	step, ok := totp.Validate(rfcSecret, code, 0)
+	if !ok {
+		t.Fatal("first use of the code was refused")
+	}
+	if _, ok := totp.Validate(rfcSecret, code, step); ok {
+		t.Error("code accepted again after its step was used")
+	}
+
+	// A later step stays valid after an earlier one was used
+	next, err := totp.Code(rfcSecret, totp.Step()+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := totp.Validate(rfcSecret, next, step); !ok {
+		t.Error("code of the next step refused")
+	}
+	// An earlier step is refused once a later one was used
+	prev, err := totp.Code(rfcSecret, totp.Step()-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := totp.Validate(rfcSecret, prev, step); ok {
+		t.Error("code of an earlier step accepted")
+	}
+}
+
+func TestTOTPValidateFormat(t *testing.T) {
+	totp := fixedTOTP(59)
+	if _, ok := totp.Validate(rfcSecret, "287 082", 0); !ok {
+		t.Error("code with a space refused")
+	}
+	for _, code := range []string{"", "28708", "2870820", "94287082"} {
+		if _, ok := totp.Validate(rfcSecret, code, 0); ok {
+			t.Errorf("Validate(%q) accepted", code)
+		}
+	}
+}
+
+func TestTOTPURL(t *testing.T) {
+	got := fixedTOTP(0).URL("JBSWY3DPEHPK3PXP", "owner@example.com")
+	want := "otpauth://totp/POS:owner@example.com?algorithm=SHA1&digits=6&issuer=POS&period=30&secret=JBSWY3DPEHPK3PXP"
+	if got != want {
+		t.Errorf("URL = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,137 @@
+// Package worker runs periodic background jobs on exactly one replica.
+//
+// Replicas campaign for a Postgres session-level advisory lock; the holder
+// runs every registered job on its own ticker. The lock lives on a dedicated
+// pooled connection, so it is released as soon as the leader stops or loses
+// its database connection, and another replica takes over.
+package worker
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"pos-api/internal/domain"
+	"pos-api/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Func is one run of a job. The context is cancelled when leadership is lost.
+type Func func(ctx context.Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	fn       Func
+}
+
+type Runner struct {
+	pool    *pgxpool.Pool
+	lockKey string
+	// retry is how often followers try to take over, and how often the leader
+	// checks that its lock connection is still alive
+	retry time.Duration
+	jobs  []job
+}
+
+func NewRunner(pool *pgxpool.Pool, lockKey string, retry time.Duration) *Runner {
+	return &Runner{
+		pool:    pool,
+		lockKey: lockKey,
+		retry:   retry,
+	}
+}
+
+// Register adds a job. It must be called before Run.
+func (r *Runner) Register(name string, interval time.Duration, fn Func) {
+	r.jobs = append(r.jobs, job{name: name, interval: interval, fn: fn})
+}
+
+// Run blocks until ctx is cancelled, leading whenever it holds the lock.
+func (r *Runner) Run(ctx context.Context) {
+	for {
+		conn, err := r.acquire(ctx)
+		if err != nil {
+			log.Println("job runner:", err)
+		}
+		if conn != nil {
+			log.Println("job runner: elected leader")
+			r.lead(ctx, conn)
+			log.Println("job runner: stepped down")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.retry):
+		}
+	}
+}
+
+// acquire returns the connection holding the lock, or nil when another
+// replica is the leader.
+func (r *Runner) acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := repository.New(conn).TryJobLock(ctx, r.lockKey)
+	if err != nil || !ok {
+		conn.Release()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (r *Runner) lead(ctx context.Context, conn *pgxpool.Conn) {
+	leaderCtx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	for _, j := range r.jobs {
+		wg.Add(1)
+		go func(j job) {
+			defer wg.Done()
+			r.loop(leaderCtx, j)
+		}(j)
+	}
+
+	// Hold the lock until shutdown or until the connection breaks
+	healthy := true
+	ticker := time.NewTicker(r.retry)
+	for healthy {
+		select {
+		case <-ctx.Done():
+			healthy = false
+		case <-ticker.C:
+			if err := conn.Ping(ctx); err != nil {
+				log.Println("job runner: lost lock connection:", err)
+				healthy = false
+			}
+		}
+	}
+	ticker.Stop()
+	cancel()
+	wg.Wait()
+
+	// Closing the connection releases the lock; it is not returned to the pool
+	_ = conn.Hijack().Close(context.Background())
+}
+
+func (r *Runner) loop(ctx context.Context, j job) {
+	// Jobs work for every store
+	jobCtx := domain.WithUnscopedAccess(ctx)
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := j.fn(jobCtx); err != nil && ctx.Err() == nil {
+				log.Println(j.name+":", err)
+			}
+		}
+	}
+}
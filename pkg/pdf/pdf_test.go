@@ -0,0 +1,150 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDocumentParses(t *testing.T) {
+	doc := New(A4Width, A4Height)
+	first := doc.AddPage()
+	first.FillRect(10, 20, 30, 40)
+	first.StrokeRect(0, 0, 100, 50.5)
+	first.Text(72, 100, 18, "Meja (1)")
+	second := doc.AddPage()
+	second.Text(72, 100, 11, "Teras")
+
+	pdf := doc.Bytes()
+	objects := parse(t, pdf)
+
+	if !strings.Contains(objects[1], "/Type /Catalog") || !strings.Contains(objects[1], "/Pages 2 0 R") {
+		t.Errorf("object 1 is not the catalog: %s", objects[1])
+	}
+	if !strings.Contains(objects[2], "/Kids [4 0 R 6 0 R] /Count 2") {
+		t.Errorf("page tree: %s", objects[2])
+	}
+	for _, n := range []int{4, 6} {
+		page := objects[n]
+		if !strings.Contains(page, "/Type /Page ") || !strings.Contains(page, "/MediaBox [0 0 595.28 841.89]") {
+			t.Errorf("object %d is not an A4 page: %s", n, page)
+		}
+		if want := fmt.Sprintf("/Contents %d 0 R", n+1); !strings.Contains(page, want) {
+			t.Errorf("object %d lacks %s", n, want)
+		}
+	}
+
+	content := stream(t, objects[5])
+	for _, op := range []string{
+		"10 781.89 30 40 re f\n", // y flipped to the bottom-left origin
+		"0 791.39 100 50.5 re S", // stroke with a trailing zero dropped
+		"BT /F1 18 Tf 72 741.89 Td (Meja \\(1\\)) Tj ET\n",
+	} {
+		if !strings.Contains(content, op) {
+			t.Errorf("page 1 content lacks %q:\n%s", op, content)
+		}
+	}
+	if content := stream(t, objects[7]); !strings.Contains(content, "(Teras) Tj") {
+		t.Errorf("page 2 content: %s", content)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := map[string]string{
+		"Meja 1":    "Meja 1",
+		`a(b)c\`:    `a\(b\)c\\`,
+		"Café":      `Caf\351`,
+		"Kopi ☕":    "Kopi ?",
+		"tab\there": "tab?here",
+	}
+	for in, want := range tests {
+		if got := escape(in); got != want {
+			t.Errorf("escape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNum(t *testing.T) {
+	tests := map[float64]string{0: "0", 2: "2", 1.5: "1.5", 841.89: "841.89", 10.004: "10", -3.25: "-3.25"}
+	for in, want := range tests {
+		if got := num(in); got != want {
+			t.Errorf("num(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+var startxrefRe = regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`)
+
+// parse follows the trailer and cross-reference table like a reader does and
+// returns the body of every object by number.
+func parse(t *testing.T, pdf []byte) map[int]string {
+	t.Helper()
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) {
+		t.Fatalf("no PDF header: %q", pdf[:min(len(pdf), 16)])
+	}
+	m := startxrefRe.FindSubmatch(pdf)
+	if m == nil {
+		t.Fatal("no startxref at the end of the file")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if xref >= len(pdf) || !bytes.HasPrefix(pdf[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+
+	lines := strings.Split(string(pdf[xref:]), "\n")
+	var first, count int
+	if _, err := fmt.Sscanf(lines[1], "%d %d", &first, &count); err != nil || first != 0 {
+		t.Fatalf("xref subsection header %q", lines[1])
+	}
+	if lines[2] != "0000000000 65535 f " {
+		t.Errorf("xref entry 0 = %q", lines[2])
+	}
+	trailer := strings.Join(lines[2+count:], "\n")
+	if want := fmt.Sprintf("trailer\n<< /Size %d /Root 1 0 R >>", count); !strings.HasPrefix(trailer, want) {
+		t.Errorf("trailer %q, want %q", trailer, want)
+	}
+
+	objects := make(map[int]string, count-1)
+	for n := 1; n < count; n++ {
+		entry := lines[2+n]
+		// Entries are exactly 20 bytes including the end of line
+		if len(entry)+1 != 20 || !strings.HasSuffix(entry, " 00000 n ") {
+			t.Fatalf("xref entry %d = %q", n, entry)
+		}
+		offset, err := strconv.Atoi(entry[:10])
+		if err != nil {
+			t.Fatal(err)
+		}
+		header := fmt.Sprintf("%d 0 obj\n", n)
+		if !bytes.HasPrefix(pdf[offset:], []byte(header)) {
+			t.Fatalf("object %d: offset %d points at %q", n, offset, pdf[offset:min(len(pdf), offset+12)])
+		}
+		body := pdf[offset+len(header):]
+		end := bytes.Index(body, []byte("\nendobj\n"))
+		if end < 0 {
+			t.Fatalf("object %d has no endobj", n)
+		}
+		objects[n] = string(body[:end])
+	}
+	return objects
+}
+
+var lengthRe = regexp.MustCompile(`^<< /Length (\d+) >>\nstream\n`)
+
+// stream returns the data of a stream object, checking its /Length.
+func stream(t *testing.T, obj string) string {
+	t.Helper()
+	m := lengthRe.FindStringSubmatch(obj)
+	if m == nil {
+		t.Fatalf("not a stream: %q", obj)
+	}
+	length, _ := strconv.Atoi(m[1])
+	data := obj[len(m[0]):]
+	if !strings.HasSuffix(data, "endstream") || len(data)-len("endstream") != length {
+		t.Fatalf("stream is %d bytes, /Length says %d", len(data)-len("endstream"), length)
+	}
+	return data[:length]
+}
@@ -0,0 +1,387 @@
+package qrcode
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// Format information of every level and mask, ISO/IEC 18004 Annex C.
+var formatStrings = map[Level][8]string{
+	L: {"111011111000100", "111001011110011", "111110110101010", "111100010011101", "110011000101111", "110001100011000", "110110001000001", "110100101110110"},
+	M: {"101010000010010", "101000100100101", "101111001111100", "101101101001011", "100010111111001", "100000011001110", "100111110010111", "100101010100000"},
+	Q: {"011010101011111", "011000001101000", "011111100110001", "011101000000110", "010010010110100", "010000110000011", "010111011011010", "010101111101101"},
+	H: {"001011010001001", "001001110111110", "001110011100111", "001100111010000", "000011101100010", "000001001010101", "000110100001100", "000100000111011"},
+}
+
+// Version information, ISO/IEC 18004 Annex D.
+var versionStrings = map[int]string{
+	7:  "000111110010010100",
+	8:  "001000010110111100",
+	9:  "001001101010011001",
+	10: "001010010011010011",
+}
+
+// Total codewords per version, ISO/IEC 18004 table 1.
+var totalCodewords = [11]int{1: 26, 2: 44, 3: 70, 4: 100, 5: 134, 6: 172, 7: 196, 8: 242, 9: 292, 10: 346}
+
+// Byte mode capacity per version and level, ISO/IEC 18004 table 7.
+var byteCapacity = [11][4]int{
+	1:  {17, 14, 11, 7},
+	2:  {32, 26, 20, 14},
+	3:  {53, 42, 32, 24},
+	4:  {78, 62, 46, 34},
+	5:  {106, 84, 60, 44},
+	6:  {134, 106, 74, 58},
+	7:  {154, 122, 86, 64},
+	8:  {192, 152, 108, 84},
+	9:  {230, 180, 130, 98},
+	10: {271, 213, 151, 119},
+}
+
+func TestBlockSpecsMatchTotals(t *testing.T) {
+	for v := 1; v <= maxVersion; v++ {
+		for level := L; level <= H; level++ {
+			s := blockSpecs[v][level]
+			total := s.g1Blocks*(s.g1DC+s.ecPerBlock) + s.g2Blocks*(s.g2DC+s.ecPerBlock)
+			if total != totalCodewords[v] {
+				t.Errorf("version %d level %d: %d codewords, want %d", v, level, total, totalCodewords[v])
+			}
+		}
+	}
+}
+
+func TestEncodeCapacity(t *testing.T) {
+	for v := 1; v <= maxVersion; v++ {
+		for level := L; level <= H; level++ {
+			n := byteCapacity[v][level]
+			c, err := Encode(strings.Repeat("a", n), level)
+			if err != nil {
+				t.Fatalf("%d bytes at level %d: %v", n, level, err)
+			}
+			if c.Version != v {
+				t.Errorf("%d bytes at level %d: version %d, want %d", n, level, c.Version, v)
+			}
+
+			c, err = Encode(strings.Repeat("a", n+1), level)
+			if v == maxVersion {
+				if !errors.Is(err, ErrTooLong) {
+					t.Errorf("%d bytes at level %d: err = %v, want ErrTooLong", n+1, level, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Version != v+1 {
+				t.Errorf("%d bytes at level %d: version %d, want %d", n+1, level, c.Version, v+1)
+			}
+		}
+	}
+}
+
+func TestEncodeDecodes(t *testing.T) {
+	contents := []string{
+		"",
+		"01234567",
+		"http://localhost:3000/order/6f1c2b0e-8a51-4d0c-9a3e-1f2d3c4b5a69/T-ab12cd34",
+		"otpauth://totp/POS:owner@example.com?algorithm=SHA1&digits=6&issuer=POS&period=30&secret=JBSWY3DPEHPK3PXP",
+		strings.Repeat("Kopi susu 18.000, ", 6),
+	}
+	for _, content := range contents {
+		for level := L; level <= H; level++ {
+			c, err := Encode(content, level)
+			if err != nil {
+				t.Fatalf("Encode(%q, %d): %v", content, level, err)
+			}
+			got, err := decode(c)
+			if err != nil {
+				t.Fatalf("%q at level %d, version %d: %v", content, level, c.Version, err)
+			}
+			if got != content {
+				t.Errorf("%q at level %d: decoded %q", content, level, got)
+			}
+		}
+	}
+}
+
+func TestEncodeGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		level   Level
+		version int
+	}{
+		{"1-M", "01234567", M, 1},
+		{"4-L", "http://localhost:3000/order/6f1c2b0e-8a51-4d0c-9a3e-1f2d3c4b5a69/T-ab12cd34", L, 4},
+		{"7-Q", "http://localhost:3000/order/6f1c2b0e-8a51-4d0c-9a3e-1f2d3c4b5a69/T-ab12cd34", Q, 7},
+		{"10-H", strings.Repeat("Kopi susu 18.000, ", 6), H, 10},
+	}
+	for _, tt := range tests {
+		c, err := Encode(tt.content, tt.level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Version != tt.version {
+			t.Errorf("%s: version %d, want %d", tt.name, c.Version, tt.version)
+		}
+
+		got := matrixString(c)
+		path := filepath.Join("testdata", tt.name+".txt")
+		if *update {
+			if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != string(want) {
+			t.Errorf("%s: matrix differs from %s:\n%s", tt.name, path, got)
+		}
+	}
+}
+
+func matrixString(c *Code) string {
+	var b strings.Builder
+	for _, row := range c.Modules {
+		for _, dark := range row {
+			if dark {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// decode reads a symbol back the way a scanner does, following the standard
+// rather than the encoder: format and version information, unmasking, the
+// codeword placement, Reed-Solomon syndromes and the byte mode segment.
+func decode(c *Code) (string, error) {
+	size := c.Size
+	if size != c.Version*4+17 {
+		return "", errors.New("size does not match the version")
+	}
+	m := c.Modules
+
+	// Format information, both copies
+	var first, second strings.Builder
+	for _, p := range [][2]int{{8, 0}, {8, 1}, {8, 2}, {8, 3}, {8, 4}, {8, 5}, {8, 7}, {8, 8}, {7, 8}, {5, 8}, {4, 8}, {3, 8}, {2, 8}, {1, 8}, {0, 8}} {
+		first.WriteByte(moduleChar(m[p[1]][p[0]]))
+	}
+	for i := 0; i < 8; i++ {
+		second.WriteByte(moduleChar(m[8][size-1-i]))
+	}
+	for i := 8; i < 15; i++ {
+		second.WriteByte(moduleChar(m[size-15+i][8]))
+	}
+	format := reverse(first.String())
+	if format != reverse(second.String()) {
+		return "", errors.New("format information copies differ")
+	}
+	mask := -1
+	for i, s := range formatStrings[c.Level] {
+		if s == format {
+			mask = i
+		}
+	}
+	if mask < 0 {
+		return "", errors.New("format information " + format + " is not valid for the level")
+	}
+	if !m[size-8][8] {
+		return "", errors.New("dark module is light")
+	}
+
+	// Version information, both copies
+	if c.Version >= 7 {
+		var a, b strings.Builder
+		for i := 17; i >= 0; i-- {
+			a.WriteByte(moduleChar(m[i/3][size-11+i%3]))
+			b.WriteByte(moduleChar(m[size-11+i%3][i/3]))
+		}
+		if a.String() != versionStrings[c.Version] || b.String() != versionStrings[c.Version] {
+			return "", errors.New("version information is wrong")
+		}
+	}
+
+	// Codewords in placement order, unmasked
+	reserved := functionModules(c.Version)
+	var bits []bool
+	upward := true
+	for right := size - 1; right > 0; right -= 2 {
+		if right == 6 {
+			right--
+		}
+		for i := 0; i < size; i++ {
+			y := i
+			if upward {
+				y = size - 1 - i
+			}
+			for _, x := range []int{right, right - 1} {
+				if reserved[y][x] {
+					continue
+				}
+				bits = append(bits, m[y][x] != maskBit(mask, x, y))
+			}
+		}
+		upward = !upward
+	}
+	total := totalCodewords[c.Version]
+	if len(bits) < total*8 {
+		return "", errors.New("data area too small")
+	}
+	codewords := make([]byte, total)
+	for i := range codewords {
+		for j := 0; j < 8; j++ {
+			if bits[i*8+j] {
+				codewords[i] |= 1 << (7 - j)
+			}
+		}
+	}
+
+	// De-interleave and check every block
+	spec := blockSpecs[c.Version][c.Level]
+	blockCount := spec.g1Blocks + spec.g2Blocks
+	blocks := make([][]byte, blockCount)
+	pos := 0
+	for i := 0; i < max(spec.g1DC, spec.g2DC); i++ {
+		for b := range blocks {
+			if i < spec.g1DC || b >= spec.g1Blocks {
+				blocks[b] = append(blocks[b], codewords[pos])
+				pos++
+			}
+		}
+	}
+	var data []byte
+	for _, block := range blocks {
+		data = append(data, block...)
+	}
+	for i := 0; i < spec.ecPerBlock; i++ {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], codewords[pos])
+			pos++
+		}
+	}
+	for _, block := range blocks {
+		for i := 0; i < spec.ecPerBlock; i++ {
+			var s byte
+			for _, cw := range block {
+				s = gfMul(s, gfExp[i]) ^ cw
+			}
+			if s != 0 {
+				return "", errors.New("Reed-Solomon syndrome is not zero")
+			}
+		}
+	}
+
+	// Byte mode segment
+	read := func(pos, n int) int {
+		v := 0
+		for i := pos; i < pos+n; i++ {
+			v <<= 1
+			if data[i/8]&(1<<(7-i%8)) != 0 {
+				v |= 1
+			}
+		}
+		return v
+	}
+	if mode := read(0, 4); mode != 0b0100 {
+		return "", errors.New("not a byte mode segment")
+	}
+	countBits := 8
+	if c.Version >= 10 {
+		countBits = 16
+	}
+	n := read(4, countBits)
+	if 4+countBits+n*8 > len(data)*8 {
+		return "", errors.New("segment longer than the data")
+	}
+	out := make([]byte, n)
+	for i := range out {
+		out[i] = byte(read(4+countBits+i*8, 8))
+	}
+	return string(out), nil
+}
+
+// functionModules marks finder patterns with their separators, timing,
+// alignment patterns, format and version information.
+func functionModules(version int) [][]bool {
+	size := version*4 + 17
+	f := make([][]bool, size)
+	for y := range f {
+		f[y] = make([]bool, size)
+		for x := range f[y] {
+			switch {
+			case x <= 8 && y <= 8, x >= size-8 && y <= 8, x <= 8 && y >= size-8:
+				f[y][x] = true // Finders, separators, format information
+			case x == 6 || y == 6:
+				f[y][x] = true
+			case version >= 7 && x >= size-11 && x < size-8 && y < 6:
+				f[y][x] = true
+			case version >= 7 && y >= size-11 && y < size-8 && x < 6:
+				f[y][x] = true
+			}
+		}
+	}
+	pos := alignmentPositions[version]
+	for _, cy := range pos {
+		for _, cx := range pos {
+			if (cx < 9 && cy < 9) || (cx > size-9 && cy < 9) || (cx < 9 && cy > size-9) {
+				continue // Overlaps a finder
+			}
+			for y := cy - 2; y <= cy+2; y++ {
+				for x := cx - 2; x <= cx+2; x++ {
+					f[y][x] = true
+				}
+			}
+		}
+	}
+	return f
+}
+
+// maskBit is the mask condition of ISO/IEC 18004 table 10, i = row, j = column.
+func maskBit(mask, j, i int) bool {
+	switch mask {
+	case 0:
+		return (i+j)%2 == 0
+	case 1:
+		return i%2 == 0
+	case 2:
+		return j%3 == 0
+	case 3:
+		return (i+j)%3 == 0
+	case 4:
+		return (i/2+j/3)%2 == 0
+	case 5:
+		return (i*j)%2+(i*j)%3 == 0
+	case 6:
+		return ((i*j)%2+(i*j)%3)%2 == 0
+	default:
+		return ((i+j)%2+(i*j)%3)%2 == 0
+	}
+}
+
+func moduleChar(dark bool) byte {
+	if dark {
+		return '1'
+	}
+	return '0'
+}
+
+func reverse(s string) string {
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
@@ -0,0 +1,56 @@
+package qrcode
+
+import (
+	"bytes"
+	"testing"
+)
+
+// The generator for 10 EC codewords as powers of alpha, ISO/IEC 18004 Annex A.
+func TestRSGenerator(t *testing.T) {
+	exponents := []int{251, 67, 46, 61, 118, 70, 64, 94, 32, 45}
+	gen := rsGenerator(10)
+	for i, e := range exponents {
+		if gen[i] != gfExp[e] {
+			t.Errorf("coefficient %d = %d, want alpha^%d = %d", i+1, gen[i], e, gfExp[e])
+		}
+	}
+}
+
+func TestRSRemainder(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		ec   []byte
+	}{
+		{
+			// ISO/IEC 18004 Annex I: "01234567" as 1-M in numeric mode
+			name: "01234567 1-M",
+			data: []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11},
+			ec:   []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55},
+		},
+		{
+			// "HELLO WORLD" as 1-M in alphanumeric mode
+			name: "HELLO WORLD 1-M",
+			data: []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17},
+			ec:   []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23},
+		},
+	}
+	for _, tt := range tests {
+		got := rsRemainder(tt.data, rsGenerator(len(tt.ec)))
+		if !bytes.Equal(got, tt.ec) {
+			t.Errorf("%s: EC codewords = % X, want % X", tt.name, got, tt.ec)
+		}
+	}
+}
+
+func TestGFTables(t *testing.T) {
+	for i := 1; i < 256; i++ {
+		if gfExp[gfLog[i]] != byte(i) {
+			t.Fatalf("exp(log(%d)) = %d", i, gfExp[gfLog[i]])
+		}
+	}
+	// x^8 = x^4 + x^3 + x^2 + 1
+	if gfExp[8] != 0x1D {
+		t.Errorf("alpha^8 = %#x, want 0x1d", gfExp[8])
+	}
+}
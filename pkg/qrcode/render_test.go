@@ -0,0 +1,67 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestPNG(t *testing.T) {
+	c, err := Encode("01234567", M)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const scale = 3
+	data, err := c.PNG(scale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dim := (c.Size + 2*QuietZone) * scale
+	if b := img.Bounds(); b.Dx() != dim || b.Dy() != dim {
+		t.Fatalf("image is %dx%d, want %dx%d", b.Dx(), b.Dy(), dim, dim)
+	}
+	for y := -QuietZone; y < c.Size+QuietZone; y++ {
+		for x := -QuietZone; x < c.Size+QuietZone; x++ {
+			dark := x >= 0 && y >= 0 && x < c.Size && y < c.Size && c.Modules[y][x]
+			r, _, _, _ := img.At((x+QuietZone)*scale+scale-1, (y+QuietZone)*scale).RGBA()
+			if (r == 0) != dark {
+				t.Fatalf("module %d,%d: dark = %v in the image", x, y, r == 0)
+			}
+		}
+	}
+}
+
+func TestSVG(t *testing.T) {
+	c, err := Encode("01234567", M)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svg := c.SVG(200)
+
+	dim := c.Size + 2*QuietZone
+	if want := fmt.Sprintf(`width="200" height="200" viewBox="0 0 %d %d"`, dim, dim); !strings.Contains(svg, want) {
+		t.Errorf("SVG lacks %s", want)
+	}
+	dark := 0
+	for _, row := range c.Modules {
+		for _, m := range row {
+			if m {
+				dark++
+			}
+		}
+	}
+	if got := strings.Count(svg, "h1v1h-1z"); got != dark {
+		t.Errorf("SVG draws %d modules, want %d", got, dark)
+	}
+	// The top-left module of the finder pattern
+	if !strings.Contains(svg, fmt.Sprintf("M%d %dh1v1h-1z", QuietZone, QuietZone)) {
+		t.Error("SVG lacks the first finder module")
+	}
+}